@@ -0,0 +1,49 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+)
+
+// Единая таблица соответствия доменных ошибок кодам ответа.
+// Порядок важен: более конкретные ошибки идут раньше общих.
+var errorMapping = []struct {
+	err    error
+	code   ErrorResponseErrorCode
+	status int
+}{
+	{domain.ErrTeamExists, TEAMEXISTS, http.StatusBadRequest},
+	{domain.ErrPRExists, PREXISTS, http.StatusConflict},
+	{domain.ErrPRMerged, PRMERGED, http.StatusConflict},
+	{domain.ErrInvalidState, INVALIDSTATE, http.StatusConflict},
+	{domain.ErrNotAssigned, NOTASSIGNED, http.StatusConflict},
+	{domain.ErrNotApproved, NOTAPPROVED, http.StatusConflict},
+	{domain.ErrNoCandidate, NOCANDIDATE, http.StatusConflict},
+	// Конфликт уникальности, который сервис не перевёл в более конкретную ошибку
+	{domain.ErrAlreadyExists, ALREADYEXISTS, http.StatusConflict},
+	{domain.ErrNotFound, NOTFOUND, http.StatusNotFound},
+	{domain.ErrInvalidInput, BADREQUEST, http.StatusBadRequest},
+	{domain.ErrUnauthorized, UNAUTHORIZED, http.StatusUnauthorized},
+}
+
+// errorCode возвращает код, статус и публичное сообщение ошибки. Сообщение берётся
+// из доменной ошибки, а не из цепочки: обёртки могут содержать детали хранилища.
+func errorCode(err error) (ErrorResponseErrorCode, int, string) {
+	for _, m := range errorMapping {
+		if errors.Is(err, m.err) {
+			return m.code, m.status, m.err.Error()
+		}
+	}
+	return INTERNAL, http.StatusInternalServerError, "internal error"
+}
+
+// writeDomainError отвечает ошибкой с кодом и статусом по таблице errorMapping,
+// полный текст ошибки уходит только в лог.
+func (h *ApiHandler) writeDomainError(w http.ResponseWriter, err error) {
+	code, status, message := errorCode(err)
+	log.Printf("Request failed with %s: %v", code, err)
+	h.writeError(w, code, message, status)
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/humooo/avito-backend-trainee-2025/internal/models"
 	"github.com/humooo/avito-backend-trainee-2025/internal/service"
@@ -12,6 +13,17 @@ type ApiHandler struct {
 	PRService   *service.PRService
 	UserService *service.UserService
 	TeamService *service.TeamService
+
+	AbsenceService *service.AbsenceService
+	WebhookService *service.WebhookService
+	EventService   *service.EventService
+	EventStream    *service.EventStream
+
+	NotificationService *service.NotificationService
+
+	// Секреты входящих вебхуков; пока секрет не задан, вебхуки платформы отклоняются
+	GitHubWebhookSecret string
+	GitLabWebhookSecret string
 }
 
 // Хелпер для отправки ошибок в формате generated ErrorResponse
@@ -28,26 +40,23 @@ func (h *ApiHandler) writeError(w http.ResponseWriter, code ErrorResponseErrorCo
 func (h *ApiHandler) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 	var body PostTeamAddJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		h.writeError(w, NOTFOUND, "invalid json: "+err.Error(), http.StatusBadRequest)
+		h.writeError(w, BADREQUEST, "invalid json: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	var members []models.User
 	for _, m := range body.Members {
 		members = append(members, models.User{
-			ID:       m.UserId,
-			Name:     m.Username,
-			IsActive: m.IsActive,
+			ID:             m.UserId,
+			Name:           m.Username,
+			IsActive:       m.IsActive,
+			MaxOpenReviews: m.MaxOpenReviews,
 		})
 	}
 
 	team, err := h.TeamService.Create(r.Context(), body.TeamName, members)
 	if err != nil {
-		if err.Error() == "team exists" {
-			h.writeError(w, TEAMEXISTS, "team already exists", http.StatusBadRequest)
-			return
-		}
-		h.writeError(w, NOTFOUND, err.Error(), http.StatusInternalServerError)
+		h.writeDomainError(w, err)
 		return
 	}
 
@@ -63,7 +72,7 @@ func (h *ApiHandler) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 func (h *ApiHandler) GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams) {
 	team, users, err := h.TeamService.GetByName(r.Context(), params.TeamName)
 	if err != nil {
-		h.writeError(w, NOTFOUND, "team not found", http.StatusNotFound)
+		h.writeDomainError(w, err)
 		return
 	}
 
@@ -72,69 +81,530 @@ func (h *ApiHandler) GetTeamGet(w http.ResponseWriter, r *http.Request, params G
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+func (h *ApiHandler) GetTeamSettings(w http.ResponseWriter, r *http.Request, params GetTeamSettingsParams) {
+	settings, err := h.TeamService.Settings(r.Context(), params.TeamName)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(mapTeamSettings(settings))
+}
+
+func (h *ApiHandler) PostTeamSettings(w http.ResponseWriter, r *http.Request) {
+	var body PostTeamSettingsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.TeamService.Settings(r.Context(), body.TeamName)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	if body.ReviewerCount != nil {
+		settings.ReviewerCount = *body.ReviewerCount
+	}
+	if body.MinApprovals != nil {
+		settings.MinApprovals = *body.MinApprovals
+	}
+	if body.LeadUserId != nil {
+		settings.LeadUserID = *body.LeadUserId
+	}
+	if body.RequireLead != nil {
+		settings.RequireLead = *body.RequireLead
+	}
+	if body.Strategy != nil {
+		settings.Strategy = *body.Strategy
+	}
+	if body.FallbackTeams != nil {
+		settings.FallbackTeams = *body.FallbackTeams
+	}
+	if body.ReviewSlaMinutes != nil {
+		settings.ReviewSLA = time.Duration(*body.ReviewSlaMinutes) * time.Minute
+	}
+	if body.SlaAction != nil {
+		settings.SLAAction = *body.SlaAction
+	}
+	if body.ChatChannel != nil {
+		settings.ChatChannel = *body.ChatChannel
+	}
+
+	if err := h.TeamService.UpdateSettings(r.Context(), settings); err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(mapTeamSettings(settings))
+}
+
+func (h *ApiHandler) GetTeamOverdueReviews(w http.ResponseWriter, r *http.Request, params GetTeamOverdueReviewsParams) {
+	overdue, err := h.PRService.OverdueReviews(r.Context(), params.TeamName, time.Now())
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	settings, err := h.TeamService.Settings(r.Context(), params.TeamName)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	response := struct {
+		TeamName         string          `json:"team_name"`
+		ReviewSlaMinutes int             `json:"review_sla_minutes"`
+		Reviews          []OverdueReview `json:"reviews"`
+	}{
+		TeamName:         params.TeamName,
+		ReviewSlaMinutes: int(settings.ReviewSLA / time.Minute),
+		Reviews:          make([]OverdueReview, len(overdue)),
+	}
+	for i, o := range overdue {
+		response.Reviews[i] = mapOverdueReview(o)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (h *ApiHandler) PostTeamDeactivateUsers(w http.ResponseWriter, r *http.Request, params PostTeamDeactivateUsersParams) {
+	var body PostTeamDeactivateUsersJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	var teamName string
+	if body.TeamName != nil {
+		teamName = *body.TeamName
+	}
+	var userIDs []string
+	if body.UserIds != nil {
+		userIDs = *body.UserIds
+	}
+
+	res, err := h.PRService.DeactivateUsers(r.Context(), teamName, userIDs, actorFrom(params.XActor))
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	reassignments := mapReviewerChanges(res.Changes)
+
+	response := struct {
+		DeactivatedUserIds []string               `json:"deactivated_user_ids"`
+		Reassignments      []ReviewerReassignment `json:"reassignments"`
+	}{
+		DeactivatedUserIds: res.Deactivated,
+		Reassignments:      reassignments,
+	}
+	if response.DeactivatedUserIds == nil {
+		response.DeactivatedUserIds = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (h *ApiHandler) PostTeamAddMembers(w http.ResponseWriter, r *http.Request) {
+	var body PostTeamAddMembersJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	members := make([]models.User, len(body.Members))
+	for i, m := range body.Members {
+		members[i] = models.User{
+			ID:             m.UserId,
+			Name:           m.Username,
+			IsActive:       m.IsActive,
+			MaxOpenReviews: m.MaxOpenReviews,
+		}
+	}
+
+	team, users, err := h.TeamService.AddMembers(r.Context(), body.TeamName, members)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]Team{"team": mapTeamToResponse(team, users)})
+}
+
+func (h *ApiHandler) PostTeamRemoveMembers(w http.ResponseWriter, r *http.Request, params PostTeamRemoveMembersParams) {
+	var body PostTeamRemoveMembersJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.PRService.RemoveTeamMembers(r.Context(), body.TeamName, body.UserIds, actorFrom(params.XActor))
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	h.writeDetached(w, res)
+}
+
+func (h *ApiHandler) PostTeamRename(w http.ResponseWriter, r *http.Request) {
+	var body PostTeamRenameJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	team, users, err := h.TeamService.Rename(r.Context(), body.TeamName, body.NewTeamName)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]Team{"team": mapTeamToResponse(team, users)})
+}
+
+func (h *ApiHandler) PostTeamDelete(w http.ResponseWriter, r *http.Request, params PostTeamDeleteParams) {
+	var body PostTeamDeleteJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.PRService.DeleteTeam(r.Context(), body.TeamName, actorFrom(params.XActor))
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	h.writeDetached(w, res)
+}
+
+// Хелпер для removeMembers/delete: кто остался без команды и что стало с их ревью
+func (h *ApiHandler) writeDetached(w http.ResponseWriter, res *models.DeactivationResult) {
+	response := struct {
+		RemovedUserIds []string               `json:"removed_user_ids"`
+		Reassignments  []ReviewerReassignment `json:"reassignments"`
+	}{
+		RemovedUserIds: res.Deactivated,
+		Reassignments:  mapReviewerChanges(res.Changes),
+	}
+	if response.RemovedUserIds == nil {
+		response.RemovedUserIds = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
 func (h *ApiHandler) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 	var body PostUsersSetIsActiveJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		h.writeError(w, NOTFOUND, "invalid json", http.StatusBadRequest)
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
 		return
 	}
 
 	err := h.UserService.SetIsActive(r.Context(), body.UserId, body.IsActive)
 	if err != nil {
-		h.writeError(w, NOTFOUND, "user not found", http.StatusNotFound)
+		h.writeDomainError(w, err)
 		return
 	}
 
-	u, _ := h.UserService.GetByID(r.Context(), body.UserId)
-
-	resp := User{
-		UserId:   u.ID,
-		Username: u.Name,
-		TeamName: u.TeamName,
-		IsActive: u.IsActive,
+	u, err := h.UserService.GetByID(r.Context(), body.UserId)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
 	}
 
+	resp := mapUserToResponse(u)
+
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]User{"user": resp})
 }
 
-func (h *ApiHandler) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+func (h *ApiHandler) PostUsersMoveTeam(w http.ResponseWriter, r *http.Request, params PostUsersMoveTeamParams) {
+	var body PostUsersMoveTeamJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	handover := body.HandoverReviews == nil || *body.HandoverReviews
+	move, changes, err := h.PRService.MoveUser(r.Context(), body.UserId, body.TeamName, handover, actorFrom(params.XActor))
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	u, err := h.UserService.GetByID(r.Context(), body.UserId)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	response := struct {
+		User          User                   `json:"user"`
+		Move          TeamMove               `json:"move"`
+		Reassignments []ReviewerReassignment `json:"reassignments"`
+	}{
+		User:          mapUserToResponse(u),
+		Move:          mapTeamMove(*move),
+		Reassignments: mapReviewerChanges(changes),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (h *ApiHandler) GetUsersTeamMoves(w http.ResponseWriter, r *http.Request, params GetUsersTeamMovesParams) {
+	moves, err := h.UserService.Moves(r.Context(), params.UserId)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	response := struct {
+		UserId string     `json:"user_id"`
+		Moves  []TeamMove `json:"moves"`
+	}{
+		UserId: params.UserId,
+		Moves:  make([]TeamMove, len(moves)),
+	}
+	for i, m := range moves {
+		response.Moves[i] = mapTeamMove(m)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (h *ApiHandler) PostUsersSetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	var body PostUsersSetWorkingHoursJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	var start, end string
+	if body.Start != nil {
+		start = *body.Start
+	}
+	if body.End != nil {
+		end = *body.End
+	}
+	if err := h.UserService.SetWorkingHours(r.Context(), body.UserId, body.TimeZone, start, end); err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	u, err := h.UserService.GetByID(r.Context(), body.UserId)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]User{"user": mapUserToResponse(u)})
+}
+
+func (h *ApiHandler) GetUsersNotificationPreferences(w http.ResponseWriter, r *http.Request, params GetUsersNotificationPreferencesParams) {
+	prefs, err := h.NotificationService.Prefs(r.Context(), params.UserId)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(mapNotificationPrefs(prefs))
+}
+
+func (h *ApiHandler) PostUsersSetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	var body PostUsersSetNotificationPreferencesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	prefs := &models.NotificationPrefs{UserID: body.UserId, Mode: body.Mode}
+	if body.Channel != nil {
+		prefs.Channel = *body.Channel
+	}
+	if err := h.NotificationService.SetPrefs(r.Context(), prefs); err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(mapNotificationPrefs(prefs))
+}
+
+func (h *ApiHandler) PostUsersLinkIdentity(w http.ResponseWriter, r *http.Request) {
+	var body PostUsersLinkIdentityJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.WebhookService.LinkIdentity(r.Context(), string(body.Platform), body.Login, body.UserId); err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"user_id":  body.UserId,
+		"platform": string(body.Platform),
+		"login":    body.Login,
+	})
+}
+
+func (h *ApiHandler) PostUsersAddAbsence(w http.ResponseWriter, r *http.Request) {
+	var body PostUsersAddAbsenceJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	a := &models.Absence{UserID: body.UserId, StartsAt: body.StartsAt, EndsAt: body.EndsAt}
+	if body.Reason != nil {
+		a.Reason = *body.Reason
+	}
+	if err := h.AbsenceService.Add(r.Context(), a); err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]Absence{"absence": mapAbsence(*a)})
+}
+
+func (h *ApiHandler) GetUsersAbsences(w http.ResponseWriter, r *http.Request, params GetUsersAbsencesParams) {
+	absences, err := h.AbsenceService.List(r.Context(), params.UserId)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	response := struct {
+		UserId   string    `json:"user_id"`
+		Absences []Absence `json:"absences"`
+	}{
+		UserId:   params.UserId,
+		Absences: make([]Absence, len(absences)),
+	}
+	for i, a := range absences {
+		response.Absences[i] = mapAbsence(a)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (h *ApiHandler) PostUsersDeleteAbsence(w http.ResponseWriter, r *http.Request) {
+	var body PostUsersDeleteAbsenceJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.AbsenceService.Delete(r.Context(), body.AbsenceId); err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ApiHandler) PostPullRequestCreate(w http.ResponseWriter, r *http.Request, params PostPullRequestCreateParams) {
 	var body PostPullRequestCreateJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		h.writeError(w, NOTFOUND, "invalid json", http.StatusBadRequest)
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
 		return
 	}
 
-	pr, err := h.PRService.Create(r.Context(), body.PullRequestId, body.PullRequestName, body.AuthorId)
+	draft := body.Draft != nil && *body.Draft
+	pr, err := h.PRService.Create(r.Context(), body.PullRequestId, body.PullRequestName, body.AuthorId, draft, actorFrom(params.XActor))
 	if err != nil {
-		if err.Error() == "pr exists" {
-			h.writeError(w, PREXISTS, "pr id already exists", http.StatusConflict)
-			return
-		}
-		if err.Error() == "author not found" || err.Error() == "team not found" {
-			h.writeError(w, NOTFOUND, err.Error(), http.StatusNotFound)
-			return
-		}
-		h.writeError(w, NOTFOUND, err.Error(), http.StatusInternalServerError)
+		h.writeDomainError(w, err)
 		return
 	}
 
-	resp := map[string]PullRequest{"pr": mapPRToResponse(pr)}
+	resp := struct {
+		PR                PullRequest `json:"pr"`
+		ReviewersShortage string      `json:"reviewers_shortage,omitempty"`
+	}{
+		PR:                mapPRToResponse(pr),
+		ReviewersShortage: pr.ReviewerShortage,
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
-func (h *ApiHandler) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+func (h *ApiHandler) PostPullRequestMerge(w http.ResponseWriter, r *http.Request, params PostPullRequestMergeParams) {
 	var body PostPullRequestMergeJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		h.writeError(w, NOTFOUND, "invalid json", http.StatusBadRequest)
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := h.PRService.Merge(r.Context(), body.PullRequestId, actorFrom(params.XActor))
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	resp := map[string]PullRequest{"pr": mapPRToResponse(pr)}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *ApiHandler) PostPullRequestReview(w http.ResponseWriter, r *http.Request) {
+	var body PostPullRequestReviewJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := h.PRService.Review(r.Context(), body.PullRequestId, body.ReviewerId, string(body.State))
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	resp := map[string]PullRequest{"pr": mapPRToResponse(pr)}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *ApiHandler) PostPullRequestReady(w http.ResponseWriter, r *http.Request, params PostPullRequestReadyParams) {
+	var body PostPullRequestReadyJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
 		return
 	}
 
-	pr, err := h.PRService.Merge(r.Context(), body.PullRequestId)
+	pr, err := h.PRService.MarkReady(r.Context(), body.PullRequestId, actorFrom(params.XActor))
 	if err != nil {
-		h.writeError(w, NOTFOUND, "pr not found", http.StatusNotFound)
+		h.writeDomainError(w, err)
+		return
+	}
+	h.writeOpenedPR(w, pr)
+}
+
+func (h *ApiHandler) PostPullRequestClose(w http.ResponseWriter, r *http.Request, params PostPullRequestCloseParams) {
+	var body PostPullRequestCloseJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := h.PRService.Close(r.Context(), body.PullRequestId, actorFrom(params.XActor))
+	if err != nil {
+		h.writeDomainError(w, err)
 		return
 	}
 
@@ -143,27 +613,49 @@ func (h *ApiHandler) PostPullRequestMerge(w http.ResponseWriter, r *http.Request
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
-func (h *ApiHandler) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+func (h *ApiHandler) PostPullRequestReopen(w http.ResponseWriter, r *http.Request, params PostPullRequestReopenParams) {
+	var body PostPullRequestReopenJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := h.PRService.Reopen(r.Context(), body.PullRequestId, actorFrom(params.XActor))
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	h.writeOpenedPR(w, pr)
+}
+
+// Хелпер для ready/reopen: PR и, если ревьюеров не хватило, причина
+func (h *ApiHandler) writeOpenedPR(w http.ResponseWriter, pr *models.PullRequest) {
+	resp := struct {
+		PR                PullRequest `json:"pr"`
+		ReviewersShortage string      `json:"reviewers_shortage,omitempty"`
+	}{
+		PR:                mapPRToResponse(pr),
+		ReviewersShortage: pr.ReviewerShortage,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *ApiHandler) PostPullRequestReassign(w http.ResponseWriter, r *http.Request, params PostPullRequestReassignParams) {
 	var body PostPullRequestReassignJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		h.writeError(w, NOTFOUND, "invalid json", http.StatusBadRequest)
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
 		return
 	}
 
-	pr, newID, err := h.PRService.Reassign(r.Context(), body.PullRequestId, body.OldUserId)
+	var reason string
+	if body.Reason != nil {
+		reason = *body.Reason
+	}
+
+	pr, newID, err := h.PRService.Reassign(r.Context(), body.PullRequestId, body.OldUserId, actorFrom(params.XActor), reason)
 	if err != nil {
-		switch err.Error() {
-		case "pr merged":
-			h.writeError(w, PRMERGED, "cannot reassign on merged PR", http.StatusConflict)
-		case "reviewer not assigned":
-			h.writeError(w, NOTASSIGNED, "reviewer is not assigned to this PR", http.StatusConflict)
-		case "no candidates":
-			h.writeError(w, NOCANDIDATE, "no active replacement candidate in team", http.StatusConflict)
-		case "pr not found", "old reviewer not found":
-			h.writeError(w, NOTFOUND, err.Error(), http.StatusNotFound)
-		default:
-			h.writeError(w, NOTFOUND, err.Error(), http.StatusInternalServerError)
-		}
+		h.writeDomainError(w, err)
 		return
 	}
 
@@ -179,10 +671,112 @@ func (h *ApiHandler) PostPullRequestReassign(w http.ResponseWriter, r *http.Requ
 	_ = json.NewEncoder(w).Encode(response)
 }
 
+func (h *ApiHandler) GetPullRequestGet(w http.ResponseWriter, r *http.Request, params GetPullRequestGetParams) {
+	pr, err := h.PRService.Get(r.Context(), params.PullRequestId)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	body, err := json.Marshal(map[string]PullRequest{"pr": mapPRToResponse(pr)})
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	etag := etagOf(body)
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if params.IfNoneMatch != nil && etagMatches(*params.IfNoneMatch, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(append(body, '\n'))
+}
+
+func (h *ApiHandler) GetPullRequestHistory(w http.ResponseWriter, r *http.Request, params GetPullRequestHistoryParams) {
+	records, err := h.PRService.History(r.Context(), params.PullRequestId)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	history := make([]AssignmentRecord, len(records))
+	for i, rec := range records {
+		history[i] = mapAssignmentRecord(rec)
+	}
+
+	response := struct {
+		PullRequestId string             `json:"pull_request_id"`
+		History       []AssignmentRecord `json:"history"`
+	}{
+		PullRequestId: params.PullRequestId,
+		History:       history,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (h *ApiHandler) GetPullRequestList(w http.ResponseWriter, r *http.Request, params GetPullRequestListParams) {
+	filter := models.PRFilter{
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+		MergedFrom:  params.MergedFrom,
+		MergedTo:    params.MergedTo,
+	}
+	if params.Status != nil {
+		filter.Status = string(*params.Status)
+	}
+	if params.AuthorId != nil {
+		filter.AuthorID = *params.AuthorId
+	}
+	if params.ReviewerId != nil {
+		filter.ReviewerID = *params.ReviewerId
+	}
+	if params.TeamName != nil {
+		filter.TeamName = *params.TeamName
+	}
+	if params.Limit != nil {
+		if *params.Limit < 1 {
+			h.writeError(w, BADREQUEST, "limit must be positive", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = *params.Limit
+	}
+	var cursor string
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+
+	prs, next, err := h.PRService.List(r.Context(), filter, cursor)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	response := struct {
+		PullRequests []PullRequestSummary `json:"pull_requests"`
+		NextCursor   *string              `json:"next_cursor"`
+	}{
+		PullRequests: make([]PullRequestSummary, len(prs)),
+	}
+	for i, pr := range prs {
+		response.PullRequests[i] = mapPullRequestSummary(pr)
+	}
+	if next != "" {
+		response.NextCursor = &next
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
 func (h *ApiHandler) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams) {
 	prs, err := h.UserService.GetReviewPRs(r.Context(), params.UserId)
 	if err != nil {
-		h.writeError(w, NOTFOUND, err.Error(), http.StatusInternalServerError)
+		h.writeDomainError(w, err)
 		return
 	}
 
@@ -203,21 +797,161 @@ func (h *ApiHandler) GetUsersGetReview(w http.ResponseWriter, r *http.Request, p
 	_ = json.NewEncoder(w).Encode(response)
 }
 
+func (h *ApiHandler) PostWebhooksCreate(w http.ResponseWriter, r *http.Request) {
+	var body PostWebhooksCreateJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	var events []string
+	if body.Events != nil {
+		events = *body.Events
+	}
+	var secret string
+	if body.Secret != nil {
+		secret = *body.Secret
+	}
+	sub, err := h.EventService.CreateSubscription(r.Context(), body.Url, events, secret)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	resp := struct {
+		Subscription WebhookSubscription `json:"subscription"`
+		Secret       string              `json:"secret"`
+	}{
+		Subscription: mapWebhookSubscription(sub),
+		Secret:       sub.Secret,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *ApiHandler) GetWebhooksList(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.EventService.ListSubscriptions(r.Context())
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	out := make([]WebhookSubscription, len(subs))
+	for i := range subs {
+		out[i] = mapWebhookSubscription(&subs[i])
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string][]WebhookSubscription{"subscriptions": out})
+}
+
+func (h *ApiHandler) PostWebhooksUpdate(w http.ResponseWriter, r *http.Request) {
+	var body PostWebhooksUpdateJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.EventService.UpdateSubscription(r.Context(), body.Id, service.SubscriptionUpdate{
+		URL:    body.Url,
+		Events: body.Events,
+		Secret: body.Secret,
+		Active: body.Active,
+	})
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]WebhookSubscription{"subscription": mapWebhookSubscription(sub)})
+}
+
+func (h *ApiHandler) PostWebhooksDelete(w http.ResponseWriter, r *http.Request) {
+	var body PostWebhooksDeleteJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.EventService.DeleteSubscription(r.Context(), body.Id); err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ApiHandler) GetWebhooksDeliveries(w http.ResponseWriter, r *http.Request, params GetWebhooksDeliveriesParams) {
+	var limit int
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	deliveries, err := h.EventService.Deliveries(r.Context(), params.SubscriptionId, limit)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	response := struct {
+		SubscriptionId int64             `json:"subscription_id"`
+		Deliveries     []WebhookDelivery `json:"deliveries"`
+	}{
+		SubscriptionId: params.SubscriptionId,
+		Deliveries:     make([]WebhookDelivery, len(deliveries)),
+	}
+	for i, d := range deliveries {
+		response.Deliveries[i] = mapWebhookDelivery(d)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (h *ApiHandler) GetWebhooksDeadLetters(w http.ResponseWriter, r *http.Request) {
+	letters, err := h.EventService.DeadLetters(r.Context())
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	out := make([]DeadLetter, len(letters))
+	for i, l := range letters {
+		out[i] = mapDeadLetter(l)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string][]DeadLetter{"dead_letters": out})
+}
+
+func (h *ApiHandler) PostWebhooksRedeliver(w http.ResponseWriter, r *http.Request) {
+	var body PostWebhooksRedeliverJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.writeError(w, BADREQUEST, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.EventService.Redeliver(r.Context(), body.DeadLetterId); err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
 func (h *ApiHandler) CustomGetStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.UserService.GetStats(r.Context())
 	if err != nil {
-		h.writeError(w, "INTERNAL", err.Error(), http.StatusInternalServerError)
+		h.writeDomainError(w, err)
 		return
 	}
 
 	type StatResponse struct {
-		Username string `json:"username"`
-		Count    int    `json:"review_count"`
+		Username  string `json:"username"`
+		Count     int    `json:"review_count"`
+		OpenCount int    `json:"open_review_count"`
 	}
 
 	resp := make([]StatResponse, len(stats))
 	for i, s := range stats {
-		resp[i] = StatResponse{Username: s.Username, Count: s.ReviewCount}
+		resp[i] = StatResponse{Username: s.Username, Count: s.ReviewCount, OpenCount: s.OpenReviewCount}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
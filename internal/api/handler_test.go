@@ -0,0 +1,271 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/memory"
+	"github.com/humooo/avito-backend-trainee-2025/internal/service"
+)
+
+// newTestServer собирает обработчики поверх хранилища в памяти, как main с -storage=memory.
+func newTestServer(t *testing.T) (http.Handler, *service.EventStream) {
+	t.Helper()
+	s := memory.NewStore()
+	users, teams, prs := memory.NewUserRepo(s), memory.NewTeamRepo(s), memory.NewPRRepo(s)
+	outboxRepo := memory.NewOutboxRepo(s)
+	selector, err := service.NewReviewerSelector(service.StrategyLeastLoaded, nil)
+	if err != nil {
+		t.Fatalf("selector: %v", err)
+	}
+	defaults := service.DefaultTeamSettings()
+	stream := service.NewEventStream(outboxRepo)
+	h := &ApiHandler{
+		PRService:   service.NewPRService(prs, users, teams, selector, defaults, nil),
+		UserService: service.NewUserService(users, prs),
+		TeamService: service.NewTeamService(teams, users, defaults),
+		EventStream: stream,
+	}
+	return HandlerFromMux(h, chi.NewRouter()), stream
+}
+
+func doRequest(t *testing.T, h http.Handler, method, target string, body any, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// seedPRs создаёт команду backend из u1..u3 и открытые PR автора u1.
+func seedPRs(t *testing.T, h http.Handler, ids ...string) {
+	t.Helper()
+	team := map[string]any{"team_name": "backend", "members": []map[string]any{
+		{"user_id": "u1", "username": "Alice", "is_active": true},
+		{"user_id": "u2", "username": "Bob", "is_active": true},
+		{"user_id": "u3", "username": "Carol", "is_active": true},
+	}}
+	if rec := doRequest(t, h, http.MethodPost, "/team/add", team, nil); rec.Code != http.StatusCreated {
+		t.Fatalf("team add: %d %s", rec.Code, rec.Body)
+	}
+	for _, id := range ids {
+		pr := map[string]any{"pull_request_id": id, "pull_request_name": "name-" + id, "author_id": "u1"}
+		if rec := doRequest(t, h, http.MethodPost, "/pullRequest/create", pr, nil); rec.Code != http.StatusCreated {
+			t.Fatalf("create %s: %d %s", id, rec.Code, rec.Body)
+		}
+	}
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) ErrorResponse {
+	t.Helper()
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	return resp
+}
+
+func TestErrorCode(t *testing.T) {
+	for _, tt := range []struct {
+		err     error
+		code    ErrorResponseErrorCode
+		status  int
+		message string
+	}{
+		{fmt.Errorf("pr pr-1: %w", domain.ErrPRExists), PREXISTS, http.StatusConflict, domain.ErrPRExists.Error()},
+		{fmt.Errorf("team x: %w", domain.ErrTeamExists), TEAMEXISTS, http.StatusBadRequest, domain.ErrTeamExists.Error()},
+		// Сервис не перевёл конфликт уникальности в конкретную ошибку
+		{fmt.Errorf("%w: duplicate key value violates unique constraint", domain.ErrAlreadyExists), ALREADYEXISTS, http.StatusConflict, "already exists"},
+		{fmt.Errorf("user u9: %w", domain.ErrNotFound), NOTFOUND, http.StatusNotFound, "not found"},
+		{domain.ErrNoCandidate, NOCANDIDATE, http.StatusConflict, domain.ErrNoCandidate.Error()},
+		{errors.New("connection refused"), INTERNAL, http.StatusInternalServerError, "internal error"},
+	} {
+		code, status, message := errorCode(tt.err)
+		if code != tt.code || status != tt.status || message != tt.message {
+			t.Fatalf("%v: got %s %d %q, want %s %d %q", tt.err, code, status, message, tt.code, tt.status, tt.message)
+		}
+	}
+}
+
+func TestDomainErrorResponse(t *testing.T) {
+	h, _ := newTestServer(t)
+	seedPRs(t, h, "pr-1")
+
+	rec := doRequest(t, h, http.MethodPost, "/pullRequest/create", map[string]any{"pull_request_id": "pr-1", "pull_request_name": "again", "author_id": "u1"}, nil)
+	if resp := decodeError(t, rec); rec.Code != http.StatusConflict || resp.Error.Code != PREXISTS {
+		t.Fatalf("duplicate pr: %d %+v", rec.Code, resp)
+	}
+	rec = doRequest(t, h, http.MethodGet, "/pullRequest/get?pull_request_id=pr-9", nil, nil)
+	if resp := decodeError(t, rec); rec.Code != http.StatusNotFound || resp.Error.Code != NOTFOUND || strings.Contains(resp.Error.Message, "pr-9") {
+		t.Fatalf("missing pr: %d %+v", rec.Code, resp)
+	}
+}
+
+func TestPullRequestGetETag(t *testing.T) {
+	h, _ := newTestServer(t)
+	seedPRs(t, h, "pr-1")
+
+	rec := doRequest(t, h, http.MethodGet, "/pullRequest/get?pull_request_id=pr-1", nil, nil)
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" {
+		t.Fatalf("get: %d, etag %q", rec.Code, etag)
+	}
+
+	// Тот же ответ — 304 без тела; W/ и список через запятую тоже совпадают
+	for _, inm := range []string{etag, `"other", W/` + etag, "*"} {
+		rec = doRequest(t, h, http.MethodGet, "/pullRequest/get?pull_request_id=pr-1", nil, http.Header{"If-None-Match": {inm}})
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 || rec.Header().Get("ETag") != etag {
+			t.Fatalf("If-None-Match %s: %d, body %q", inm, rec.Code, rec.Body)
+		}
+	}
+
+	// После изменения PR старый ETag не совпадает
+	if rec := doRequest(t, h, http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "pr-1"}, nil); rec.Code != http.StatusOK {
+		t.Fatalf("merge: %d %s", rec.Code, rec.Body)
+	}
+	rec = doRequest(t, h, http.MethodGet, "/pullRequest/get?pull_request_id=pr-1", nil, http.Header{"If-None-Match": {etag}})
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag || !strings.Contains(rec.Body.String(), `"MERGED"`) {
+		t.Fatalf("after merge: %d, etag %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestPullRequestListCursor(t *testing.T) {
+	h, _ := newTestServer(t)
+	seedPRs(t, h, "pr-1", "pr-2", "pr-3")
+
+	type page struct {
+		PullRequests []PullRequestSummary `json:"pull_requests"`
+		NextCursor   *string              `json:"next_cursor"`
+	}
+	var ids []string
+	target := "/pullRequest/list?author_id=u1&limit=2"
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("paging does not stop")
+		}
+		rec := doRequest(t, h, http.MethodGet, target, nil, nil)
+		var p page
+		if err := json.NewDecoder(rec.Body).Decode(&p); rec.Code != http.StatusOK || err != nil {
+			t.Fatalf("list %s: %d, %v", target, rec.Code, err)
+		}
+		for _, pr := range p.PullRequests {
+			ids = append(ids, pr.PullRequestId)
+		}
+		if p.NextCursor == nil {
+			break
+		}
+		target = "/pullRequest/list?author_id=u1&limit=2&cursor=" + url.QueryEscape(*p.NextCursor)
+	}
+	slices.Sort(ids)
+	if !slices.Equal(ids, []string{"pr-1", "pr-2", "pr-3"}) {
+		t.Fatalf("pages: %v", ids)
+	}
+
+	for _, target := range []string{"/pullRequest/list?cursor=garbage", "/pullRequest/list?limit=0"} {
+		rec := doRequest(t, h, http.MethodGet, target, nil, nil)
+		if resp := decodeError(t, rec); rec.Code != http.StatusBadRequest || resp.Error.Code != BADREQUEST {
+			t.Fatalf("%s: %d %+v", target, rec.Code, resp)
+		}
+	}
+}
+
+func TestEventsStreamResume(t *testing.T) {
+	h, stream := newTestServer(t)
+	// По PR: pr.created и два reviewer.assigned, id 1-6
+	seedPRs(t, h, "pr-1", "pr-2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := stream.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	if rec := doRequest(t, h, http.MethodGet, "/events/stream", nil, http.Header{"Last-Event-Id": {"x"}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid Last-Event-ID: %d", rec.Code)
+	}
+
+	// Клиент видел всё по pr.created второго PR включительно
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events/stream", nil)
+	req.Header.Set("Last-Event-ID", "4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("stream: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	type sseEvent struct {
+		id   string
+		data streamEvent
+	}
+	events := make(chan sseEvent)
+	go func() {
+		defer close(events)
+		var ev sseEvent
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				ev.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev.data)
+			case line == "" && ev.id != "":
+				events <- ev
+				ev = sseEvent{}
+			}
+		}
+	}()
+	next := func() sseEvent {
+		t.Helper()
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				t.Fatal("stream closed")
+			}
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event")
+		}
+		return sseEvent{}
+	}
+
+	// Догонка: назначения pr-2, дальше — живой поток
+	for _, id := range []int64{5, 6} {
+		if ev := next(); ev.id != fmt.Sprint(id) || ev.data.ID != id || ev.data.PRID != "pr-2" {
+			t.Fatalf("replayed: %+v", ev)
+		}
+	}
+	if rec := doRequest(t, h, http.MethodPost, "/pullRequest/merge", map[string]any{"pull_request_id": "pr-1"}, nil); rec.Code != http.StatusOK {
+		t.Fatalf("merge: %d %s", rec.Code, rec.Body)
+	}
+	if ev := next(); ev.id != "7" || ev.data.Type != "pr.merged" || ev.data.PRID != "pr-1" {
+		t.Fatalf("live: %+v", ev)
+	}
+}
@@ -1,36 +1,51 @@
 package api
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/humooo/avito-backend-trainee-2025/internal/models"
 )
 
 func mapPRToResponse(pr *models.PullRequest) PullRequest {
-	status := PullRequestStatusOPEN
-	if pr.Status == "MERGED" {
-		status = PullRequestStatusMERGED
+	reviews := make([]ReviewerState, len(pr.Reviewers))
+	for i, id := range pr.Reviewers {
+		reviews[i] = ReviewerState{ReviewerId: id, State: ReviewerStateState(pr.ReviewState(id)), IsFallback: pr.Fallback[id]}
+		if at, ok := pr.AssignedAt[id]; ok {
+			reviews[i].AssignedAt = &at
+		}
 	}
 
 	return PullRequest{
 		PullRequestId:     pr.ID,
 		PullRequestName:   pr.Title,
 		AuthorId:          pr.AuthorID,
-		Status:            status,
+		Status:            PullRequestStatus(pr.Status),
 		AssignedReviewers: pr.Reviewers,
+		Reviews:           reviews,
 		CreatedAt:         &pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
 	}
 }
 
 func mapPullRequestShort(pr *models.PullRequest) PullRequestShort {
-	status := PullRequestShortStatusOPEN
-	if pr.Status == "MERGED" {
-		status = PullRequestShortStatusMERGED
-	}
 	return PullRequestShort{
 		PullRequestId:   pr.ID,
 		PullRequestName: pr.Title,
 		AuthorId:        pr.AuthorID,
-		Status:          status,
+		Status:          PullRequestShortStatus(pr.Status),
+	}
+}
+
+func mapPullRequestSummary(pr *models.PullRequest) PullRequestSummary {
+	return PullRequestSummary{
+		PullRequestId:     pr.ID,
+		PullRequestName:   pr.Title,
+		AuthorId:          pr.AuthorID,
+		Status:            PullRequestSummaryStatus(pr.Status),
+		AssignedReviewers: append([]string{}, pr.Reviewers...),
+		CreatedAt:         pr.CreatedAt,
+		MergedAt:          pr.MergedAt,
 	}
 }
 
@@ -38,9 +53,10 @@ func mapTeamToResponse(team *models.Team, users []*models.User) Team {
 	members := make([]TeamMember, len(users))
 	for i, u := range users {
 		members[i] = TeamMember{
-			UserId:   u.ID,
-			Username: u.Name,
-			IsActive: u.IsActive,
+			UserId:         u.ID,
+			Username:       u.Name,
+			IsActive:       u.IsActive,
+			MaxOpenReviews: u.MaxOpenReviews,
 		}
 	}
 	return Team{
@@ -48,3 +64,184 @@ func mapTeamToResponse(team *models.Team, users []*models.User) Team {
 		Members:  members,
 	}
 }
+
+func mapTeamSettings(ts *models.TeamSettings) TeamSettings {
+	resp := TeamSettings{
+		TeamName:      ts.TeamName,
+		ReviewerCount: ts.ReviewerCount,
+		MinApprovals:  ts.MinApprovals,
+		RequireLead:   ts.RequireLead,
+		FallbackTeams: ts.FallbackTeams,
+	}
+	if resp.FallbackTeams == nil {
+		resp.FallbackTeams = []string{}
+	}
+	if ts.LeadUserID != "" {
+		resp.LeadUserId = &ts.LeadUserID
+	}
+	if ts.Strategy != "" {
+		strategy := TeamSettingsStrategy(ts.Strategy)
+		resp.Strategy = &strategy
+	}
+	resp.ReviewSlaMinutes = int(ts.ReviewSLA / time.Minute)
+	if ts.SLAAction != "" {
+		action := TeamSettingsSlaAction(ts.SLAAction)
+		resp.SlaAction = &action
+	}
+	if ts.ChatChannel != "" {
+		resp.ChatChannel = &ts.ChatChannel
+	}
+	return resp
+}
+
+func mapUserToResponse(u *models.User) User {
+	resp := User{
+		UserId:         u.ID,
+		Username:       u.Name,
+		TeamName:       u.TeamName,
+		IsActive:       u.IsActive,
+		MaxOpenReviews: u.MaxOpenReviews,
+	}
+	if wh := u.WorkingHours; wh != nil {
+		resp.WorkingHours = &WorkingHours{TimeZone: wh.TimeZone, Start: clock(wh.Start), End: clock(wh.End)}
+	}
+	return resp
+}
+
+// clock форматирует минуты от полуночи как HH:MM.
+func clock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func mapReviewerChanges(changes []models.ReviewerChange) []ReviewerReassignment {
+	out := make([]ReviewerReassignment, len(changes))
+	for i, c := range changes {
+		out[i] = mapReviewerChange(c)
+	}
+	return out
+}
+
+func mapReviewerChange(c models.ReviewerChange) ReviewerReassignment {
+	out := ReviewerReassignment{
+		PullRequestId: c.PRID,
+		OldUserId:     c.OldReviewerID,
+		Outcome:       ReviewerReassignmentOutcomeREMOVED,
+	}
+	if c.NewReviewerID != "" {
+		newID := c.NewReviewerID
+		out.Outcome = ReviewerReassignmentOutcomeREPLACED
+		out.ReplacedBy = &newID
+	}
+	return out
+}
+
+func mapAssignmentRecord(rec models.AssignmentRecord) AssignmentRecord {
+	out := AssignmentRecord{
+		Id:         rec.ID,
+		ReviewerId: rec.ReviewerID,
+		Action:     AssignmentRecordAction(rec.Action),
+		Actor:      rec.Actor,
+		Reason:     rec.Reason,
+		CreatedAt:  rec.CreatedAt,
+	}
+	if rec.NewReviewerID != "" {
+		newID := rec.NewReviewerID
+		out.NewReviewerId = &newID
+	}
+	return out
+}
+
+func mapTeamMove(m models.TeamMove) TeamMove {
+	out := TeamMove{
+		Id:              m.ID,
+		UserId:          m.UserID,
+		ToTeam:          m.ToTeam,
+		HandoverReviews: m.Handover,
+		Actor:           m.Actor,
+		CreatedAt:       m.CreatedAt,
+	}
+	if m.FromTeam != "" {
+		from := m.FromTeam
+		out.FromTeam = &from
+	}
+	return out
+}
+
+func mapAbsence(a models.Absence) Absence {
+	return Absence{
+		AbsenceId: a.ID,
+		UserId:    a.UserID,
+		StartsAt:  a.StartsAt,
+		EndsAt:    a.EndsAt,
+		Reason:    a.Reason,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+func mapOverdueReview(o models.OverdueReview) OverdueReview {
+	return OverdueReview{
+		PullRequestId:   o.PRID,
+		PullRequestName: o.Title,
+		AuthorId:        o.AuthorID,
+		ReviewerId:      o.ReviewerID,
+		AssignedAt:      o.AssignedAt,
+		Escalated:       o.Escalated,
+	}
+}
+
+func mapWebhookSubscription(sub *models.WebhookSubscription) WebhookSubscription {
+	return WebhookSubscription{
+		Id:        sub.ID,
+		Url:       sub.URL,
+		Events:    append([]string{}, sub.Events...),
+		Active:    sub.Active,
+		CreatedAt: sub.CreatedAt,
+	}
+}
+
+func mapWebhookDelivery(d models.WebhookDelivery) WebhookDelivery {
+	out := WebhookDelivery{
+		Id:             d.ID,
+		SubscriptionId: d.SubscriptionID,
+		Event:          d.Event,
+		Status:         WebhookDeliveryStatus(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	if d.LastError != "" {
+		lastError := d.LastError
+		out.LastError = &lastError
+	}
+	return out
+}
+
+func mapDeadLetter(l models.DeadLetter) DeadLetter {
+	return DeadLetter{
+		Id:             l.ID,
+		DeliveryId:     l.DeliveryID,
+		SubscriptionId: l.SubscriptionID,
+		Event:          l.Event,
+		Payload:        string(l.Payload),
+		Attempts:       l.Attempts,
+		LastError:      l.LastError,
+		CreatedAt:      l.CreatedAt,
+	}
+}
+
+func actorFrom(header *ActorHeader) string {
+	if header == nil {
+		return ""
+	}
+	return *header
+}
+
+func mapNotificationPrefs(p *models.NotificationPrefs) NotificationPreferences {
+	return NotificationPreferences{
+		UserId:  p.UserID,
+		Mode:    NotificationPreferencesMode(p.Mode),
+		Channel: p.Channel,
+	}
+}
@@ -13,90 +13,1351 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Поток событий назначения, замены ревьюеров и merge (Server-Sent Events)
+	// (GET /events/stream)
+	GetEventsStream(w http.ResponseWriter, r *http.Request, params GetEventsStreamParams)
+	// Закрыть PR без merge и снять ревьюверов (идемпотентная операция)
+	// (POST /pullRequest/close)
+	PostPullRequestClose(w http.ResponseWriter, r *http.Request, params PostPullRequestCloseParams)
 	// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
 	// (POST /pullRequest/create)
-	PostPullRequestCreate(w http.ResponseWriter, r *http.Request)
+	PostPullRequestCreate(w http.ResponseWriter, r *http.Request, params PostPullRequestCreateParams)
+	// Получить текущее состояние PR
+	// (GET /pullRequest/get)
+	GetPullRequestGet(w http.ResponseWriter, r *http.Request, params GetPullRequestGetParams)
+	// История назначений ревьюверов PR
+	// (GET /pullRequest/history)
+	GetPullRequestHistory(w http.ResponseWriter, r *http.Request, params GetPullRequestHistoryParams)
+	// Список PR с фильтрами и постраничной выдачей
+	// (GET /pullRequest/list)
+	GetPullRequestList(w http.ResponseWriter, r *http.Request, params GetPullRequestListParams)
 	// Пометить PR как MERGED (идемпотентная операция)
 	// (POST /pullRequest/merge)
-	PostPullRequestMerge(w http.ResponseWriter, r *http.Request)
+	PostPullRequestMerge(w http.ResponseWriter, r *http.Request, params PostPullRequestMergeParams)
+	// Перевести черновик в OPEN и назначить ревьюверов
+	// (POST /pullRequest/ready)
+	PostPullRequestReady(w http.ResponseWriter, r *http.Request, params PostPullRequestReadyParams)
 	// Переназначить конкретного ревьювера на другого из его команды
 	// (POST /pullRequest/reassign)
-	PostPullRequestReassign(w http.ResponseWriter, r *http.Request)
+	PostPullRequestReassign(w http.ResponseWriter, r *http.Request, params PostPullRequestReassignParams)
+	// Переоткрыть закрытый PR с новым назначением ревьюверов
+	// (POST /pullRequest/reopen)
+	PostPullRequestReopen(w http.ResponseWriter, r *http.Request, params PostPullRequestReopenParams)
+	// Записать решение ревьювера по OPEN PR
+	// (POST /pullRequest/review)
+	PostPullRequestReview(w http.ResponseWriter, r *http.Request)
 	// Создать команду с участниками (создаёт/обновляет пользователей)
 	// (POST /team/add)
 	PostTeamAdd(w http.ResponseWriter, r *http.Request)
+	// Добавить участников в существующую команду
+	// (POST /team/addMembers)
+	PostTeamAddMembers(w http.ResponseWriter, r *http.Request)
+	// Массово деактивировать пользователей и переназначить их открытые ревью
+	// (POST /team/deactivateUsers)
+	PostTeamDeactivateUsers(w http.ResponseWriter, r *http.Request, params PostTeamDeactivateUsersParams)
+	// Удалить команду
+	// (POST /team/delete)
+	PostTeamDelete(w http.ResponseWriter, r *http.Request, params PostTeamDeleteParams)
 	// Получить команду с участниками
 	// (GET /team/get)
 	GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams)
+	// Просроченные ревью PR команды
+	// (GET /team/overdueReviews)
+	GetTeamOverdueReviews(w http.ResponseWriter, r *http.Request, params GetTeamOverdueReviewsParams)
+	// Убрать участников из команды
+	// (POST /team/removeMembers)
+	PostTeamRemoveMembers(w http.ResponseWriter, r *http.Request, params PostTeamRemoveMembersParams)
+	// Переименовать команду
+	// (POST /team/rename)
+	PostTeamRename(w http.ResponseWriter, r *http.Request)
+	// Получить настройки назначения ревьюверов для команды
+	// (GET /team/settings)
+	GetTeamSettings(w http.ResponseWriter, r *http.Request, params GetTeamSettingsParams)
+	// Изменить настройки команды
+	// (POST /team/settings)
+	PostTeamSettings(w http.ResponseWriter, r *http.Request)
+	// Периоды отсутствия пользователя
+	// (GET /users/absences)
+	GetUsersAbsences(w http.ResponseWriter, r *http.Request, params GetUsersAbsencesParams)
+	// Зарегистрировать период отсутствия
+	// (POST /users/addAbsence)
+	PostUsersAddAbsence(w http.ResponseWriter, r *http.Request)
+	// Удалить период отсутствия
+	// (POST /users/deleteAbsence)
+	PostUsersDeleteAbsence(w http.ResponseWriter, r *http.Request)
 	// Получить PR'ы, где пользователь назначен ревьювером
 	// (GET /users/getReview)
 	GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams)
+	// Связать логин на GitHub/GitLab с пользователем
+	// (POST /users/linkIdentity)
+	PostUsersLinkIdentity(w http.ResponseWriter, r *http.Request)
+	// Перевести пользователя в другую команду
+	// (POST /users/moveTeam)
+	PostUsersMoveTeam(w http.ResponseWriter, r *http.Request, params PostUsersMoveTeamParams)
+	// Настройки уведомлений пользователя о назначениях
+	// (GET /users/notificationPreferences)
+	GetUsersNotificationPreferences(w http.ResponseWriter, r *http.Request, params GetUsersNotificationPreferencesParams)
 	// Установить флаг активности пользователя
 	// (POST /users/setIsActive)
 	PostUsersSetIsActive(w http.ResponseWriter, r *http.Request)
+	// Задать настройки уведомлений пользователя о назначениях
+	// (POST /users/setNotificationPreferences)
+	PostUsersSetNotificationPreferences(w http.ResponseWriter, r *http.Request)
+	// Задать часовой пояс и рабочие часы пользователя
+	// (POST /users/setWorkingHours)
+	PostUsersSetWorkingHours(w http.ResponseWriter, r *http.Request)
+	// История переводов пользователя между командами
+	// (GET /users/teamMoves)
+	GetUsersTeamMoves(w http.ResponseWriter, r *http.Request, params GetUsersTeamMovesParams)
+	// Подписаться на события PR
+	// (POST /webhooks/create)
+	PostWebhooksCreate(w http.ResponseWriter, r *http.Request)
+	// Доставки, исчерпавшие попытки
+	// (GET /webhooks/deadLetters)
+	GetWebhooksDeadLetters(w http.ResponseWriter, r *http.Request)
+	// Удалить подписку вместе с журналом доставок
+	// (POST /webhooks/delete)
+	PostWebhooksDelete(w http.ResponseWriter, r *http.Request)
+	// Журнал доставок подписки
+	// (GET /webhooks/deliveries)
+	GetWebhooksDeliveries(w http.ResponseWriter, r *http.Request, params GetWebhooksDeliveriesParams)
+	// Подписки на события
+	// (GET /webhooks/list)
+	GetWebhooksList(w http.ResponseWriter, r *http.Request)
+	// Повторить недоставленное событие
+	// (POST /webhooks/redeliver)
+	PostWebhooksRedeliver(w http.ResponseWriter, r *http.Request)
+	// Изменить подписку
+	// (POST /webhooks/update)
+	PostWebhooksUpdate(w http.ResponseWriter, r *http.Request)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
+// Поток событий назначения, замены ревьюеров и merge (Server-Sent Events)
+// (GET /events/stream)
+func (_ Unimplemented) GetEventsStream(w http.ResponseWriter, r *http.Request, params GetEventsStreamParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Закрыть PR без merge и снять ревьюверов (идемпотентная операция)
+// (POST /pullRequest/close)
+func (_ Unimplemented) PostPullRequestClose(w http.ResponseWriter, r *http.Request, params PostPullRequestCloseParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Создать PR и автоматически назначить до 2 ревьюверов из команды автора
 // (POST /pullRequest/create)
-func (_ Unimplemented) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+func (_ Unimplemented) PostPullRequestCreate(w http.ResponseWriter, r *http.Request, params PostPullRequestCreateParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить текущее состояние PR
+// (GET /pullRequest/get)
+func (_ Unimplemented) GetPullRequestGet(w http.ResponseWriter, r *http.Request, params GetPullRequestGetParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// История назначений ревьюверов PR
+// (GET /pullRequest/history)
+func (_ Unimplemented) GetPullRequestHistory(w http.ResponseWriter, r *http.Request, params GetPullRequestHistoryParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Список PR с фильтрами и постраничной выдачей
+// (GET /pullRequest/list)
+func (_ Unimplemented) GetPullRequestList(w http.ResponseWriter, r *http.Request, params GetPullRequestListParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Пометить PR как MERGED (идемпотентная операция)
+// (POST /pullRequest/merge)
+func (_ Unimplemented) PostPullRequestMerge(w http.ResponseWriter, r *http.Request, params PostPullRequestMergeParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Перевести черновик в OPEN и назначить ревьюверов
+// (POST /pullRequest/ready)
+func (_ Unimplemented) PostPullRequestReady(w http.ResponseWriter, r *http.Request, params PostPullRequestReadyParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Переназначить конкретного ревьювера на другого из его команды
+// (POST /pullRequest/reassign)
+func (_ Unimplemented) PostPullRequestReassign(w http.ResponseWriter, r *http.Request, params PostPullRequestReassignParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Переоткрыть закрытый PR с новым назначением ревьюверов
+// (POST /pullRequest/reopen)
+func (_ Unimplemented) PostPullRequestReopen(w http.ResponseWriter, r *http.Request, params PostPullRequestReopenParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Записать решение ревьювера по OPEN PR
+// (POST /pullRequest/review)
+func (_ Unimplemented) PostPullRequestReview(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создать команду с участниками (создаёт/обновляет пользователей)
+// (POST /team/add)
+func (_ Unimplemented) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Добавить участников в существующую команду
+// (POST /team/addMembers)
+func (_ Unimplemented) PostTeamAddMembers(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Массово деактивировать пользователей и переназначить их открытые ревью
+// (POST /team/deactivateUsers)
+func (_ Unimplemented) PostTeamDeactivateUsers(w http.ResponseWriter, r *http.Request, params PostTeamDeactivateUsersParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить команду
+// (POST /team/delete)
+func (_ Unimplemented) PostTeamDelete(w http.ResponseWriter, r *http.Request, params PostTeamDeleteParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить команду с участниками
+// (GET /team/get)
+func (_ Unimplemented) GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Просроченные ревью PR команды
+// (GET /team/overdueReviews)
+func (_ Unimplemented) GetTeamOverdueReviews(w http.ResponseWriter, r *http.Request, params GetTeamOverdueReviewsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Убрать участников из команды
+// (POST /team/removeMembers)
+func (_ Unimplemented) PostTeamRemoveMembers(w http.ResponseWriter, r *http.Request, params PostTeamRemoveMembersParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Переименовать команду
+// (POST /team/rename)
+func (_ Unimplemented) PostTeamRename(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить настройки назначения ревьюверов для команды
+// (GET /team/settings)
+func (_ Unimplemented) GetTeamSettings(w http.ResponseWriter, r *http.Request, params GetTeamSettingsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Изменить настройки команды
+// (POST /team/settings)
+func (_ Unimplemented) PostTeamSettings(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Периоды отсутствия пользователя
+// (GET /users/absences)
+func (_ Unimplemented) GetUsersAbsences(w http.ResponseWriter, r *http.Request, params GetUsersAbsencesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Зарегистрировать период отсутствия
+// (POST /users/addAbsence)
+func (_ Unimplemented) PostUsersAddAbsence(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить период отсутствия
+// (POST /users/deleteAbsence)
+func (_ Unimplemented) PostUsersDeleteAbsence(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получить PR'ы, где пользователь назначен ревьювером
+// (GET /users/getReview)
+func (_ Unimplemented) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Связать логин на GitHub/GitLab с пользователем
+// (POST /users/linkIdentity)
+func (_ Unimplemented) PostUsersLinkIdentity(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Перевести пользователя в другую команду
+// (POST /users/moveTeam)
+func (_ Unimplemented) PostUsersMoveTeam(w http.ResponseWriter, r *http.Request, params PostUsersMoveTeamParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Настройки уведомлений пользователя о назначениях
+// (GET /users/notificationPreferences)
+func (_ Unimplemented) GetUsersNotificationPreferences(w http.ResponseWriter, r *http.Request, params GetUsersNotificationPreferencesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Установить флаг активности пользователя
+// (POST /users/setIsActive)
+func (_ Unimplemented) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Задать настройки уведомлений пользователя о назначениях
+// (POST /users/setNotificationPreferences)
+func (_ Unimplemented) PostUsersSetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Задать часовой пояс и рабочие часы пользователя
+// (POST /users/setWorkingHours)
+func (_ Unimplemented) PostUsersSetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// История переводов пользователя между командами
+// (GET /users/teamMoves)
+func (_ Unimplemented) GetUsersTeamMoves(w http.ResponseWriter, r *http.Request, params GetUsersTeamMovesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Подписаться на события PR
+// (POST /webhooks/create)
+func (_ Unimplemented) PostWebhooksCreate(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Доставки, исчерпавшие попытки
+// (GET /webhooks/deadLetters)
+func (_ Unimplemented) GetWebhooksDeadLetters(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удалить подписку вместе с журналом доставок
+// (POST /webhooks/delete)
+func (_ Unimplemented) PostWebhooksDelete(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Журнал доставок подписки
+// (GET /webhooks/deliveries)
+func (_ Unimplemented) GetWebhooksDeliveries(w http.ResponseWriter, r *http.Request, params GetWebhooksDeliveriesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Подписки на события
+// (GET /webhooks/list)
+func (_ Unimplemented) GetWebhooksList(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Повторить недоставленное событие
+// (POST /webhooks/redeliver)
+func (_ Unimplemented) PostWebhooksRedeliver(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Изменить подписку
+// (POST /webhooks/update)
+func (_ Unimplemented) PostWebhooksUpdate(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// Пометить PR как MERGED (идемпотентная операция)
-// (POST /pullRequest/merge)
-func (_ Unimplemented) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetEventsStream operation middleware
+func (siw *ServerInterfaceWrapper) GetEventsStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEventsStreamParams
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Last-Event-ID", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Last-Event-ID", runtime.ParamLocationHeader, valueList[0], &LastEventID)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Last-Event-ID", Err: err})
+			return
+		}
+
+		params.LastEventID = &LastEventID
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetEventsStream(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostPullRequestClose operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestClose(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestCloseParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor ActorHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Actor", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Actor", Err: err})
+			return
+		}
+
+		params.XActor = &XActor
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestClose(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostPullRequestCreate operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestCreateParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor ActorHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Actor", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Actor", Err: err})
+			return
+		}
+
+		params.XActor = &XActor
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestCreate(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetPullRequestGet operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestGet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestGetParams
+
+	// ------------- Required query parameter "pull_request_id" -------------
+
+	if paramValue := r.URL.Query().Get("pull_request_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "pull_request_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "pull_request_id", r.URL.Query(), &params.PullRequestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pull_request_id", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatchHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, valueList[0], &IfNoneMatch)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPullRequestGet(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetPullRequestHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestHistoryParams
+
+	// ------------- Required query parameter "pull_request_id" -------------
+
+	if paramValue := r.URL.Query().Get("pull_request_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "pull_request_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "pull_request_id", r.URL.Query(), &params.PullRequestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pull_request_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPullRequestHistory(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetPullRequestList operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestListParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "author_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author_id", r.URL.Query(), &params.AuthorId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "reviewer_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reviewer_id", r.URL.Query(), &params.ReviewerId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "reviewer_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_from", r.URL.Query(), &params.CreatedFrom)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_to", r.URL.Query(), &params.CreatedTo)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "merged_from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "merged_from", r.URL.Query(), &params.MergedFrom)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "merged_from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "merged_to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "merged_to", r.URL.Query(), &params.MergedTo)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "merged_to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPullRequestList(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostPullRequestMerge operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestMergeParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor ActorHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Actor", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Actor", Err: err})
+			return
+		}
+
+		params.XActor = &XActor
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestMerge(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostPullRequestReady operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestReady(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestReadyParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor ActorHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Actor", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Actor", Err: err})
+			return
+		}
+
+		params.XActor = &XActor
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestReady(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostPullRequestReassign operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestReassignParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor ActorHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Actor", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Actor", Err: err})
+			return
+		}
+
+		params.XActor = &XActor
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestReassign(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostPullRequestReopen operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestReopen(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostPullRequestReopenParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor ActorHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Actor", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Actor", Err: err})
+			return
+		}
+
+		params.XActor = &XActor
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestReopen(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostPullRequestReview operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestReview(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostTeamAdd operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamAdd(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostTeamAddMembers operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamAddMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamAddMembers(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostTeamDeactivateUsers operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamDeactivateUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostTeamDeactivateUsersParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor ActorHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Actor", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Actor", Err: err})
+			return
+		}
+
+		params.XActor = &XActor
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamDeactivateUsers(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostTeamDelete operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostTeamDeleteParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor ActorHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Actor", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Actor", Err: err})
+			return
+		}
+
+		params.XActor = &XActor
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamDelete(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetTeamGet operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamGet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamGetParams
+
+	// ------------- Required query parameter "team_name" -------------
+
+	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamGet(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetTeamOverdueReviews operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamOverdueReviews(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamOverdueReviewsParams
+
+	// ------------- Required query parameter "team_name" -------------
+
+	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamOverdueReviews(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostTeamRemoveMembers operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamRemoveMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostTeamRemoveMembersParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor ActorHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Actor", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Actor", Err: err})
+			return
+		}
+
+		params.XActor = &XActor
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamRemoveMembers(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostTeamRename operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamRename(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamRename(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetTeamSettings operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamSettingsParams
+
+	// ------------- Required query parameter "team_name" -------------
+
+	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamSettings(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostTeamSettings operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamSettings(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetUsersAbsences operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersAbsences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersAbsencesParams
+
+	// ------------- Required query parameter "user_id" -------------
+
+	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsersAbsences(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostUsersAddAbsence operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersAddAbsence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUsersAddAbsence(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostUsersDeleteAbsence operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersDeleteAbsence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUsersDeleteAbsence(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetUsersGetReview operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersGetReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersGetReviewParams
+
+	// ------------- Required query parameter "user_id" -------------
+
+	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsersGetReview(w, r, params)
+	}))
 
-// Переназначить конкретного ревьювера на другого из его команды
-// (POST /pullRequest/reassign)
-func (_ Unimplemented) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-// Создать команду с участниками (создаёт/обновляет пользователей)
-// (POST /team/add)
-func (_ Unimplemented) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// Получить команду с участниками
-// (GET /team/get)
-func (_ Unimplemented) GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+// PostUsersLinkIdentity operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersLinkIdentity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-// Получить PR'ы, где пользователь назначен ревьювером
-// (GET /users/getReview)
-func (_ Unimplemented) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUsersLinkIdentity(w, r)
+	}))
 
-// Установить флаг активности пользователя
-// (POST /users/setIsActive)
-func (_ Unimplemented) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type MiddlewareFunc func(http.Handler) http.Handler
+// PostUsersMoveTeam operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersMoveTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-// PostPullRequestCreate operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostUsersMoveTeamParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Actor" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Actor")]; found {
+		var XActor ActorHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Actor", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Actor", runtime.ParamLocationHeader, valueList[0], &XActor)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Actor", Err: err})
+			return
+		}
+
+		params.XActor = &XActor
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUsersMoveTeam(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetUsersNotificationPreferences operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersNotificationPreferences(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersNotificationPreferencesParams
+
+	// ------------- Required query parameter "user_id" -------------
+
+	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestCreate(w, r)
+		siw.Handler.GetUsersNotificationPreferences(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -106,12 +1367,12 @@ func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter,
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostPullRequestMerge operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+// PostUsersSetIsActive operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestMerge(w, r)
+		siw.Handler.PostUsersSetIsActive(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -121,12 +1382,12 @@ func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostPullRequestReassign operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+// PostUsersSetNotificationPreferences operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersSetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestReassign(w, r)
+		siw.Handler.PostUsersSetNotificationPreferences(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -136,12 +1397,12 @@ func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostTeamAdd operation middleware
-func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+// PostUsersSetWorkingHours operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersSetWorkingHours(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostTeamAdd(w, r)
+		siw.Handler.PostUsersSetWorkingHours(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -151,32 +1412,32 @@ func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetTeamGet operation middleware
-func (siw *ServerInterfaceWrapper) GetTeamGet(w http.ResponseWriter, r *http.Request) {
+// GetUsersTeamMoves operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersTeamMoves(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetTeamGetParams
+	var params GetUsersTeamMovesParams
 
-	// ------------- Required query parameter "team_name" -------------
+	// ------------- Required query parameter "user_id" -------------
 
-	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
 
 	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
 		return
 	}
 
-	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetTeamGet(w, r, params)
+		siw.Handler.GetUsersTeamMoves(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -186,32 +1447,85 @@ func (siw *ServerInterfaceWrapper) GetTeamGet(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetUsersGetReview operation middleware
-func (siw *ServerInterfaceWrapper) GetUsersGetReview(w http.ResponseWriter, r *http.Request) {
+// PostWebhooksCreate operation middleware
+func (siw *ServerInterfaceWrapper) PostWebhooksCreate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebhooksCreate(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetWebhooksDeadLetters operation middleware
+func (siw *ServerInterfaceWrapper) GetWebhooksDeadLetters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetWebhooksDeadLetters(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostWebhooksDelete operation middleware
+func (siw *ServerInterfaceWrapper) PostWebhooksDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebhooksDelete(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetWebhooksDeliveries operation middleware
+func (siw *ServerInterfaceWrapper) GetWebhooksDeliveries(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetUsersGetReviewParams
+	var params GetWebhooksDeliveriesParams
 
-	// ------------- Required query parameter "user_id" -------------
+	// ------------- Required query parameter "subscription_id" -------------
 
-	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+	if paramValue := r.URL.Query().Get("subscription_id"); paramValue != "" {
 
 	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "subscription_id"})
 		return
 	}
 
-	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
+	err = runtime.BindQueryParameter("form", true, true, "subscription_id", r.URL.Query(), &params.SubscriptionId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "subscription_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetUsersGetReview(w, r, params)
+		siw.Handler.GetWebhooksDeliveries(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -221,12 +1535,42 @@ func (siw *ServerInterfaceWrapper) GetUsersGetReview(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostUsersSetIsActive operation middleware
-func (siw *ServerInterfaceWrapper) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
+// GetWebhooksList operation middleware
+func (siw *ServerInterfaceWrapper) GetWebhooksList(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostUsersSetIsActive(w, r)
+		siw.Handler.GetWebhooksList(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostWebhooksRedeliver operation middleware
+func (siw *ServerInterfaceWrapper) PostWebhooksRedeliver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebhooksRedeliver(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostWebhooksUpdate operation middleware
+func (siw *ServerInterfaceWrapper) PostWebhooksUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebhooksUpdate(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -349,27 +1693,123 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/events/stream", wrapper.GetEventsStream)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/pullRequest/close", wrapper.PostPullRequestClose)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/pullRequest/create", wrapper.PostPullRequestCreate)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/pullRequest/get", wrapper.GetPullRequestGet)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/pullRequest/history", wrapper.GetPullRequestHistory)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/pullRequest/list", wrapper.GetPullRequestList)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/pullRequest/merge", wrapper.PostPullRequestMerge)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/pullRequest/ready", wrapper.PostPullRequestReady)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/pullRequest/reassign", wrapper.PostPullRequestReassign)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/pullRequest/reopen", wrapper.PostPullRequestReopen)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/pullRequest/review", wrapper.PostPullRequestReview)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/team/add", wrapper.PostTeamAdd)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/addMembers", wrapper.PostTeamAddMembers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/deactivateUsers", wrapper.PostTeamDeactivateUsers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/delete", wrapper.PostTeamDelete)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/team/get", wrapper.GetTeamGet)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/team/overdueReviews", wrapper.GetTeamOverdueReviews)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/removeMembers", wrapper.PostTeamRemoveMembers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/rename", wrapper.PostTeamRename)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/team/settings", wrapper.GetTeamSettings)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/team/settings", wrapper.PostTeamSettings)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users/absences", wrapper.GetUsersAbsences)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/addAbsence", wrapper.PostUsersAddAbsence)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/deleteAbsence", wrapper.PostUsersDeleteAbsence)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/users/getReview", wrapper.GetUsersGetReview)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/linkIdentity", wrapper.PostUsersLinkIdentity)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/moveTeam", wrapper.PostUsersMoveTeam)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users/notificationPreferences", wrapper.GetUsersNotificationPreferences)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/users/setIsActive", wrapper.PostUsersSetIsActive)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/setNotificationPreferences", wrapper.PostUsersSetNotificationPreferences)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/setWorkingHours", wrapper.PostUsersSetWorkingHours)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users/teamMoves", wrapper.GetUsersTeamMoves)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webhooks/create", wrapper.PostWebhooksCreate)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/webhooks/deadLetters", wrapper.GetWebhooksDeadLetters)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webhooks/delete", wrapper.PostWebhooksDelete)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/webhooks/deliveries", wrapper.GetWebhooksDeliveries)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/webhooks/list", wrapper.GetWebhooksList)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webhooks/redeliver", wrapper.PostWebhooksRedeliver)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/webhooks/update", wrapper.PostWebhooksUpdate)
+	})
 
 	return r
 }
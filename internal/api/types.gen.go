@@ -7,28 +7,160 @@ import (
 	"time"
 )
 
+// Defines values for AssignmentRecordAction.
+const (
+	AssignmentRecordActionASSIGNED   AssignmentRecordAction = "ASSIGNED"
+	AssignmentRecordActionREASSIGNED AssignmentRecordAction = "REASSIGNED"
+	AssignmentRecordActionRELEASED   AssignmentRecordAction = "RELEASED"
+	AssignmentRecordActionREMOVED    AssignmentRecordAction = "REMOVED"
+)
+
 // Defines values for ErrorResponseErrorCode.
 const (
-	NOCANDIDATE ErrorResponseErrorCode = "NO_CANDIDATE"
-	NOTASSIGNED ErrorResponseErrorCode = "NOT_ASSIGNED"
-	NOTFOUND    ErrorResponseErrorCode = "NOT_FOUND"
-	PREXISTS    ErrorResponseErrorCode = "PR_EXISTS"
-	PRMERGED    ErrorResponseErrorCode = "PR_MERGED"
-	TEAMEXISTS  ErrorResponseErrorCode = "TEAM_EXISTS"
+	ALREADYEXISTS ErrorResponseErrorCode = "ALREADY_EXISTS"
+	BADREQUEST    ErrorResponseErrorCode = "BAD_REQUEST"
+	INTERNAL      ErrorResponseErrorCode = "INTERNAL"
+	INVALIDSTATE  ErrorResponseErrorCode = "INVALID_STATE"
+	NOCANDIDATE   ErrorResponseErrorCode = "NO_CANDIDATE"
+	NOTAPPROVED   ErrorResponseErrorCode = "NOT_APPROVED"
+	NOTASSIGNED   ErrorResponseErrorCode = "NOT_ASSIGNED"
+	NOTFOUND      ErrorResponseErrorCode = "NOT_FOUND"
+	PREXISTS      ErrorResponseErrorCode = "PR_EXISTS"
+	PRMERGED      ErrorResponseErrorCode = "PR_MERGED"
+	TEAMEXISTS    ErrorResponseErrorCode = "TEAM_EXISTS"
+	UNAUTHORIZED  ErrorResponseErrorCode = "UNAUTHORIZED"
+)
+
+// Defines values for NotificationPreferencesMode.
+const (
+	Digest  NotificationPreferencesMode = "digest"
+	Instant NotificationPreferencesMode = "instant"
+	Mute    NotificationPreferencesMode = "mute"
 )
 
 // Defines values for PullRequestStatus.
 const (
+	PullRequestStatusCLOSED PullRequestStatus = "CLOSED"
+	PullRequestStatusDRAFT  PullRequestStatus = "DRAFT"
 	PullRequestStatusMERGED PullRequestStatus = "MERGED"
 	PullRequestStatusOPEN   PullRequestStatus = "OPEN"
 )
 
 // Defines values for PullRequestShortStatus.
 const (
+	PullRequestShortStatusCLOSED PullRequestShortStatus = "CLOSED"
+	PullRequestShortStatusDRAFT  PullRequestShortStatus = "DRAFT"
 	PullRequestShortStatusMERGED PullRequestShortStatus = "MERGED"
 	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
 )
 
+// Defines values for PullRequestSummaryStatus.
+const (
+	PullRequestSummaryStatusCLOSED PullRequestSummaryStatus = "CLOSED"
+	PullRequestSummaryStatusDRAFT  PullRequestSummaryStatus = "DRAFT"
+	PullRequestSummaryStatusMERGED PullRequestSummaryStatus = "MERGED"
+	PullRequestSummaryStatusOPEN   PullRequestSummaryStatus = "OPEN"
+)
+
+// Defines values for ReviewerReassignmentOutcome.
+const (
+	ReviewerReassignmentOutcomeREMOVED  ReviewerReassignmentOutcome = "REMOVED"
+	ReviewerReassignmentOutcomeREPLACED ReviewerReassignmentOutcome = "REPLACED"
+)
+
+// Defines values for ReviewerStateState.
+const (
+	ReviewerStateStateAPPROVED         ReviewerStateState = "APPROVED"
+	ReviewerStateStateCHANGESREQUESTED ReviewerStateState = "CHANGES_REQUESTED"
+	ReviewerStateStateCOMMENTED        ReviewerStateState = "COMMENTED"
+	ReviewerStateStatePENDING          ReviewerStateState = "PENDING"
+)
+
+// Defines values for TeamSettingsSlaAction.
+const (
+	AddReviewer TeamSettingsSlaAction = "add_reviewer"
+	Reassign    TeamSettingsSlaAction = "reassign"
+)
+
+// Defines values for TeamSettingsStrategy.
+const (
+	TeamSettingsStrategyLeastLoaded  TeamSettingsStrategy = "least_loaded"
+	TeamSettingsStrategyRandom       TeamSettingsStrategy = "random"
+	TeamSettingsStrategyRoundRobin   TeamSettingsStrategy = "round_robin"
+	TeamSettingsStrategyWorkingHours TeamSettingsStrategy = "working_hours"
+)
+
+// Defines values for WebhookDeliveryStatus.
+const (
+	Dead      WebhookDeliveryStatus = "dead"
+	Delivered WebhookDeliveryStatus = "delivered"
+	Pending   WebhookDeliveryStatus = "pending"
+)
+
+// Defines values for GetPullRequestListParamsStatus.
+const (
+	GetPullRequestListParamsStatusCLOSED GetPullRequestListParamsStatus = "CLOSED"
+	GetPullRequestListParamsStatusDRAFT  GetPullRequestListParamsStatus = "DRAFT"
+	GetPullRequestListParamsStatusMERGED GetPullRequestListParamsStatus = "MERGED"
+	GetPullRequestListParamsStatusOPEN   GetPullRequestListParamsStatus = "OPEN"
+)
+
+// Defines values for PostPullRequestReviewJSONBodyState.
+const (
+	PostPullRequestReviewJSONBodyStateAPPROVED         PostPullRequestReviewJSONBodyState = "APPROVED"
+	PostPullRequestReviewJSONBodyStateCHANGESREQUESTED PostPullRequestReviewJSONBodyState = "CHANGES_REQUESTED"
+	PostPullRequestReviewJSONBodyStateCOMMENTED        PostPullRequestReviewJSONBodyState = "COMMENTED"
+)
+
+// Defines values for PostUsersLinkIdentityJSONBodyPlatform.
+const (
+	Github PostUsersLinkIdentityJSONBodyPlatform = "github"
+	Gitlab PostUsersLinkIdentityJSONBodyPlatform = "gitlab"
+)
+
+// Absence defines model for Absence.
+type Absence struct {
+	AbsenceId int64     `json:"absence_id"`
+	CreatedAt time.Time `json:"created_at"`
+	EndsAt    time.Time `json:"ends_at"`
+
+	// Reason Отпуск, больничный и т.п.
+	Reason   string    `json:"reason"`
+	StartsAt time.Time `json:"starts_at"`
+	UserId   string    `json:"user_id"`
+}
+
+// AssignmentRecord defines model for AssignmentRecord.
+type AssignmentRecord struct {
+	// Action RELEASED — назначение закрыто вместе с PR (merge или close)
+	Action    AssignmentRecordAction `json:"action"`
+	Actor     string                 `json:"actor"`
+	CreatedAt time.Time              `json:"created_at"`
+	Id        int64                  `json:"id"`
+
+	// NewReviewerId Только для REASSIGNED — кто пришёл на замену reviewer_id
+	NewReviewerId *string `json:"new_reviewer_id"`
+	Reason        string  `json:"reason"`
+	ReviewerId    string  `json:"reviewer_id"`
+}
+
+// AssignmentRecordAction RELEASED — назначение закрыто вместе с PR (merge или close)
+type AssignmentRecordAction string
+
+// DeadLetter defines model for DeadLetter.
+type DeadLetter struct {
+	Attempts   int       `json:"attempts"`
+	CreatedAt  time.Time `json:"created_at"`
+	DeliveryId int64     `json:"delivery_id"`
+	Event      string    `json:"event"`
+	Id         int64     `json:"id"`
+	LastError  string    `json:"last_error"`
+
+	// Payload Тело, которое не удалось доставить
+	Payload        string `json:"payload"`
+	SubscriptionId int64  `json:"subscription_id"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Error struct {
@@ -40,32 +172,109 @@ type ErrorResponse struct {
 // ErrorResponseErrorCode defines model for ErrorResponse.Error.Code.
 type ErrorResponseErrorCode string
 
+// NotificationPreferences defines model for NotificationPreferences.
+type NotificationPreferences struct {
+	// Channel Личный канал в чате (@alice или id пользователя); пусто — канал команды
+	Channel string `json:"channel"`
+
+	// Mode instant — сообщение о каждом назначении сразу, digest — одним сообщением раз в NOTIFY_DIGEST_INTERVAL, mute — без уведомлений
+	Mode   NotificationPreferencesMode `json:"mode"`
+	UserId string                      `json:"user_id"`
+}
+
+// NotificationPreferencesMode instant — сообщение о каждом назначении сразу, digest — одним сообщением раз в NOTIFY_DIGEST_INTERVAL, mute — без уведомлений
+type NotificationPreferencesMode string
+
+// OverdueReview defines model for OverdueReview.
+type OverdueReview struct {
+	AssignedAt time.Time `json:"assigned_at"`
+	AuthorId   string    `json:"author_id"`
+
+	// Escalated По этому ревью уже добавлен дополнительный ревьювер (add_reviewer)
+	Escalated       bool   `json:"escalated"`
+	PullRequestId   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	ReviewerId      string `json:"reviewer_id"`
+}
+
 // PullRequest defines model for PullRequest.
 type PullRequest struct {
-	// AssignedReviewers user_id назначенных ревьюверов (0..2)
-	AssignedReviewers []string          `json:"assigned_reviewers"`
-	AuthorId          string            `json:"author_id"`
-	CreatedAt         *time.Time        `json:"createdAt"`
-	MergedAt          *time.Time        `json:"mergedAt"`
-	PullRequestId     string            `json:"pull_request_id"`
-	PullRequestName   string            `json:"pull_request_name"`
-	Status            PullRequestStatus `json:"status"`
+	// AssignedReviewers user_id назначенных ревьюверов (число задаётся настройками команды, по умолчанию до 2)
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	AuthorId          string     `json:"author_id"`
+	CreatedAt         *time.Time `json:"createdAt"`
+	MergedAt          *time.Time `json:"mergedAt"`
+	PullRequestId     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+
+	// Reviews Решения назначенных ревьюверов, в порядке assigned_reviewers
+	Reviews []ReviewerState `json:"reviews"`
+
+	// Status DRAFT → OPEN (ready) | CLOSED; OPEN → MERGED | CLOSED; CLOSED → OPEN (reopen). MERGED конечный.
+	Status PullRequestStatus `json:"status"`
 }
 
-// PullRequestStatus defines model for PullRequest.Status.
+// PullRequestStatus DRAFT → OPEN (ready) | CLOSED; OPEN → MERGED | CLOSED; CLOSED → OPEN (reopen). MERGED конечный.
 type PullRequestStatus string
 
 // PullRequestShort defines model for PullRequestShort.
 type PullRequestShort struct {
-	AuthorId        string                 `json:"author_id"`
-	PullRequestId   string                 `json:"pull_request_id"`
-	PullRequestName string                 `json:"pull_request_name"`
-	Status          PullRequestShortStatus `json:"status"`
+	AuthorId        string `json:"author_id"`
+	PullRequestId   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+
+	// Status DRAFT → OPEN (ready) | CLOSED; OPEN → MERGED | CLOSED; CLOSED → OPEN (reopen). MERGED конечный.
+	Status PullRequestShortStatus `json:"status"`
 }
 
-// PullRequestShortStatus defines model for PullRequestShort.Status.
+// PullRequestShortStatus DRAFT → OPEN (ready) | CLOSED; OPEN → MERGED | CLOSED; CLOSED → OPEN (reopen). MERGED конечный.
 type PullRequestShortStatus string
 
+// PullRequestSummary defines model for PullRequestSummary.
+type PullRequestSummary struct {
+	AssignedReviewers []string                 `json:"assigned_reviewers"`
+	AuthorId          string                   `json:"author_id"`
+	CreatedAt         time.Time                `json:"createdAt"`
+	MergedAt          *time.Time               `json:"mergedAt"`
+	PullRequestId     string                   `json:"pull_request_id"`
+	PullRequestName   string                   `json:"pull_request_name"`
+	Status            PullRequestSummaryStatus `json:"status"`
+}
+
+// PullRequestSummaryStatus defines model for PullRequestSummary.Status.
+type PullRequestSummaryStatus string
+
+// ReviewerReassignment defines model for ReviewerReassignment.
+type ReviewerReassignment struct {
+	OldUserId string `json:"old_user_id"`
+
+	// Outcome REMOVED — замены не нашлось, ревьювер просто снят с PR
+	Outcome       ReviewerReassignmentOutcome `json:"outcome"`
+	PullRequestId string                      `json:"pull_request_id"`
+
+	// ReplacedBy user_id нового ревьювера (только для REPLACED)
+	ReplacedBy *string `json:"replaced_by"`
+}
+
+// ReviewerReassignmentOutcome REMOVED — замены не нашлось, ревьювер просто снят с PR
+type ReviewerReassignmentOutcome string
+
+// ReviewerState defines model for ReviewerState.
+type ReviewerState struct {
+	// AssignedAt Когда назначен (при замене — время замены); от него считается SLA
+	AssignedAt *time.Time `json:"assigned_at"`
+
+	// IsFallback Ревьювер взят из запасной команды (fallback_teams), а не из команды автора
+	IsFallback bool   `json:"is_fallback"`
+	ReviewerId string `json:"reviewer_id"`
+
+	// State После переназначения новый ревьювер начинает с PENDING
+	State ReviewerStateState `json:"state"`
+}
+
+// ReviewerStateState После переназначения новый ревьювер начинает с PENDING
+type ReviewerStateState string
+
 // Team defines model for Team.
 type Team struct {
 	Members  []TeamMember `json:"members"`
@@ -74,41 +283,298 @@ type Team struct {
 
 // TeamMember defines model for TeamMember.
 type TeamMember struct {
-	IsActive bool   `json:"is_active"`
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+	IsActive bool `json:"is_active"`
+
+	// MaxOpenReviews Максимум одновременных ревью по OPEN PR (null — без ограничения)
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+	UserId         string `json:"user_id"`
+	Username       string `json:"username"`
+}
+
+// TeamMove defines model for TeamMove.
+type TeamMove struct {
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// FromTeam Пустое, если пользователь был без команды
+	FromTeam *string `json:"from_team"`
+
+	// HandoverReviews Открытые ревью переданы участникам прежней команды
+	HandoverReviews bool   `json:"handover_reviews"`
+	Id              int64  `json:"id"`
+	ToTeam          string `json:"to_team"`
+	UserId          string `json:"user_id"`
 }
 
+// TeamSettings defines model for TeamSettings.
+type TeamSettings struct {
+	// ChatChannel Канал команды в чате (например, #backend-reviews) для уведомлений о назначениях участникам без личного канала (null — канал вебхука по умолчанию) null — только показывать в /team/overdueReviews.
+	ChatChannel *string `json:"chat_channel"`
+
+	// FallbackTeams Запасные команды по порядку. Если в команде не хватает доступных ревьюверов, недостающие берутся из них и помечаются is_fallback.
+	FallbackTeams []string `json:"fallback_teams"`
+
+	// LeadUserId Тимлид команды (должен состоять в ней)
+	LeadUserId *string `json:"lead_user_id"`
+
+	// MinApprovals Сколько APPROVED нужно для merge (не больше reviewer_count)
+	MinApprovals int `json:"min_approvals"`
+
+	// RequireLead Всегда назначать тимлида ревьювером, если он не автор и доступен
+	RequireLead bool `json:"require_lead"`
+
+	// ReviewSlaMinutes Сколько минут ревью может оставаться в PENDING (0 — SLA не отслеживается)
+	ReviewSlaMinutes int `json:"review_sla_minutes"`
+
+	// ReviewerCount Сколько ревьюверов назначать на PR авторов команды
+	ReviewerCount int `json:"reviewer_count"`
+
+	// SlaAction Что делать с просроченным ревью: reassign — заменить ревьювера по правилам /pullRequest/reassign, add_reviewer — добавить ещё одного из команды.
+	SlaAction *TeamSettingsSlaAction `json:"sla_action"`
+
+	// Strategy Стратегия выбора ревьюверов (null — REVIEWER_STRATEGY сервиса)
+	Strategy *TeamSettingsStrategy `json:"strategy"`
+	TeamName string                `json:"team_name"`
+}
+
+// TeamSettingsSlaAction Что делать с просроченным ревью: reassign — заменить ревьювера по правилам /pullRequest/reassign, add_reviewer — добавить ещё одного из команды.
+type TeamSettingsSlaAction string
+
+// TeamSettingsStrategy Стратегия выбора ревьюверов (null — REVIEWER_STRATEGY сервиса)
+type TeamSettingsStrategy string
+
 // User defines model for User.
 type User struct {
-	IsActive bool   `json:"is_active"`
-	TeamName string `json:"team_name"`
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+	IsActive bool `json:"is_active"`
+
+	// MaxOpenReviews Максимум одновременных ревью по OPEN PR (null — без ограничения)
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+	TeamName       string `json:"team_name"`
+	UserId         string `json:"user_id"`
+	Username       string `json:"username"`
+
+	// WorkingHours null — не заданы, пользователь считается доступным всегда
+	WorkingHours *WorkingHours `json:"working_hours"`
+}
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	Attempts       int                   `json:"attempts"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	Event          string                `json:"event"`
+	Id             int64                 `json:"id"`
+	LastError      *string               `json:"last_error,omitempty"`
+	LastStatusCode *int                  `json:"last_status_code"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	SubscriptionId int64                 `json:"subscription_id"`
+}
+
+// WebhookDeliveryStatus defines model for WebhookDelivery.Status.
+type WebhookDeliveryStatus string
+
+// WebhookSubscription defines model for WebhookSubscription.
+type WebhookSubscription struct {
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Events pr.assigned, pr.reassigned, pr.merged; пустой список — все события
+	Events []string `json:"events"`
+	Id     int64    `json:"id"`
+	Url    string   `json:"url"`
 }
 
+// WorkingHours defines model for WorkingHours.
+type WorkingHours struct {
+	// End Конец рабочего дня, HH:MM (раньше start — окно через полночь)
+	End string `json:"end"`
+
+	// Start Начало рабочего дня по местному времени, HH:MM
+	Start string `json:"start"`
+
+	// TimeZone Часовой пояс IANA, например Europe/Moscow
+	TimeZone string `json:"time_zone"`
+}
+
+// ActorHeader defines model for ActorHeader.
+type ActorHeader = string
+
+// IfNoneMatchHeader defines model for IfNoneMatchHeader.
+type IfNoneMatchHeader = string
+
+// PullRequestIdQuery defines model for PullRequestIdQuery.
+type PullRequestIdQuery = string
+
 // TeamNameQuery defines model for TeamNameQuery.
 type TeamNameQuery = string
 
 // UserIdQuery defines model for UserIdQuery.
 type UserIdQuery = string
 
+// GetEventsStreamParams defines parameters for GetEventsStream.
+type GetEventsStreamParams struct {
+	// TeamName Только PR, автор которых в этой команде
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// UserId Только события, где пользователь автор PR, назначенный или снятый ревьюер; merge — и для всех ревьюеров PR
+	UserId *string `form:"user_id,omitempty" json:"user_id,omitempty"`
+
+	// LastEventID id последнего полученного события; поток начнётся со следующего
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// PostPullRequestCloseJSONBody defines parameters for PostPullRequestClose.
+type PostPullRequestCloseJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
+// PostPullRequestCloseParams defines parameters for PostPullRequestClose.
+type PostPullRequestCloseParams struct {
+	// XActor Кто выполняет операцию (пишется в историю назначений, по умолчанию system)
+	XActor *ActorHeader `json:"X-Actor,omitempty"`
+}
+
 // PostPullRequestCreateJSONBody defines parameters for PostPullRequestCreate.
 type PostPullRequestCreateJSONBody struct {
-	AuthorId        string `json:"author_id"`
+	AuthorId string `json:"author_id"`
+
+	// Draft Создать черновик (DRAFT) без ревьюверов; они назначаются в /pullRequest/ready
+	Draft           *bool  `json:"draft,omitempty"`
 	PullRequestId   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 }
 
+// PostPullRequestCreateParams defines parameters for PostPullRequestCreate.
+type PostPullRequestCreateParams struct {
+	// XActor Кто выполняет операцию (пишется в историю назначений, по умолчанию system)
+	XActor *ActorHeader `json:"X-Actor,omitempty"`
+}
+
+// GetPullRequestGetParams defines parameters for GetPullRequestGet.
+type GetPullRequestGetParams struct {
+	// PullRequestId Идентификатор PR
+	PullRequestId PullRequestIdQuery `form:"pull_request_id" json:"pull_request_id"`
+
+	// IfNoneMatch ETag из предыдущего ответа; если PR не изменился — 304 без тела
+	IfNoneMatch *IfNoneMatchHeader `json:"If-None-Match,omitempty"`
+}
+
+// GetPullRequestHistoryParams defines parameters for GetPullRequestHistory.
+type GetPullRequestHistoryParams struct {
+	// PullRequestId Идентификатор PR
+	PullRequestId PullRequestIdQuery `form:"pull_request_id" json:"pull_request_id"`
+}
+
+// GetPullRequestListParams defines parameters for GetPullRequestList.
+type GetPullRequestListParams struct {
+	Status   *GetPullRequestListParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+	AuthorId *string                         `form:"author_id,omitempty" json:"author_id,omitempty"`
+
+	// ReviewerId Назначенный сейчас ревьювер
+	ReviewerId *string `form:"reviewer_id,omitempty" json:"reviewer_id,omitempty"`
+
+	// TeamName Команда автора PR
+	TeamName    *string    `form:"team_name,omitempty" json:"team_name,omitempty"`
+	CreatedFrom *time.Time `form:"created_from,omitempty" json:"created_from,omitempty"`
+	CreatedTo   *time.Time `form:"created_to,omitempty" json:"created_to,omitempty"`
+	MergedFrom  *time.Time `form:"merged_from,omitempty" json:"merged_from,omitempty"`
+	MergedTo    *time.Time `form:"merged_to,omitempty" json:"merged_to,omitempty"`
+	Limit       *int       `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor next_cursor предыдущей страницы
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetPullRequestListParamsStatus defines parameters for GetPullRequestList.
+type GetPullRequestListParamsStatus string
+
 // PostPullRequestMergeJSONBody defines parameters for PostPullRequestMerge.
 type PostPullRequestMergeJSONBody struct {
 	PullRequestId string `json:"pull_request_id"`
 }
 
+// PostPullRequestMergeParams defines parameters for PostPullRequestMerge.
+type PostPullRequestMergeParams struct {
+	// XActor Кто выполняет операцию (пишется в историю назначений, по умолчанию system)
+	XActor *ActorHeader `json:"X-Actor,omitempty"`
+}
+
+// PostPullRequestReadyJSONBody defines parameters for PostPullRequestReady.
+type PostPullRequestReadyJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
+// PostPullRequestReadyParams defines parameters for PostPullRequestReady.
+type PostPullRequestReadyParams struct {
+	// XActor Кто выполняет операцию (пишется в историю назначений, по умолчанию system)
+	XActor *ActorHeader `json:"X-Actor,omitempty"`
+}
+
 // PostPullRequestReassignJSONBody defines parameters for PostPullRequestReassign.
 type PostPullRequestReassignJSONBody struct {
 	OldUserId     string `json:"old_user_id"`
 	PullRequestId string `json:"pull_request_id"`
+
+	// Reason Причина для истории назначений
+	Reason *string `json:"reason,omitempty"`
+}
+
+// PostPullRequestReassignParams defines parameters for PostPullRequestReassign.
+type PostPullRequestReassignParams struct {
+	// XActor Кто выполняет операцию (пишется в историю назначений, по умолчанию system)
+	XActor *ActorHeader `json:"X-Actor,omitempty"`
+}
+
+// PostPullRequestReopenJSONBody defines parameters for PostPullRequestReopen.
+type PostPullRequestReopenJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
+// PostPullRequestReopenParams defines parameters for PostPullRequestReopen.
+type PostPullRequestReopenParams struct {
+	// XActor Кто выполняет операцию (пишется в историю назначений, по умолчанию system)
+	XActor *ActorHeader `json:"X-Actor,omitempty"`
+}
+
+// PostPullRequestReviewJSONBody defines parameters for PostPullRequestReview.
+type PostPullRequestReviewJSONBody struct {
+	PullRequestId string                             `json:"pull_request_id"`
+	ReviewerId    string                             `json:"reviewer_id"`
+	State         PostPullRequestReviewJSONBodyState `json:"state"`
+}
+
+// PostPullRequestReviewJSONBodyState defines parameters for PostPullRequestReview.
+type PostPullRequestReviewJSONBodyState string
+
+// PostTeamAddMembersJSONBody defines parameters for PostTeamAddMembers.
+type PostTeamAddMembersJSONBody struct {
+	Members  []TeamMember `json:"members"`
+	TeamName string       `json:"team_name"`
+}
+
+// PostTeamDeactivateUsersJSONBody defines parameters for PostTeamDeactivateUsers.
+type PostTeamDeactivateUsersJSONBody struct {
+	// TeamName Деактивировать всю команду
+	TeamName *string   `json:"team_name,omitempty"`
+	UserIds  *[]string `json:"user_ids,omitempty"`
+}
+
+// PostTeamDeactivateUsersParams defines parameters for PostTeamDeactivateUsers.
+type PostTeamDeactivateUsersParams struct {
+	// XActor Кто выполняет операцию (пишется в историю назначений, по умолчанию system)
+	XActor *ActorHeader `json:"X-Actor,omitempty"`
+}
+
+// PostTeamDeleteJSONBody defines parameters for PostTeamDelete.
+type PostTeamDeleteJSONBody struct {
+	TeamName string `json:"team_name"`
+}
+
+// PostTeamDeleteParams defines parameters for PostTeamDelete.
+type PostTeamDeleteParams struct {
+	// XActor Кто выполняет операцию (пишется в историю назначений, по умолчанию system)
+	XActor *ActorHeader `json:"X-Actor,omitempty"`
 }
 
 // GetTeamGetParams defines parameters for GetTeamGet.
@@ -117,29 +583,253 @@ type GetTeamGetParams struct {
 	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
 }
 
+// GetTeamOverdueReviewsParams defines parameters for GetTeamOverdueReviews.
+type GetTeamOverdueReviewsParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+}
+
+// PostTeamRemoveMembersJSONBody defines parameters for PostTeamRemoveMembers.
+type PostTeamRemoveMembersJSONBody struct {
+	TeamName string   `json:"team_name"`
+	UserIds  []string `json:"user_ids"`
+}
+
+// PostTeamRemoveMembersParams defines parameters for PostTeamRemoveMembers.
+type PostTeamRemoveMembersParams struct {
+	// XActor Кто выполняет операцию (пишется в историю назначений, по умолчанию system)
+	XActor *ActorHeader `json:"X-Actor,omitempty"`
+}
+
+// PostTeamRenameJSONBody defines parameters for PostTeamRename.
+type PostTeamRenameJSONBody struct {
+	NewTeamName string `json:"new_team_name"`
+	TeamName    string `json:"team_name"`
+}
+
+// GetTeamSettingsParams defines parameters for GetTeamSettings.
+type GetTeamSettingsParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+}
+
+// PostTeamSettingsJSONBody defines parameters for PostTeamSettings.
+type PostTeamSettingsJSONBody struct {
+	// ChatChannel Пустая строка — канал вебхука по умолчанию
+	ChatChannel *string `json:"chat_channel,omitempty"`
+
+	// FallbackTeams Заменяет список целиком; пустой массив — без запасных команд
+	FallbackTeams *[]string `json:"fallback_teams,omitempty"`
+
+	// LeadUserId Пустая строка — снять тимлида
+	LeadUserId       *string `json:"lead_user_id,omitempty"`
+	MinApprovals     *int    `json:"min_approvals,omitempty"`
+	RequireLead      *bool   `json:"require_lead,omitempty"`
+	ReviewSlaMinutes *int    `json:"review_sla_minutes,omitempty"`
+	ReviewerCount    *int    `json:"reviewer_count,omitempty"`
+
+	// SlaAction reassign или add_reviewer; пустая строка — без автоматических действий
+	SlaAction *string `json:"sla_action,omitempty"`
+
+	// Strategy random, round_robin, least_loaded или working_hours; пустая строка — стратегия сервиса
+	Strategy *string `json:"strategy,omitempty"`
+	TeamName string  `json:"team_name"`
+}
+
+// GetUsersAbsencesParams defines parameters for GetUsersAbsences.
+type GetUsersAbsencesParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
+// PostUsersAddAbsenceJSONBody defines parameters for PostUsersAddAbsence.
+type PostUsersAddAbsenceJSONBody struct {
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   *string   `json:"reason,omitempty"`
+	StartsAt time.Time `json:"starts_at"`
+	UserId   string    `json:"user_id"`
+}
+
+// PostUsersDeleteAbsenceJSONBody defines parameters for PostUsersDeleteAbsence.
+type PostUsersDeleteAbsenceJSONBody struct {
+	AbsenceId int64 `json:"absence_id"`
+}
+
 // GetUsersGetReviewParams defines parameters for GetUsersGetReview.
 type GetUsersGetReviewParams struct {
 	// UserId Идентификатор пользователя
 	UserId UserIdQuery `form:"user_id" json:"user_id"`
 }
 
+// PostUsersLinkIdentityJSONBody defines parameters for PostUsersLinkIdentity.
+type PostUsersLinkIdentityJSONBody struct {
+	Login    string                                `json:"login"`
+	Platform PostUsersLinkIdentityJSONBodyPlatform `json:"platform"`
+	UserId   string                                `json:"user_id"`
+}
+
+// PostUsersLinkIdentityJSONBodyPlatform defines parameters for PostUsersLinkIdentity.
+type PostUsersLinkIdentityJSONBodyPlatform string
+
+// PostUsersMoveTeamJSONBody defines parameters for PostUsersMoveTeam.
+type PostUsersMoveTeamJSONBody struct {
+	HandoverReviews *bool `json:"handover_reviews,omitempty"`
+
+	// TeamName Команда, в которую переводится пользователь
+	TeamName string `json:"team_name"`
+	UserId   string `json:"user_id"`
+}
+
+// PostUsersMoveTeamParams defines parameters for PostUsersMoveTeam.
+type PostUsersMoveTeamParams struct {
+	// XActor Кто выполняет операцию (пишется в историю назначений, по умолчанию system)
+	XActor *ActorHeader `json:"X-Actor,omitempty"`
+}
+
+// GetUsersNotificationPreferencesParams defines parameters for GetUsersNotificationPreferences.
+type GetUsersNotificationPreferencesParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
 // PostUsersSetIsActiveJSONBody defines parameters for PostUsersSetIsActive.
 type PostUsersSetIsActiveJSONBody struct {
 	IsActive bool   `json:"is_active"`
 	UserId   string `json:"user_id"`
 }
 
+// PostUsersSetNotificationPreferencesJSONBody defines parameters for PostUsersSetNotificationPreferences.
+type PostUsersSetNotificationPreferencesJSONBody struct {
+	// Channel Личный канал; пустая строка или нет поля — канал команды
+	Channel *string `json:"channel,omitempty"`
+
+	// Mode instant, digest или mute
+	Mode   string `json:"mode"`
+	UserId string `json:"user_id"`
+}
+
+// PostUsersSetWorkingHoursJSONBody defines parameters for PostUsersSetWorkingHours.
+type PostUsersSetWorkingHoursJSONBody struct {
+	End      *string `json:"end,omitempty"`
+	Start    *string `json:"start,omitempty"`
+	TimeZone string  `json:"time_zone"`
+	UserId   string  `json:"user_id"`
+}
+
+// GetUsersTeamMovesParams defines parameters for GetUsersTeamMoves.
+type GetUsersTeamMovesParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
+// PostWebhooksCreateJSONBody defines parameters for PostWebhooksCreate.
+type PostWebhooksCreateJSONBody struct {
+	// Events pr.assigned, pr.reassigned, pr.merged; пустой список — все события
+	Events *[]string `json:"events,omitempty"`
+
+	// Secret Если не задан — генерируется
+	Secret *string `json:"secret,omitempty"`
+	Url    string  `json:"url"`
+}
+
+// PostWebhooksDeleteJSONBody defines parameters for PostWebhooksDelete.
+type PostWebhooksDeleteJSONBody struct {
+	Id int64 `json:"id"`
+}
+
+// GetWebhooksDeliveriesParams defines parameters for GetWebhooksDeliveries.
+type GetWebhooksDeliveriesParams struct {
+	SubscriptionId int64 `form:"subscription_id" json:"subscription_id"`
+	Limit          *int  `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostWebhooksRedeliverJSONBody defines parameters for PostWebhooksRedeliver.
+type PostWebhooksRedeliverJSONBody struct {
+	DeadLetterId int64 `json:"dead_letter_id"`
+}
+
+// PostWebhooksUpdateJSONBody defines parameters for PostWebhooksUpdate.
+type PostWebhooksUpdateJSONBody struct {
+	Active *bool `json:"active,omitempty"`
+
+	// Events pr.assigned, pr.reassigned, pr.merged; пустой список — все события
+	Events *[]string `json:"events,omitempty"`
+	Id     int64     `json:"id"`
+	Secret *string   `json:"secret,omitempty"`
+	Url    *string   `json:"url,omitempty"`
+}
+
+// PostPullRequestCloseJSONRequestBody defines body for PostPullRequestClose for application/json ContentType.
+type PostPullRequestCloseJSONRequestBody PostPullRequestCloseJSONBody
+
 // PostPullRequestCreateJSONRequestBody defines body for PostPullRequestCreate for application/json ContentType.
 type PostPullRequestCreateJSONRequestBody PostPullRequestCreateJSONBody
 
 // PostPullRequestMergeJSONRequestBody defines body for PostPullRequestMerge for application/json ContentType.
 type PostPullRequestMergeJSONRequestBody PostPullRequestMergeJSONBody
 
+// PostPullRequestReadyJSONRequestBody defines body for PostPullRequestReady for application/json ContentType.
+type PostPullRequestReadyJSONRequestBody PostPullRequestReadyJSONBody
+
 // PostPullRequestReassignJSONRequestBody defines body for PostPullRequestReassign for application/json ContentType.
 type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
 
+// PostPullRequestReopenJSONRequestBody defines body for PostPullRequestReopen for application/json ContentType.
+type PostPullRequestReopenJSONRequestBody PostPullRequestReopenJSONBody
+
+// PostPullRequestReviewJSONRequestBody defines body for PostPullRequestReview for application/json ContentType.
+type PostPullRequestReviewJSONRequestBody PostPullRequestReviewJSONBody
+
 // PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
 type PostTeamAddJSONRequestBody = Team
 
+// PostTeamAddMembersJSONRequestBody defines body for PostTeamAddMembers for application/json ContentType.
+type PostTeamAddMembersJSONRequestBody PostTeamAddMembersJSONBody
+
+// PostTeamDeactivateUsersJSONRequestBody defines body for PostTeamDeactivateUsers for application/json ContentType.
+type PostTeamDeactivateUsersJSONRequestBody PostTeamDeactivateUsersJSONBody
+
+// PostTeamDeleteJSONRequestBody defines body for PostTeamDelete for application/json ContentType.
+type PostTeamDeleteJSONRequestBody PostTeamDeleteJSONBody
+
+// PostTeamRemoveMembersJSONRequestBody defines body for PostTeamRemoveMembers for application/json ContentType.
+type PostTeamRemoveMembersJSONRequestBody PostTeamRemoveMembersJSONBody
+
+// PostTeamRenameJSONRequestBody defines body for PostTeamRename for application/json ContentType.
+type PostTeamRenameJSONRequestBody PostTeamRenameJSONBody
+
+// PostTeamSettingsJSONRequestBody defines body for PostTeamSettings for application/json ContentType.
+type PostTeamSettingsJSONRequestBody PostTeamSettingsJSONBody
+
+// PostUsersAddAbsenceJSONRequestBody defines body for PostUsersAddAbsence for application/json ContentType.
+type PostUsersAddAbsenceJSONRequestBody PostUsersAddAbsenceJSONBody
+
+// PostUsersDeleteAbsenceJSONRequestBody defines body for PostUsersDeleteAbsence for application/json ContentType.
+type PostUsersDeleteAbsenceJSONRequestBody PostUsersDeleteAbsenceJSONBody
+
+// PostUsersLinkIdentityJSONRequestBody defines body for PostUsersLinkIdentity for application/json ContentType.
+type PostUsersLinkIdentityJSONRequestBody PostUsersLinkIdentityJSONBody
+
+// PostUsersMoveTeamJSONRequestBody defines body for PostUsersMoveTeam for application/json ContentType.
+type PostUsersMoveTeamJSONRequestBody PostUsersMoveTeamJSONBody
+
 // PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
 type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
+
+// PostUsersSetNotificationPreferencesJSONRequestBody defines body for PostUsersSetNotificationPreferences for application/json ContentType.
+type PostUsersSetNotificationPreferencesJSONRequestBody PostUsersSetNotificationPreferencesJSONBody
+
+// PostUsersSetWorkingHoursJSONRequestBody defines body for PostUsersSetWorkingHours for application/json ContentType.
+type PostUsersSetWorkingHoursJSONRequestBody PostUsersSetWorkingHoursJSONBody
+
+// PostWebhooksCreateJSONRequestBody defines body for PostWebhooksCreate for application/json ContentType.
+type PostWebhooksCreateJSONRequestBody PostWebhooksCreateJSONBody
+
+// PostWebhooksDeleteJSONRequestBody defines body for PostWebhooksDelete for application/json ContentType.
+type PostWebhooksDeleteJSONRequestBody PostWebhooksDeleteJSONBody
+
+// PostWebhooksRedeliverJSONRequestBody defines body for PostWebhooksRedeliver for application/json ContentType.
+type PostWebhooksRedeliverJSONRequestBody PostWebhooksRedeliverJSONBody
+
+// PostWebhooksUpdateJSONRequestBody defines body for PostWebhooksUpdate for application/json ContentType.
+type PostWebhooksUpdateJSONRequestBody PostWebhooksUpdateJSONBody
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/webhook"
+)
+
+// maxWebhookBody — предел тела вебхука; GitHub присылает до 25 МБ, но события
+// pull_request заметно меньше.
+const maxWebhookBody = 5 << 20
+
+// Вебхуки принимают тело платформы как есть (подпись считается по сырым байтам),
+// поэтому они не описаны в openapi и регистрируются отдельно, как /stats.
+
+func (h *ApiHandler) CustomPostGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	h.handleWebhook(w, r, webhook.GitHub, h.GitHubWebhookSecret)
+}
+
+func (h *ApiHandler) CustomPostGitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	h.handleWebhook(w, r, webhook.GitLab, h.GitLabWebhookSecret)
+}
+
+func (h *ApiHandler) handleWebhook(w http.ResponseWriter, r *http.Request, parse webhook.Parser, secret string) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		h.writeError(w, BADREQUEST, "cannot read body", http.StatusBadRequest)
+		return
+	}
+	ev, err := parse(r.Header, body, secret)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	response := struct {
+		Action        *string      `json:"action"`
+		PullRequestId *string      `json:"pull_request_id"`
+		Applied       bool         `json:"applied"`
+		PR            *PullRequest `json:"pr,omitempty"`
+	}{}
+	if ev != nil {
+		pr, applied, err := h.WebhookService.Apply(r.Context(), *ev)
+		if err != nil {
+			h.writeDomainError(w, fmt.Errorf("%s %s: %w", ev.Action, ev.PRID, err))
+			return
+		}
+		resp := mapPRToResponse(pr)
+		response.Action, response.PullRequestId = &ev.Action, &ev.PRID
+		response.Applied, response.PR = applied, &resp
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,27 @@
+// Package domain содержит ошибки предметной области. Репозитории и сервисы
+// возвращают их (часто обёрнутыми через %w), а HTTP-слой сопоставляет им
+// код ErrorResponse и статус через errors.Is, не глядя на текст.
+package domain
+
+import "errors"
+
+// Ошибки хранилища
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+)
+
+// Нарушения доменных правил
+var (
+	ErrTeamExists   = errors.New("team already exists")
+	ErrPRExists     = errors.New("pull request already exists")
+	ErrPRMerged     = errors.New("pull request is merged")
+	ErrInvalidState = errors.New("operation is not allowed in current pull request status")
+	ErrNotAssigned  = errors.New("reviewer is not assigned to this pull request")
+	ErrNotApproved  = errors.New("not enough approvals to merge")
+	ErrNoCandidate  = errors.New("no active replacement candidate in team")
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+// ErrUnauthorized — запрос не прошёл проверку подлинности (например, подпись вебхука).
+var ErrUnauthorized = errors.New("unauthorized")
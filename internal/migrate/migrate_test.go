@@ -0,0 +1,161 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_users.up.sql":   {Data: []byte("CREATE TABLE users ()")},
+		"0002_users.down.sql": {Data: []byte("DROP TABLE users")},
+		"0001_init.up.sql":    {Data: []byte("CREATE TABLE teams ()")},
+		"README.md":           {Data: []byte("not a migration")},
+		"0003_dir.up.sql/x":   {Data: []byte("")},
+	}
+	migs, err := load(fsys)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(migs) != 2 || migs[0].Version != 1 || migs[1].Version != 2 {
+		t.Fatalf("migrations: %+v", migs)
+	}
+	if migs[0].Name != "init" || migs[0].Down != "" || migs[1].Down != "DROP TABLE users" {
+		t.Fatalf("parsed: %+v", migs)
+	}
+
+	bad := map[string]fstest.MapFS{
+		"no up file": {"0001_init.down.sql": {Data: []byte("DROP TABLE teams")}},
+		"two names": {
+			"0001_init.up.sql":  {Data: []byte("SELECT 1")},
+			"0001_other.up.sql": {Data: []byte("SELECT 1")},
+		},
+	}
+	for name, fsys := range bad {
+		if _, err := load(fsys); err == nil {
+			t.Fatalf("%s: want error", name)
+		}
+	}
+}
+
+// testPool подключается к TEST_DATABASE_URL и работает в отдельной схеме,
+// чтобы не мешать тестам репозиториев на той же базе.
+func testPool(t *testing.T) *pgxpool.Pool {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	admin, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(admin.Close)
+
+	schema := fmt.Sprintf("migrate_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { _, _ = admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE") })
+
+	cfg, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func applied(statuses []Status) []int64 {
+	var out []int64
+	for _, s := range statuses {
+		if s.AppliedAt != nil {
+			out = append(out, s.Version)
+		}
+	}
+	return out
+}
+
+func TestUpDownStatus(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	m := &Migrator{pool: pool, migrations: []Migration{
+		{Version: 1, Name: "teams", Up: "CREATE TABLE teams (name TEXT)", Down: "DROP TABLE teams"},
+		{Version: 2, Name: "users", Up: "CREATE TABLE users (id TEXT)", Down: "DROP TABLE users"},
+	}}
+
+	// Status на пустой базе ничего не создаёт
+	statuses, err := m.Status(ctx)
+	if err != nil || len(statuses) != 2 || len(applied(statuses)) != 0 {
+		t.Fatalf("status before up: %+v, %v", statuses, err)
+	}
+	var exists bool
+	if err := pool.QueryRow(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil || exists {
+		t.Fatalf("status created schema_migrations: %v, %v", exists, err)
+	}
+
+	if migs, err := m.Up(ctx); err != nil || len(migs) != 2 {
+		t.Fatalf("up: %+v, %v", migs, err)
+	}
+	if migs, err := m.Up(ctx); err != nil || len(migs) != 0 {
+		t.Fatalf("second up: %+v, %v", migs, err)
+	}
+	if statuses, err := m.Status(ctx); err != nil || fmt.Sprint(applied(statuses)) != "[1 2]" {
+		t.Fatalf("status after up: %+v, %v", statuses, err)
+	}
+
+	if migs, err := m.Down(ctx, 1); err != nil || len(migs) != 1 || migs[0].Version != 2 {
+		t.Fatalf("down: %+v, %v", migs, err)
+	}
+	if statuses, err := m.Status(ctx); err != nil || fmt.Sprint(applied(statuses)) != "[1]" {
+		t.Fatalf("status after down: %+v, %v", statuses, err)
+	}
+	if err := pool.QueryRow(ctx, "SELECT to_regclass('users') IS NOT NULL").Scan(&exists); err != nil || exists {
+		t.Fatalf("users after down: %v, %v", exists, err)
+	}
+
+	// Падающая миграция откатывается целиком и не отмечается применённой
+	m.migrations = append(m.migrations, Migration{Version: 3, Name: "broken", Up: "CREATE TABLE ok (id TEXT); SELECT broken"})
+	if _, err := m.Up(ctx); err == nil || !strings.Contains(err.Error(), "3_broken") {
+		t.Fatalf("broken up: %v", err)
+	}
+	if statuses, err := m.Status(ctx); err != nil || fmt.Sprint(applied(statuses)) != "[1 2]" {
+		t.Fatalf("status after failed up: %+v, %v", statuses, err)
+	}
+	if err := pool.QueryRow(ctx, "SELECT to_regclass('ok') IS NOT NULL").Scan(&exists); err != nil || exists {
+		t.Fatalf("failed migration left a table: %v, %v", exists, err)
+	}
+}
+
+// Все миграции проекта накатываются, откатываются и накатываются снова.
+func TestProjectMigrations(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	m, err := New(pool, os.DirFS("../../migrations"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	up, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if down, err := m.Down(ctx, len(up)); err != nil || len(down) != len(up) {
+		t.Fatalf("down: %d of %d, %v", len(down), len(up), err)
+	}
+	if again, err := m.Up(ctx); err != nil || len(again) != len(up) {
+		t.Fatalf("up again: %d of %d, %v", len(again), len(up), err)
+	}
+}
@@ -1,29 +1,489 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type User struct {
 	ID       string
 	Name     string
 	IsActive bool
 	TeamName string
+	// nil — без ограничения
+	MaxOpenReviews *int
+	// nil — не заданы, считается доступным всегда
+	WorkingHours *WorkingHours
+}
+
+// WorkingHours — рабочее окно пользователя в его часовом поясе. Start и End —
+// минуты от полуночи; End < Start — окно через полночь.
+type WorkingHours struct {
+	TimeZone string
+	Start    int
+	End      int
+}
+
+// Contains сообщает, рабочее ли сейчас время по t. Неизвестный пояс считается UTC.
+func (w WorkingHours) Contains(t time.Time) bool {
+	return w.ContainsIn(t, w.Location())
+}
+
+// Location загружает часовой пояс окна; неизвестный — UTC.
+func (w WorkingHours) Location() *time.Location {
+	if loc, err := time.LoadLocation(w.TimeZone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// ContainsIn — Contains с уже загруженным поясом окна loc.
+func (w WorkingHours) ContainsIn(t time.Time, loc *time.Location) bool {
+	t = t.In(loc)
+	m := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return m >= w.Start && m < w.End
+	}
+	return m >= w.Start || m < w.End
 }
 
 type Team struct {
 	Name string
 }
 
+// TeamSettings — политика назначения ревьюеров для PR авторов команды.
+type TeamSettings struct {
+	TeamName      string
+	ReviewerCount int
+	// Сколько APPROVED нужно для merge
+	MinApprovals int
+	// Тимлид команды; при RequireLead он всегда среди ревьюеров (если не автор)
+	LeadUserID  string
+	RequireLead bool
+	// Пусто — стратегия по умолчанию (REVIEWER_STRATEGY)
+	Strategy string
+	// Запасные команды по порядку: из них добираются недостающие ревьюеры
+	FallbackTeams []string
+	// Сколько ревью может висеть в PENDING; 0 — SLA не отслеживается
+	ReviewSLA time.Duration
+	// Что делать при нарушении SLA; пусто — только показывать в списке просроченных
+	SLAAction string
+	// Канал команды в чате для уведомлений о назначениях; пусто — канал вебхука по умолчанию
+	ChatChannel string
+}
+
+// Действия при нарушении SLA ревью.
+const (
+	SLAActionReassign    = "reassign"
+	SLAActionAddReviewer = "add_reviewer"
+)
+
+// Причины, по которым при создании PR назначено меньше ревьюеров, чем нужно.
+const (
+	ShortageNotEnoughTeammates = "NOT_ENOUGH_TEAMMATES"
+	ShortageAtCapacity         = "REVIEWERS_AT_CAPACITY"
+)
+
+// Статусы PR. Допустимые переходы проверяет PRService.
+const (
+	StatusDraft  = "DRAFT"
+	StatusOpen   = "OPEN"
+	StatusMerged = "MERGED"
+	StatusClosed = "CLOSED"
+)
+
+// Решения ревьюеров. Новое назначение (в том числе замена) начинается с PENDING.
+const (
+	ReviewPending          = "PENDING"
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	ReviewCommented        = "COMMENTED"
+)
+
 type PullRequest struct {
 	ID        string
 	Title     string
 	AuthorID  string
 	Status    string
 	Reviewers []string
+	// Решение по каждому ревьюеру; нет записи — PENDING
+	ReviewStates map[string]string
+	// Ревьюеры, взятые из запасной команды
+	Fallback map[string]bool
+	// Когда назначен каждый ревьюер (при замене — время замены)
+	AssignedAt map[string]time.Time
+	// Ревьюеры, по которым уже выполнена SLA-эскалация
+	Escalated map[string]bool
 	CreatedAt time.Time
 	MergedAt  *time.Time
+	// Заполняется только при назначении, если ревьюеров не хватило: репозиторий
+	// отмечает ShortageAtCapacity, сервис — остальные причины
+	ReviewerShortage string
+}
+
+// ReviewState возвращает решение ревьюера по PR.
+func (pr *PullRequest) ReviewState(reviewerID string) string {
+	if state, ok := pr.ReviewStates[reviewerID]; ok {
+		return state
+	}
+	return ReviewPending
+}
+
+// Approvals — сколько текущих ревьюеров одобрили PR.
+func (pr *PullRequest) Approvals() int {
+	n := 0
+	for _, id := range pr.Reviewers {
+		if pr.ReviewState(id) == ReviewApproved {
+			n++
+		}
+	}
+	return n
+}
+
+type ReviewCandidate struct {
+	UserID         string
+	OpenReviews    int
+	MaxOpenReviews *int
+	WorkingHours   *WorkingHours
+}
+
+// Working — рабочее ли у кандидата время в момент t (без заданных часов — да).
+func (c ReviewCandidate) Working(t time.Time) bool {
+	return c.WorkingHours == nil || c.WorkingHours.Contains(t)
+}
+
+func (c ReviewCandidate) AtCapacity() bool {
+	return c.MaxOpenReviews != nil && c.OpenReviews >= *c.MaxOpenReviews
+}
+
+// Действия в истории назначений ревьюеров.
+const (
+	ActionAssigned   = "ASSIGNED"
+	ActionReassigned = "REASSIGNED"
+	ActionRemoved    = "REMOVED"
+	// Назначение закрыто вместе с PR (merge или close)
+	ActionReleased = "RELEASED"
+)
+
+// Audit — кто и почему меняет назначения; пишется в историю.
+type Audit struct {
+	Actor  string
+	Reason string
+}
+
+type AssignmentRecord struct {
+	ID         int64
+	PRID       string
+	ReviewerID string
+	// Только для REASSIGNED: кто пришёл на замену
+	NewReviewerID string
+	Action        string
+	Actor         string
+	Reason        string
+	CreatedAt     time.Time
+}
+
+// AffectedReview — открытый PR, где ревьюер попал под деактивацию.
+type AffectedReview struct {
+	PRID       string
+	AuthorID   string
+	ReviewerID string
+	// Команда автора PR; пусто — автор без команды
+	AuthorTeam string
+	// Ревьюер пришёл из запасной команды
+	Fallback  bool
+	Reviewers []string
+}
+
+// ReplacementTeams — где по порядку искать замену ревьюеру PR: в команде автора,
+// а запасному ревьюеру — в запасных командах из settings команды автора (nil — их нет).
+func ReplacementTeams(authorTeam string, fallback bool, settings *TeamSettings) []string {
+	switch {
+	case authorTeam == "":
+		return nil
+	case !fallback:
+		return []string{authorTeam}
+	case settings == nil:
+		return nil
+	}
+	return settings.FallbackTeams
+}
+
+type ReviewerChange struct {
+	PRID          string
+	OldReviewerID string
+	// Пусто, если замены не нашлось и ревьюер просто снят
+	NewReviewerID string
+}
+
+type DeactivationResult struct {
+	Deactivated []string
+	Changes     []ReviewerChange
+}
+
+// TeamMove — запись о переводе пользователя в другую команду. Названия команд
+// сохраняются как были на момент перевода.
+type TeamMove struct {
+	ID       int64
+	UserID   string
+	FromTeam string
+	ToTeam   string
+	// Открытые ревью переданы участникам прежней команды
+	Handover  bool
+	Actor     string
+	CreatedAt time.Time
+}
+
+// Absence — период отсутствия (отпуск, больничный). Пока он идёт, пользователь
+// не кандидат в ревьюеры.
+type Absence struct {
+	ID       int64
+	UserID   string
+	StartsAt time.Time
+	EndsAt   time.Time
+	Reason   string
+	// Открытые ревью уже переназначены при начале отсутствия
+	Handled   bool
+	CreatedAt time.Time
+}
+
+// Платформы, с которых приходят вебхуки о PR.
+const (
+	PlatformGitHub = "github"
+	PlatformGitLab = "gitlab"
+)
+
+// События PR на платформе, которые меняют его жизненный цикл у нас.
+const (
+	PREventOpened   = "opened"
+	PREventReady    = "ready"
+	PREventMerged   = "merged"
+	PREventClosed   = "closed"
+	PREventReopened = "reopened"
+)
+
+// PREvent — событие PR (merge request) из вебхука платформы. PRID строится из
+// репозитория и номера PR, логины — платформенные, а не наши user_id.
+type PREvent struct {
+	Platform    string
+	Action      string
+	PRID        string
+	Title       string
+	AuthorLogin string
+	Draft       bool
+	// Кто выполнил действие на платформе
+	SenderLogin string
+}
+
+// OverdueReview — ревью в PENDING дольше SLA команды автора PR.
+type OverdueReview struct {
+	PRID       string
+	Title      string
+	AuthorID   string
+	TeamName   string
+	ReviewerID string
+	AssignedAt time.Time
+	Escalated  bool
+}
+
+// Escalation — что сделано с просроченным ревью. Для reassign ReviewerID заменён
+// на NewReviewerID, для add_reviewer NewReviewerID добавлен к нему. Пустой
+// NewReviewerID — кандидатов не нашлось, ревью только помечено эскалированным.
+type Escalation struct {
+	PRID          string
+	ReviewerID    string
+	Action        string
+	NewReviewerID string
+}
+
+// PRFilter — условия выборки PR для списка. Пустые поля не фильтруют; границы
+// периодов: From включительно, To — нет. Порядок — от новых к старым.
+type PRFilter struct {
+	Status      string
+	AuthorID    string
+	ReviewerID  string
+	TeamName    string // команда автора
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+	// After — последний PR предыдущей страницы
+	After *PRCursor
+	Limit int
+}
+
+// PRCursor — позиция в списке PR: (CreatedAt, ID) последнего выданного.
+type PRCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// События для внешних подписчиков (исходящие вебхуки); строятся из событий outbox.
+const (
+	// Ревьюер назначен (reviewer.assigned): создание PR, ready, reopen, SLA add_reviewer
+	EventPRAssigned = "pr.assigned"
+	// Ревьюер заменён (reviewer.replaced; new_reviewer_id пустой — снят без замены)
+	EventPRReassigned = "pr.reassigned"
+	EventPRMerged     = "pr.merged"
+)
+
+// WebhookSubscription — подписка внешнего сервиса на события. Events пустой — все события.
+type WebhookSubscription struct {
+	ID        int64
+	URL       string
+	Secret    string
+	Events    []string
+	Active    bool
+	CreatedAt time.Time
+}
+
+// Wants сообщает, нужно ли подписке событие eventType.
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	if !s.Active {
+		return false
+	}
+	return len(s.Events) == 0 || slices.Contains(s.Events, eventType)
+}
+
+// Статусы доставки вебхука.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	// Попытки исчерпаны, доставка перенесена в dead letters
+	DeliveryDead = "dead"
+)
+
+// WebhookDelivery — доставка одного события одной подписке; она же запись журнала.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	// Событие outbox, из которого создана доставка
+	EventID       int64
+	Event         string
+	Payload       []byte
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	// Код ответа последней попытки; nil — ответа не было
+	LastStatusCode *int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+
+	// Заполняются при выборке к отправке
+	URL    string
+	Secret string
+}
+
+// DeadLetter — доставка, для которой исчерпаны попытки.
+type DeadLetter struct {
+	ID             int64
+	DeliveryID     int64
+	SubscriptionID int64
+	Event          string
+	Payload        []byte
+	Attempts       int
+	LastError      string
+	CreatedAt      time.Time
+}
+
+// Доменные события. Пишутся в outbox в одной транзакции с изменением, поэтому
+// не теряются, если процесс упадёт после commit.
+const (
+	OutboxPRCreated        = "pr.created"
+	OutboxReviewerAssigned = "reviewer.assigned"
+	// NewReviewerID пустой — ревьюер снят без замены
+	OutboxReviewerReplaced = "reviewer.replaced"
+	OutboxPRMerged         = "pr.merged"
+	OutboxUserDeactivated  = "user.deactivated"
+)
+
+// Получатели outbox. Каждый отмечает опубликованное отдельно, так что сбой одного
+// не задерживает другого.
+const (
+	// Вебхуки и уведомления в чат
+	OutboxConsumerInternal = "internal"
+	// Sinks из OUTBOX_SINKS
+	OutboxConsumerExternal = "external"
+)
+
+// OutboxEvent — доменное событие; поля, не относящиеся к типу, пустые.
+type OutboxEvent struct {
+	ID   int64
+	Type string
+	PRID string
+	// Автор для pr.created, пользователь для user.deactivated
+	UserID        string
+	ReviewerID    string
+	NewReviewerID string
+	Actor         string
+	Reason        string
+	CreatedAt     time.Time
+}
+
+// ReviewerEvents — события outbox для записей истории о назначении и замене
+// ревьюеров. Снятие при merge и close событий не даёт.
+func ReviewerEvents(records []AssignmentRecord) []OutboxEvent {
+	var events []OutboxEvent
+	for _, rec := range records {
+		ev := OutboxEvent{PRID: rec.PRID, ReviewerID: rec.ReviewerID, Actor: rec.Actor, Reason: rec.Reason}
+		switch rec.Action {
+		case ActionAssigned:
+			ev.Type = OutboxReviewerAssigned
+		case ActionReassigned, ActionRemoved:
+			ev.Type = OutboxReviewerReplaced
+			ev.NewReviewerID = rec.NewReviewerID
+		default:
+			continue
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+// FeedEvent — событие outbox вместе с текущими автором, командой автора и
+// ревьюерами PR, по которым фильтруется поток /events/stream.
+type FeedEvent struct {
+	OutboxEvent
+	AuthorID  string
+	TeamName  string
+	Reviewers []string
+	// Cursor — с какого id продолжать поток (Last-Event-ID): события до него
+	// включительно уже отданы. Меньше ID, пока перед событием есть пропуск.
+	Cursor int64
+}
+
+// Режимы уведомлений пользователя о назначениях на ревью.
+const (
+	NotifyInstant = "instant"
+	// Назначения копятся и приходят одним сообщением раз в период дайджеста
+	NotifyDigest = "digest"
+	NotifyMute   = "mute"
+)
+
+// NotificationPrefs — настройки уведомлений пользователя; без записи — instant в канал команды.
+type NotificationPrefs struct {
+	UserID string
+	Mode   string
+	// Личный канал (например, @alice или id пользователя в чате); пусто — канал команды
+	Channel string
+}
+
+// Notification — сообщение ревьюеру о назначении в очереди на отправку. Канал и
+// текст определяются при постановке в очередь.
+type Notification struct {
+	ID       int64
+	UserID   string
+	Channel  string
+	Text     string
+	Digest   bool
+	Attempts int
+	// Для дайджестов — когда пришло первое уведомление
+	CreatedAt time.Time
 }
 
 type UserStat struct {
-	Username    string
-	ReviewCount int
+	Username        string
+	ReviewCount     int
+	OpenReviewCount int
 }
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestWorkingHoursContains(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2025, 3, 10, hour, minute, 0, 0, time.UTC) }
+	day := WorkingHours{TimeZone: "UTC", Start: 9 * 60, End: 18 * 60}
+	night := WorkingHours{TimeZone: "UTC", Start: 22 * 60, End: 6 * 60}
+	// Москва — UTC+3 без перехода на летнее время
+	moscow := WorkingHours{TimeZone: "Europe/Moscow", Start: 9 * 60, End: 18 * 60}
+	// Нью-Йорк 10 марта 2025 уже на летнем времени, UTC-4
+	newYork := WorkingHours{TimeZone: "America/New_York", Start: 9 * 60, End: 18 * 60}
+
+	tests := []struct {
+		name  string
+		hours WorkingHours
+		t     time.Time
+		want  bool
+	}{
+		{"start is inclusive", day, at(9, 0), true},
+		{"before start", day, at(8, 59), false},
+		{"last minute", day, at(17, 59), true},
+		{"end is exclusive", day, at(18, 0), false},
+		{"overnight before midnight", night, at(23, 30), true},
+		{"overnight after midnight", night, at(5, 59), true},
+		{"overnight end", night, at(6, 0), false},
+		{"overnight gap", night, at(12, 0), false},
+		{"zone shifts the window", moscow, at(6, 0), true},
+		{"zone end", moscow, at(15, 0), false},
+		{"daylight saving", newYork, at(13, 0), true},
+		{"daylight saving start", newYork, at(12, 59), false},
+		{"unknown zone is UTC", WorkingHours{TimeZone: "Mars/Olympus", Start: 9 * 60, End: 18 * 60}, at(9, 0), true},
+		{"input zone is ignored", day, at(8, 0).In(time.FixedZone("UTC+2", 2*3600)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hours.Contains(tt.t); got != tt.want {
+				t.Fatalf("Contains(%s) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
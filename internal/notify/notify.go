@@ -0,0 +1,16 @@
+// Package notify — отправка уведомлений о назначениях в чаты.
+package notify
+
+import "context"
+
+// Message — сообщение в чат.
+type Message struct {
+	// Личка (@alice, id пользователя) или канал (#backend); пусто — куда настроен вебхук
+	Channel string
+	Text    string
+}
+
+// Notifier доставляет сообщения в чат. Ошибка — сообщение не принято, его можно повторить.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
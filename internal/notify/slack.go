@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxErrorBody — сколько ответа чата попадает в текст ошибки
+const maxErrorBody = 256
+
+// SlackNotifier шлёт сообщения в Slack-совместимый incoming webhook (Slack,
+// Mattermost, Rocket.Chat): POST с JSON {"channel", "text"}.
+type SlackNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewSlackNotifier(rawURL string, client *http.Client) (*SlackNotifier, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("slack notifier: %q is not an absolute http(s) URL", rawURL)
+	}
+	return &SlackNotifier{url: rawURL, client: client}, nil
+}
+
+type slackPayload struct {
+	Channel string `json:"channel,omitempty"`
+	Text    string `json:"text"`
+}
+
+// Notify считает сообщение принятым при ответе 2xx. Slack отвечает на ошибки 4xx
+// с кодом в теле (channel_not_found и т.п.), он попадает в ошибку.
+func (n *SlackNotifier) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(slackPayload{Channel: msg.Channel, Text: msg.Text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("slack notifier: %s: %s", resp.Status, strings.TrimSpace(string(reply)))
+	}
+	return nil
+}
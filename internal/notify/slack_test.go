@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSlackNotifier(t *testing.T) {
+	var got slackPayload
+	var contentType string
+	status, reply := http.StatusOK, "ok"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(reply))
+	}))
+	defer srv.Close()
+
+	n, err := NewSlackNotifier(srv.URL, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(context.Background(), Message{Channel: "@bob", Text: "review pr-1"}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if got.Channel != "@bob" || got.Text != "review pr-1" || contentType != "application/json" {
+		t.Fatalf("received %+v, %s", got, contentType)
+	}
+
+	// Без канала поле не передаётся: сообщение уйдёт в канал вебхука
+	got = slackPayload{Channel: "unset"}
+	if err := n.Notify(context.Background(), Message{Text: "hello"}); err != nil || got.Channel != "unset" {
+		t.Fatalf("no channel: %+v, %v", got, err)
+	}
+
+	status, reply = http.StatusNotFound, "channel_not_found"
+	if err := n.Notify(context.Background(), Message{Channel: "#nope", Text: "x"}); err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Fatalf("want error with slack reply, got %v", err)
+	}
+
+	if _, err := NewSlackNotifier("hooks.slack.com/services/x", srv.Client()); err == nil {
+		t.Fatal("want error for URL without scheme")
+	}
+}
@@ -0,0 +1,79 @@
+// Package outbox — приёмники (sinks), в которые relay публикует доменные события из outbox.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+// Sink принимает события по одному, в порядке записи. Доставка — хотя бы раз:
+// после сбоя событие придёт повторно, повторы отбрасываются по id.
+type Sink interface {
+	Publish(ctx context.Context, ev models.OutboxEvent) error
+}
+
+// message — событие в том виде, в каком его получают sinks.
+type message struct {
+	ID            int64     `json:"id"`
+	Type          string    `json:"type"`
+	PRID          string    `json:"pull_request_id,omitempty"`
+	UserID        string    `json:"user_id,omitempty"`
+	ReviewerID    string    `json:"reviewer_id,omitempty"`
+	NewReviewerID string    `json:"new_reviewer_id,omitempty"`
+	Actor         string    `json:"actor"`
+	Reason        string    `json:"reason,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// Encode — JSON события, одинаковый для всех sinks.
+func Encode(ev models.OutboxEvent) ([]byte, error) {
+	return json.Marshal(message{
+		ID:            ev.ID,
+		Type:          ev.Type,
+		PRID:          ev.PRID,
+		UserID:        ev.UserID,
+		ReviewerID:    ev.ReviewerID,
+		NewReviewerID: ev.NewReviewerID,
+		Actor:         ev.Actor,
+		Reason:        ev.Reason,
+		OccurredAt:    ev.CreatedAt.UTC(),
+	})
+}
+
+// ParseSinks разбирает список sinks через запятую: "log", "http=<url>", "file=<путь>".
+// Пустая строка — ни одного.
+func ParseSinks(spec string, client *http.Client) ([]Sink, error) {
+	var sinks []Sink
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kind, arg, _ := strings.Cut(item, "=")
+		switch kind {
+		case "log":
+			sinks = append(sinks, NewLogSink(nil))
+		case "http":
+			sink, err := NewHTTPSink(arg, client)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case "file":
+			sink, err := NewFileSink(arg)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		default:
+			return nil, fmt.Errorf("unknown outbox sink %q (expected log, http=<url> or file=<path>)", item)
+		}
+	}
+	return sinks, nil
+}
@@ -0,0 +1,145 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+var testEvent = models.OutboxEvent{
+	ID:            7,
+	Type:          models.OutboxReviewerReplaced,
+	PRID:          "pr-1",
+	ReviewerID:    "u2",
+	NewReviewerID: "u3",
+	Actor:         "alice",
+	Reason:        "on vacation",
+	CreatedAt:     time.Date(2025, 10, 24, 12, 0, 0, 0, time.UTC),
+}
+
+const testEventJSON = `{"id":7,"type":"reviewer.replaced","pull_request_id":"pr-1","reviewer_id":"u2","new_reviewer_id":"u3",` +
+	`"actor":"alice","reason":"on vacation","occurred_at":"2025-10-24T12:00:00Z"}`
+
+func TestEncode(t *testing.T) {
+	body, err := Encode(testEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != testEventJSON {
+		t.Fatalf("got %s", body)
+	}
+}
+
+func TestLogSink(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewLogSink(log.New(&buf, "", 0)).Publish(context.Background(), testEvent); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "Outbox event "+testEventJSON {
+		t.Fatalf("logged %q", got)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var gotID string
+	var gotBody []byte
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = r.Header.Get(HeaderEventID)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink, err := NewHTTPSink(srv.URL, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Publish(context.Background(), testEvent); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if gotID != "7" || string(gotBody) != testEventJSON {
+		t.Fatalf("received id %q, body %s", gotID, gotBody)
+	}
+
+	status = http.StatusBadGateway
+	if err := sink.Publish(context.Background(), testEvent); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("want error on 502, got %v", err)
+	}
+	if _, err := NewHTTPSink("localhost:9000", srv.Client()); err == nil {
+		t.Fatal("want error for URL without scheme")
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := testEvent
+	second.ID = 8
+	for _, ev := range []models.OutboxEvent{testEvent, second} {
+		if err := sink.Publish(context.Background(), ev); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 || lines[0] != testEventJSON {
+		t.Fatalf("file content:\n%s", data)
+	}
+	var msg struct{ ID int64 }
+	if err := json.Unmarshal([]byte(lines[1]), &msg); err != nil || msg.ID != 8 {
+		t.Fatalf("second line: %s, %v", lines[1], err)
+	}
+}
+
+func TestParseSinks(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "events.jsonl")
+	sinks, err := ParseSinks(" log, http=https://example.com/events ,file="+file, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sinks) != 3 {
+		t.Fatalf("got %d sinks", len(sinks))
+	}
+	if _, ok := sinks[0].(*LogSink); !ok {
+		t.Errorf("sink 0 is %T", sinks[0])
+	}
+	if _, ok := sinks[1].(*HTTPSink); !ok {
+		t.Errorf("sink 1 is %T", sinks[1])
+	}
+	if fs, ok := sinks[2].(*FileSink); !ok {
+		t.Errorf("sink 2 is %T", sinks[2])
+	} else {
+		_ = fs.Close()
+	}
+
+	if sinks, err := ParseSinks("", nil); err != nil || len(sinks) != 0 {
+		t.Fatalf("empty spec: %v, %v", sinks, err)
+	}
+	for _, spec := range []string{"kafka", "http=", "file="} {
+		if _, err := ParseSinks(spec, http.DefaultClient); err == nil {
+			t.Errorf("%q: want error", spec)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+// LogSink пишет события в лог.
+type LogSink struct {
+	logger *log.Logger
+}
+
+// NewLogSink: logger nil — стандартный логгер.
+func NewLogSink(logger *log.Logger) *LogSink {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Publish(ctx context.Context, ev models.OutboxEvent) error {
+	body, err := Encode(ev)
+	if err != nil {
+		return err
+	}
+	s.logger.Printf("Outbox event %s", body)
+	return nil
+}
+
+// HTTPSink отправляет каждое событие POST-запросом с JSON-телом; ответ не 2xx — ошибка.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+const HeaderEventID = "X-Outbox-Event-Id"
+
+func NewHTTPSink(rawURL string, client *http.Client) (*HTTPSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("outbox http sink: %q is not an absolute http(s) URL", rawURL)
+	}
+	return &HTTPSink{url: rawURL, client: client}, nil
+}
+
+func (s *HTTPSink) Publish(ctx context.Context, ev models.OutboxEvent) error {
+	body, err := Encode(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, strconv.FormatInt(ev.ID, 10))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("outbox http sink: %s responded %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+// FileSink дописывает события в файл по одному JSON на строку.
+type FileSink struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		return nil, errors.New("outbox file sink: empty path")
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{f: f}, nil
+}
+
+// Publish возвращается после fsync: событие пометится опубликованным, только
+// когда оно уже на диске.
+func (s *FileSink) Publish(ctx context.Context, ev models.OutboxEvent) error {
+	body, err := Encode(ev)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.f.Write(append(body, '\n')); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+func (s *FileSink) Close() error {
+	return s.f.Close()
+}
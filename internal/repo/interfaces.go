@@ -2,28 +2,151 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/humooo/avito-backend-trainee-2025/internal/models"
 )
 
+// ReassignPlanner решает, кем заменить ревьюеров при массовой деактивации.
+// settings — заданные настройки команд авторов; candidates — активные участники
+// команд из models.ReplacementTeams затронутых ревью. Ключ обоих — имя команды.
+type ReassignPlanner func(reviews []models.AffectedReview, settings map[string]*models.TeamSettings,
+	candidates map[string][]models.ReviewCandidate) []models.ReviewerChange
+
 type UserRepository interface {
 	Upsert(ctx context.Context, user *models.User) error
 	GetByID(ctx context.Context, id string) (*models.User, error)
 	ListByTeam(ctx context.Context, teamName string, activeOnly bool) ([]*models.User, error)
-	SetActive(ctx context.Context, id string, active bool) error
+	// SetActive при выключении активного пользователя пишет user.deactivated в outbox
+	SetActive(ctx context.Context, id string, active bool, audit models.Audit) error
+	// SetWorkingHours задаёт рабочие часы, nil — сбрасывает. Upsert их не меняет.
+	SetWorkingHours(ctx context.Context, id string, wh *models.WorkingHours) error
 	GetStats(ctx context.Context) ([]models.UserStat, error)
+	DeactivateWithReassign(ctx context.Context, teamName string, userIDs []string, plan ReassignPlanner, audit models.Audit) (*models.DeactivationResult, error)
+	// DetachWithReassign убирает участников из команды (TeamName = "") и переназначает
+	// их ревью в OPEN PR на оставшихся. Участники других команд не затрагиваются.
+	DetachWithReassign(ctx context.Context, teamName string, userIDs []string, plan ReassignPlanner, audit models.Audit) (*models.DeactivationResult, error)
+	// MoveTeam переводит пользователя в toTeam и записывает перевод. При handover его
+	// ревью в OPEN PR переназначаются, как при одиночной замене: в команде автора PR
+	// или её запасных командах.
+	MoveTeam(ctx context.Context, userID, toTeam string, handover bool, plan ReassignPlanner, audit models.Audit) (*models.TeamMove, []models.ReviewerChange, error)
+	ListMoves(ctx context.Context, userID string) ([]models.TeamMove, error)
+	// LinkIdentity связывает логин на платформе с пользователем (прежняя связь логина заменяется)
+	LinkIdentity(ctx context.Context, platform, login, userID string) error
+	// FindByIdentity ищет пользователя по логину на платформе; нет связи — domain.ErrNotFound
+	FindByIdentity(ctx context.Context, platform, login string) (*models.User, error)
 }
 
 type TeamRepository interface {
 	Create(ctx context.Context, team *models.Team) error
 	FindByName(ctx context.Context, name string) (*models.Team, error)
+	// Rename: занятое имя — domain.ErrAlreadyExists
+	Rename(ctx context.Context, oldName, newName string) error
+	// Delete убирает участников из команды, снимает их с OPEN PR и удаляет команду
+	Delete(ctx context.Context, name string, plan ReassignPlanner, audit models.Audit) (*models.DeactivationResult, error)
+	// GetSettings возвращает domain.ErrNotFound, если настройки команды не заданы
+	GetSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	UpsertSettings(ctx context.Context, settings *models.TeamSettings) error
 }
 
 type PRRepository interface {
-	CreateWithReviewers(ctx context.Context, pr *models.PullRequest) error
+	// Изменяющие методы пишут историю назначений и доменные события в той же транзакции.
+	// CreateWithReviewers оставляет в pr.Reviewers только записанных; если кто-то отсеян
+	// из-за лимита открытых ревью, pr.ReviewerShortage — models.ShortageAtCapacity.
+	CreateWithReviewers(ctx context.Context, pr *models.PullRequest, audit models.Audit) error
 	GetByID(ctx context.Context, id string) (*models.PullRequest, error)
-	Merge(ctx context.Context, id string) error
-	ReplaceReviewer(ctx context.Context, prID, oldID, newID string) error
+	Merge(ctx context.Context, id string, audit models.Audit) error
+	// OpenWithReviewers переводит PR из статуса from в OPEN и назначает pr.Reviewers
+	// (с теми же проверками, что и при создании). Если статус уже другой — domain.ErrInvalidState.
+	OpenWithReviewers(ctx context.Context, pr *models.PullRequest, from string, audit models.Audit) error
+	// Close закрывает DRAFT/OPEN PR и снимает ревьюеров; для CLOSED ничего не делает.
+	Close(ctx context.Context, id string, audit models.Audit) error
+	// ReplaceReviewer ставит newID вместо oldID. Замена проверяется заново, как при
+	// назначении: неактивная, отсутствующая или упёршаяся в лимит — domain.ErrNoCandidate.
+	ReplaceReviewer(ctx context.Context, prID, oldID, newID string, audit models.Audit) error
+	// SetReviewState записывает решение назначенного ревьюера; не назначен — domain.ErrNotFound
+	SetReviewState(ctx context.Context, prID, reviewerID, state string) error
+	// AddReviewer добавляет ревьюера к OPEN PR (ErrInvalidState для прочих).
+	// Неактивного, отсутствующего или упёршегося в лимит — ErrNoCandidate.
+	AddReviewer(ctx context.Context, prID, reviewerID string, audit models.Audit) error
+	// ListOverdue возвращает PENDING-ревью OPEN PR, назначенные раньше, чем now
+	// минус SLA команды автора. teamName пустой — по всем командам.
+	ListOverdue(ctx context.Context, teamName string, now time.Time) ([]models.OverdueReview, error)
+	MarkEscalated(ctx context.Context, prID, reviewerID string) error
 	ListByReviewer(ctx context.Context, reviewerID string) ([]*models.PullRequest, error)
-	FindCandidateForReassign(ctx context.Context, teamName, oldReviewerID, authorID string, currentReviewers []string) (string, error)
+	// List возвращает до filter.Limit PR после filter.After (с ревьюерами, без решений).
+	List(ctx context.Context, filter models.PRFilter) ([]*models.PullRequest, error)
+	FindCandidates(ctx context.Context, teamName string, exclude []string) ([]models.ReviewCandidate, error)
+	ListHistory(ctx context.Context, prID string) ([]models.AssignmentRecord, error)
+}
+
+type AbsenceRepository interface {
+	Create(ctx context.Context, a *models.Absence) error
+	ListByUser(ctx context.Context, userID string) ([]models.Absence, error)
+	Delete(ctx context.Context, id int64) error
+	// ListStarted возвращает необработанные отсутствия, идущие в момент now.
+	ListStarted(ctx context.Context, now time.Time) ([]models.Absence, error)
+	MarkHandled(ctx context.Context, id int64) error
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	// DeleteSubscription удаляет подписку вместе с её доставками
+	DeleteSubscription(ctx context.Context, id int64) error
+
+	// Enqueue ставит событие outbox eventID в очередь каждой активной подписке на
+	// event; подписке, уже получившей eventID, повтор не ставится. Возвращает число новых доставок.
+	Enqueue(ctx context.Context, eventID int64, event string, payload []byte) (int, error)
+	// ClaimDue выдаёт до limit ожидающих доставок активных подписок с next_attempt_at <= now
+	// и откладывает их до leaseUntil, чтобы другой экземпляр сервиса не взял их одновременно.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, statusCode int) error
+	// MarkFailed записывает неудачную попытку и назначает следующую на next. next nil —
+	// попытки исчерпаны: доставка становится dead и копируется в dead letters.
+	MarkFailed(ctx context.Context, id int64, statusCode *int, lastError string, next *time.Time) error
+	// ListDeliveries — журнал доставок подписки, новые первыми
+	ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error)
+	ListDeadLetters(ctx context.Context) ([]models.DeadLetter, error)
+	// Redeliver возвращает доставку из dead letters в очередь с нуля попыток
+	Redeliver(ctx context.Context, deadLetterID int64) error
+}
+
+// OutboxPublisher отправляет события по порядку и возвращает, сколько первых из них
+// отправлено; на первой ошибке останавливается.
+type OutboxPublisher func(ctx context.Context, events []models.OutboxEvent) (int, error)
+
+type OutboxRepository interface {
+	// PublishPending передаёт publish до limit событий, ещё не опубликованных для
+	// consumer (models.OutboxConsumer*), в порядке записи и помечает первые n, которые
+	// он подтвердил. Для каждого consumer публикует один экземпляр сервиса за раз:
+	// если другой уже публикует, возвращает 0. publish получает ctx со сроком аренды.
+	PublishPending(ctx context.Context, consumer string, limit int, publish OutboxPublisher) (int, error)
+	// ListAfter — до limit событий с id > afterID по возрастанию id, независимо от публикации
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]models.FeedEvent, error)
+	// LastID — id последнего записанного события, 0 — outbox пуст
+	LastID(ctx context.Context) (int64, error)
+	// Notify сигналит в канал о новых событиях, в том числе записанных другими
+	// экземплярами сервиса. Сигналы сливаются; канал закрывается при отмене ctx или
+	// потере соединения.
+	Notify(ctx context.Context) (<-chan struct{}, error)
+}
+
+// NotificationSender отправляет получателю одно мгновенное уведомление или весь его дайджест.
+type NotificationSender func(ctx context.Context, items []models.Notification) error
+
+type NotificationRepository interface {
+	// GetPrefs: без сохранённых настроек — instant без личного канала
+	GetPrefs(ctx context.Context, userID string) (*models.NotificationPrefs, error)
+	// SetPrefs: при переходе в digest период дайджеста отсчитывается заново
+	SetPrefs(ctx context.Context, p *models.NotificationPrefs) error
+	// Enqueue ставит уведомления по событию outbox; повтор eventID ничего не ставит
+	Enqueue(ctx context.Context, eventID int64, items []models.Notification) error
+	// SendPending передаёт send до limit мгновенных уведомлений по одному и дайджесты
+	// пользователей, чей прошлый дайджест был не позже digestBefore. Отправленное
+	// удаляется; при ошибке растёт счётчик попыток, после maxAttempts уведомление
+	// удаляется. Что уже отправляет другой экземпляр сервиса, пропускается.
+	SendPending(ctx context.Context, digestBefore time.Time, limit, maxAttempts int, send NotificationSender) (sent, failed int, err error)
 }
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+type AbsenceRepo struct {
+	s *Store
+}
+
+func NewAbsenceRepo(s *Store) *AbsenceRepo {
+	return &AbsenceRepo{s: s}
+}
+
+func (r *AbsenceRepo) Create(ctx context.Context, a *models.Absence) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[a.UserID]; !ok {
+		return fmt.Errorf("user %s: %w", a.UserID, domain.ErrNotFound)
+	}
+	r.s.lastAbsenceID++
+	a.ID = r.s.lastAbsenceID
+	a.Handled = false
+	a.CreatedAt = time.Now()
+	stored := *a
+	r.s.absences = append(r.s.absences, &stored)
+	return nil
+}
+
+func (r *AbsenceRepo) ListByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	return r.list(func(a *models.Absence) bool { return a.UserID == userID }), nil
+}
+
+func (r *AbsenceRepo) Delete(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	i := slices.IndexFunc(r.s.absences, func(a *models.Absence) bool { return a.ID == id })
+	if i < 0 {
+		return fmt.Errorf("absence %d: %w", id, domain.ErrNotFound)
+	}
+	r.s.absences = slices.Delete(r.s.absences, i, i+1)
+	return nil
+}
+
+func (r *AbsenceRepo) ListStarted(ctx context.Context, now time.Time) ([]models.Absence, error) {
+	return r.list(func(a *models.Absence) bool {
+		return !a.Handled && !a.StartsAt.After(now) && a.EndsAt.After(now)
+	}), nil
+}
+
+func (r *AbsenceRepo) MarkHandled(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, a := range r.s.absences {
+		if a.ID == id {
+			a.Handled = true
+		}
+	}
+	return nil
+}
+
+// list возвращает копии подходящих отсутствий в порядке начала.
+func (r *AbsenceRepo) list(match func(a *models.Absence) bool) []models.Absence {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var out []models.Absence
+	for _, a := range r.s.absences {
+		if match(a) {
+			out = append(out, *a)
+		}
+	}
+	slices.SortFunc(out, func(a, b models.Absence) int {
+		if c := a.StartsAt.Compare(b.StartsAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return out
+}
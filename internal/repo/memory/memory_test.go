@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+)
+
+var (
+	_ repo.UserRepository    = (*UserRepo)(nil)
+	_ repo.TeamRepository    = (*TeamRepo)(nil)
+	_ repo.PRRepository      = (*PRRepo)(nil)
+	_ repo.AbsenceRepository = (*AbsenceRepo)(nil)
+	_ repo.WebhookRepository = (*WebhookRepo)(nil)
+	_ repo.OutboxRepository  = (*OutboxRepo)(nil)
+
+	_ repo.NotificationRepository = (*NotificationRepo)(nil)
+)
+
+func newRepos(testing.TB) repotest.Repos {
+	s := NewStore()
+	return repotest.Repos{
+		Users:    NewUserRepo(s),
+		Teams:    NewTeamRepo(s),
+		PRs:      NewPRRepo(s),
+		Absences: NewAbsenceRepo(s),
+		Webhooks: NewWebhookRepo(s),
+		Outbox:   NewOutboxRepo(s),
+
+		Notifications: NewNotificationRepo(s),
+	}
+}
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, newRepos)
+}
+
+func BenchmarkDeactivate(b *testing.B) {
+	repotest.BenchmarkDeactivate(b, newRepos)
+}
+
+func BenchmarkListPRs(b *testing.B) {
+	repotest.BenchmarkListPRs(b, newRepos)
+}
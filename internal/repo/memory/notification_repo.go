@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+)
+
+type NotificationRepo struct {
+	s *Store
+}
+
+func NewNotificationRepo(s *Store) *NotificationRepo {
+	return &NotificationRepo{s: s}
+}
+
+func (r *NotificationRepo) GetPrefs(ctx context.Context, userID string) (*models.NotificationPrefs, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	if p, ok := r.s.notifyPrefs[userID]; ok {
+		return &p, nil
+	}
+	return &models.NotificationPrefs{UserID: userID, Mode: models.NotifyInstant}, nil
+}
+
+func (r *NotificationRepo) SetPrefs(ctx context.Context, p *models.NotificationPrefs) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[p.UserID]; !ok {
+		return fmt.Errorf("user %s: %w", p.UserID, domain.ErrNotFound)
+	}
+	old, ok := r.s.notifyPrefs[p.UserID]
+	if !ok || (old.Mode != models.NotifyDigest && p.Mode == models.NotifyDigest) {
+		r.s.lastDigestAt[p.UserID] = time.Now()
+	}
+	r.s.notifyPrefs[p.UserID] = *p
+	return nil
+}
+
+func (r *NotificationRepo) Enqueue(ctx context.Context, eventID int64, items []models.Notification) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if len(items) == 0 || r.s.notifiedEvents[eventID] {
+		return nil
+	}
+	for _, n := range items {
+		if _, ok := r.s.users[n.UserID]; !ok {
+			return fmt.Errorf("user %s: %w", n.UserID, domain.ErrNotFound)
+		}
+	}
+	r.s.notifiedEvents[eventID] = true
+	now := time.Now()
+	for _, n := range items {
+		r.s.lastNotificationID++
+		n.ID = r.s.lastNotificationID
+		n.Attempts = 0
+		n.CreatedAt = now
+		r.s.notifications = append(r.s.notifications, n)
+	}
+	return nil
+}
+
+// SendPending не держит блокировку хранилища, пока работает send; второй
+// отправляющий в это время ничего не делает.
+func (r *NotificationRepo) SendPending(ctx context.Context, digestBefore time.Time, limit, maxAttempts int, send repo.NotificationSender) (int, int, error) {
+	if !r.s.senderMu.TryLock() {
+		return 0, 0, nil
+	}
+	defer r.s.senderMu.Unlock()
+
+	var batches [][]models.Notification
+	var digests []models.Notification
+	r.s.mu.RLock()
+	for _, n := range r.s.notifications {
+		if !n.Digest && len(batches) < limit {
+			batches = append(batches, []models.Notification{n})
+		}
+		if at, ok := r.s.lastDigestAt[n.UserID]; n.Digest && ok && !at.After(digestBefore) {
+			digests = append(digests, n)
+		}
+	}
+	r.s.mu.RUnlock()
+
+	slices.SortStableFunc(digests, func(a, b models.Notification) int { return cmp.Compare(a.UserID, b.UserID) })
+	for i, n := range digests {
+		if i == 0 || n.UserID != digests[i-1].UserID {
+			batches = append(batches, nil)
+		}
+		batches[len(batches)-1] = append(batches[len(batches)-1], n)
+	}
+
+	done := make(map[int64]bool)
+	failedIDs := make(map[int64]bool)
+	var digested []string
+	sent, failed := 0, 0
+	for _, batch := range batches {
+		if err := send(ctx, batch); err != nil {
+			failed++
+			for _, n := range batch {
+				failedIDs[n.ID] = true
+			}
+			continue
+		}
+		sent++
+		for _, n := range batch {
+			done[n.ID] = true
+		}
+		if batch[0].Digest {
+			digested = append(digested, batch[0].UserID)
+		}
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	for _, id := range digested {
+		r.s.lastDigestAt[id] = now
+	}
+	r.s.notifications = slices.DeleteFunc(r.s.notifications, func(n models.Notification) bool {
+		return done[n.ID]
+	})
+	for i := range r.s.notifications {
+		if failedIDs[r.s.notifications[i].ID] {
+			r.s.notifications[i].Attempts++
+		}
+	}
+	r.s.notifications = slices.DeleteFunc(r.s.notifications, func(n models.Notification) bool {
+		return failedIDs[n.ID] && n.Attempts >= maxAttempts
+	})
+	return sent, failed, nil
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+)
+
+type OutboxRepo struct {
+	s *Store
+}
+
+func NewOutboxRepo(s *Store) *OutboxRepo {
+	return &OutboxRepo{s: s}
+}
+
+// PublishPending не держит блокировку хранилища, пока работает publish: события
+// пишутся только в конец, а опубликованные — всегда начало outbox.
+func (r *OutboxRepo) PublishPending(ctx context.Context, consumer string, limit int, publish repo.OutboxPublisher) (int, error) {
+	if consumer != models.OutboxConsumerInternal && consumer != models.OutboxConsumerExternal {
+		return 0, fmt.Errorf("unknown outbox consumer %q", consumer)
+	}
+
+	r.s.mu.Lock()
+	if r.s.outboxRelays[consumer] {
+		r.s.mu.Unlock()
+		return 0, nil
+	}
+	from := r.s.outboxPublished[consumer]
+	events := append([]models.OutboxEvent(nil), r.s.outbox[from:min(from+limit, len(r.s.outbox))]...)
+	if len(events) == 0 {
+		r.s.mu.Unlock()
+		return 0, nil
+	}
+	r.s.outboxRelays[consumer] = true
+	r.s.mu.Unlock()
+
+	n, err := publish(ctx, events)
+	r.s.mu.Lock()
+	r.s.outboxPublished[consumer] += n
+	delete(r.s.outboxRelays, consumer)
+	r.s.mu.Unlock()
+	return n, err
+}
+
+// ListAfter опирается на то, что id событий идут подряд с 1.
+func (r *OutboxRepo) ListAfter(ctx context.Context, afterID int64, limit int) ([]models.FeedEvent, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	from := int(min(max(afterID, 0), int64(len(r.s.outbox))))
+	var events []models.FeedEvent
+	for _, ev := range r.s.outbox[from:min(from+limit, len(r.s.outbox))] {
+		fe := models.FeedEvent{OutboxEvent: ev}
+		if pr, ok := r.s.prs[ev.PRID]; ok {
+			fe.AuthorID = pr.AuthorID
+			if author, ok := r.s.users[pr.AuthorID]; ok {
+				fe.TeamName = author.TeamName
+			}
+			fe.Reviewers = slices.Sorted(slices.Values(pr.Reviewers))
+		}
+		events = append(events, fe)
+	}
+	return events, nil
+}
+
+func (r *OutboxRepo) LastID(ctx context.Context) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.lastOutboxID, nil
+}
+
+func (r *OutboxRepo) Notify(ctx context.Context) (<-chan struct{}, error) {
+	ch := make(chan struct{}, 1)
+	r.s.mu.Lock()
+	r.s.outboxListeners[ch] = struct{}{}
+	r.s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		r.s.mu.Lock()
+		delete(r.s.outboxListeners, ch)
+		close(ch)
+		r.s.mu.Unlock()
+	}()
+	return ch, nil
+}
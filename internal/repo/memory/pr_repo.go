@@ -0,0 +1,393 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+type PRRepo struct {
+	s *Store
+}
+
+func NewPRRepo(s *Store) *PRRepo {
+	return &PRRepo{s: s}
+}
+
+func (r *PRRepo) CreateWithReviewers(ctx context.Context, pr *models.PullRequest, audit models.Audit) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.prs[pr.ID]; ok {
+		return fmt.Errorf("pr %s: %w", pr.ID, domain.ErrAlreadyExists)
+	}
+	if _, ok := r.s.users[pr.AuthorID]; !ok {
+		return fmt.Errorf("author %s: %w", pr.AuthorID, domain.ErrNotFound)
+	}
+
+	r.s.appendOutbox(models.OutboxEvent{Type: models.OutboxPRCreated, PRID: pr.ID, UserID: pr.AuthorID, Actor: audit.Actor})
+	r.s.assignReviewers(pr, audit)
+	stored := copyPR(pr)
+	stored.ReviewerShortage = ""
+	stored.CreatedAt = time.Now()
+	r.s.prs[pr.ID] = stored
+	return nil
+}
+
+func (r *PRRepo) OpenWithReviewers(ctx context.Context, pr *models.PullRequest, from string, audit models.Audit) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.prs[pr.ID]
+	if !ok {
+		return fmt.Errorf("pr %s: %w", pr.ID, domain.ErrNotFound)
+	}
+	if stored.Status != from {
+		return fmt.Errorf("pr %s is not %s: %w", pr.ID, from, domain.ErrInvalidState)
+	}
+	r.s.assignReviewers(pr, audit)
+	stored.Status = models.StatusOpen
+	r.s.addAssignments(stored, pr)
+
+	pr.Status = stored.Status
+	pr.Reviewers = slices.Clone(stored.Reviewers)
+	pr.Fallback = maps.Clone(stored.Fallback)
+	pr.AssignedAt = maps.Clone(stored.AssignedAt)
+	return nil
+}
+
+// assignReviewers оставляет в pr.Reviewers и pr.Fallback тех, кого можно
+// назначить, и пишет историю. Как и в Postgres: только активные, не отсутствующие
+// и не упёршиеся в лимит; отсеянные по лимиту — pr.ReviewerShortage.
+func (s *Store) assignReviewers(pr *models.PullRequest, audit models.Audit) {
+	counts := s.openReviewCounts()
+	var out []string
+	fallback := make(map[string]bool)
+	pr.ReviewerShortage = ""
+	for _, id := range pr.Reviewers {
+		u, ok := s.users[id]
+		if !ok || !u.IsActive || s.away(id) || slices.Contains(out, id) {
+			continue
+		}
+		if u.MaxOpenReviews != nil && counts[id] >= *u.MaxOpenReviews {
+			pr.ReviewerShortage = models.ShortageAtCapacity
+			continue
+		}
+		out = append(out, id)
+		if pr.Fallback[id] {
+			fallback[id] = true
+		}
+	}
+	now := time.Now()
+	pr.AssignedAt = make(map[string]time.Time, len(out))
+	history := make([]models.AssignmentRecord, len(out))
+	for i, id := range out {
+		history[i] = models.AssignmentRecord{PRID: pr.ID, ReviewerID: id, Action: models.ActionAssigned, Actor: audit.Actor, Reason: audit.Reason}
+		pr.AssignedAt[id] = now
+	}
+	s.appendHistory(history...)
+	s.appendOutbox(models.ReviewerEvents(history)...)
+	pr.Reviewers = out
+	pr.Fallback = nil
+	if len(fallback) > 0 {
+		pr.Fallback = fallback
+	}
+}
+
+// addAssignments дописывает к сохранённому PR ревьюеров, уже прошедших assignReviewers.
+func (s *Store) addAssignments(stored, added *models.PullRequest) {
+	stored.Reviewers = append(stored.Reviewers, added.Reviewers...)
+	for id := range added.Fallback {
+		if stored.Fallback == nil {
+			stored.Fallback = make(map[string]bool)
+		}
+		stored.Fallback[id] = true
+	}
+	if stored.AssignedAt == nil {
+		stored.AssignedAt = make(map[string]time.Time)
+	}
+	maps.Copy(stored.AssignedAt, added.AssignedAt)
+}
+
+func (r *PRRepo) GetByID(ctx context.Context, id string) (*models.PullRequest, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	pr, ok := r.s.prs[id]
+	if !ok {
+		return nil, fmt.Errorf("pr %s: %w", id, domain.ErrNotFound)
+	}
+	return copyPR(pr), nil
+}
+
+func (r *PRRepo) Merge(ctx context.Context, id string, audit models.Audit) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	pr, ok := r.s.prs[id]
+	if !ok || pr.Status != models.StatusOpen {
+		return nil
+	}
+	now := time.Now()
+	pr.Status = models.StatusMerged
+	pr.MergedAt = &now
+
+	for _, rid := range slices.Sorted(slices.Values(pr.Reviewers)) {
+		r.s.appendHistory(models.AssignmentRecord{PRID: id, ReviewerID: rid, Action: models.ActionReleased, Actor: audit.Actor, Reason: audit.Reason})
+	}
+	r.s.appendOutbox(models.OutboxEvent{Type: models.OutboxPRMerged, PRID: id, Actor: audit.Actor, Reason: audit.Reason})
+	return nil
+}
+
+func (r *PRRepo) Close(ctx context.Context, id string, audit models.Audit) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	pr, ok := r.s.prs[id]
+	if !ok || (pr.Status != models.StatusOpen && pr.Status != models.StatusDraft) {
+		return nil
+	}
+	pr.Status = models.StatusClosed
+
+	for _, rid := range slices.Sorted(slices.Values(pr.Reviewers)) {
+		r.s.appendHistory(models.AssignmentRecord{PRID: id, ReviewerID: rid, Action: models.ActionReleased, Actor: audit.Actor, Reason: audit.Reason})
+	}
+	pr.Reviewers = nil
+	pr.ReviewStates = nil
+	pr.Fallback = nil
+	pr.AssignedAt = nil
+	pr.Escalated = nil
+	return nil
+}
+
+func (r *PRRepo) ReplaceReviewer(ctx context.Context, prID, oldID, newID string, audit models.Audit) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	pr, ok := r.s.prs[prID]
+	if !ok || !slices.Contains(pr.Reviewers, oldID) {
+		return fmt.Errorf("reviewer %s on pr %s: %w", oldID, prID, domain.ErrNotFound)
+	}
+	i := slices.Index(pr.Reviewers, oldID)
+	if slices.Contains(pr.Reviewers, newID) {
+		return fmt.Errorf("reviewer %s on pr %s: %w", newID, prID, domain.ErrAlreadyExists)
+	}
+	u, ok := r.s.users[newID]
+	if !ok {
+		return fmt.Errorf("user %s: %w", newID, domain.ErrNotFound)
+	}
+	if !u.IsActive || r.s.away(newID) || (u.MaxOpenReviews != nil && r.s.openReviewCounts()[newID] >= *u.MaxOpenReviews) {
+		return fmt.Errorf("user %s: %w", newID, domain.ErrNoCandidate)
+	}
+	pr.Reviewers[i] = newID
+	replaceAssignment(pr, oldID, newID)
+
+	rec := models.AssignmentRecord{
+		PRID:          prID,
+		ReviewerID:    oldID,
+		NewReviewerID: newID,
+		Action:        models.ActionReassigned,
+		Actor:         audit.Actor,
+		Reason:        audit.Reason,
+	}
+	r.s.appendHistory(rec)
+	r.s.appendOutbox(models.ReviewerEvents([]models.AssignmentRecord{rec})...)
+	return nil
+}
+
+func (r *PRRepo) SetReviewState(ctx context.Context, prID, reviewerID, state string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	pr, ok := r.s.prs[prID]
+	if !ok || !slices.Contains(pr.Reviewers, reviewerID) {
+		return fmt.Errorf("reviewer %s on pr %s: %w", reviewerID, prID, domain.ErrNotFound)
+	}
+	if pr.ReviewStates == nil {
+		pr.ReviewStates = make(map[string]string)
+	}
+	pr.ReviewStates[reviewerID] = state
+	return nil
+}
+
+func (r *PRRepo) AddReviewer(ctx context.Context, prID, reviewerID string, audit models.Audit) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.prs[prID]
+	if !ok {
+		return fmt.Errorf("pr %s: %w", prID, domain.ErrNotFound)
+	}
+	if stored.Status != models.StatusOpen {
+		return fmt.Errorf("pr %s is %s: %w", prID, stored.Status, domain.ErrInvalidState)
+	}
+	if slices.Contains(stored.Reviewers, reviewerID) {
+		return fmt.Errorf("reviewer %s on pr %s: %w", reviewerID, prID, domain.ErrAlreadyExists)
+	}
+
+	added := &models.PullRequest{ID: prID, Reviewers: []string{reviewerID}}
+	r.s.assignReviewers(added, audit)
+	if len(added.Reviewers) == 0 {
+		return fmt.Errorf("user %s: %w", reviewerID, domain.ErrNoCandidate)
+	}
+	r.s.addAssignments(stored, added)
+	return nil
+}
+
+func (r *PRRepo) ListOverdue(ctx context.Context, teamName string, now time.Time) ([]models.OverdueReview, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var overdue []models.OverdueReview
+	for _, pr := range r.s.prs {
+		author, ok := r.s.users[pr.AuthorID]
+		if pr.Status != models.StatusOpen || !ok || (teamName != "" && author.TeamName != teamName) {
+			continue
+		}
+		ts, ok := r.s.settings[author.TeamName]
+		if !ok || ts.ReviewSLA <= 0 {
+			continue
+		}
+		for _, id := range pr.Reviewers {
+			assignedAt := pr.AssignedAt[id]
+			if pr.ReviewState(id) != models.ReviewPending || assignedAt.Add(ts.ReviewSLA).After(now) {
+				continue
+			}
+			overdue = append(overdue, models.OverdueReview{
+				PRID:       pr.ID,
+				Title:      pr.Title,
+				AuthorID:   pr.AuthorID,
+				TeamName:   author.TeamName,
+				ReviewerID: id,
+				AssignedAt: assignedAt,
+				Escalated:  pr.Escalated[id],
+			})
+		}
+	}
+	slices.SortFunc(overdue, func(a, b models.OverdueReview) int {
+		if c := a.AssignedAt.Compare(b.AssignedAt); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.PRID, b.PRID); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ReviewerID, b.ReviewerID)
+	})
+	return overdue, nil
+}
+
+func (r *PRRepo) MarkEscalated(ctx context.Context, prID, reviewerID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	pr, ok := r.s.prs[prID]
+	if !ok || !slices.Contains(pr.Reviewers, reviewerID) {
+		return fmt.Errorf("reviewer %s on pr %s: %w", reviewerID, prID, domain.ErrNotFound)
+	}
+	if pr.Escalated == nil {
+		pr.Escalated = make(map[string]bool)
+	}
+	pr.Escalated[reviewerID] = true
+	return nil
+}
+
+func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]*models.PullRequest, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var prs []*models.PullRequest
+	for _, pr := range r.s.prs {
+		if !slices.Contains(pr.Reviewers, reviewerID) {
+			continue
+		}
+		prs = append(prs, &models.PullRequest{
+			ID:       pr.ID,
+			Title:    pr.Title,
+			AuthorID: pr.AuthorID,
+			Status:   pr.Status,
+		})
+	}
+	slices.SortFunc(prs, func(a, b *models.PullRequest) int { return strings.Compare(a.ID, b.ID) })
+	return prs, nil
+}
+
+func (r *PRRepo) List(ctx context.Context, filter models.PRFilter) ([]*models.PullRequest, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	in := func(t time.Time, from, to *time.Time) bool {
+		return (from == nil || !t.Before(*from)) && (to == nil || t.Before(*to))
+	}
+
+	var prs []*models.PullRequest
+	for _, pr := range r.s.prs {
+		switch {
+		case filter.Status != "" && pr.Status != filter.Status,
+			filter.AuthorID != "" && pr.AuthorID != filter.AuthorID,
+			filter.ReviewerID != "" && !slices.Contains(pr.Reviewers, filter.ReviewerID),
+			filter.TeamName != "" && r.s.users[pr.AuthorID].TeamName != filter.TeamName,
+			!in(pr.CreatedAt, filter.CreatedFrom, filter.CreatedTo):
+			continue
+		}
+		if filter.MergedFrom != nil || filter.MergedTo != nil {
+			if pr.MergedAt == nil || !in(*pr.MergedAt, filter.MergedFrom, filter.MergedTo) {
+				continue
+			}
+		}
+		if filter.After != nil && comparePRPosition(pr, *filter.After) >= 0 {
+			continue
+		}
+		c := copyPR(pr)
+		slices.Sort(c.Reviewers)
+		prs = append(prs, &models.PullRequest{
+			ID:        c.ID,
+			Title:     c.Title,
+			AuthorID:  c.AuthorID,
+			Status:    c.Status,
+			Reviewers: c.Reviewers,
+			CreatedAt: c.CreatedAt,
+			MergedAt:  c.MergedAt,
+		})
+	}
+	slices.SortFunc(prs, func(a, b *models.PullRequest) int {
+		return -comparePRPosition(a, models.PRCursor{CreatedAt: b.CreatedAt, ID: b.ID})
+	})
+	if len(prs) > filter.Limit {
+		prs = prs[:filter.Limit]
+	}
+	return prs, nil
+}
+
+// comparePRPosition сравнивает PR с позицией по (CreatedAt, ID), как индекс в Postgres.
+func comparePRPosition(pr *models.PullRequest, pos models.PRCursor) int {
+	if c := pr.CreatedAt.Compare(pos.CreatedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(pr.ID, pos.ID)
+}
+
+func (r *PRRepo) FindCandidates(ctx context.Context, teamName string, exclude []string) ([]models.ReviewCandidate, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.s.candidates(teamName, exclude, r.s.openReviewCounts()), nil
+}
+
+func (r *PRRepo) ListHistory(ctx context.Context, prID string) ([]models.AssignmentRecord, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var records []models.AssignmentRecord
+	for _, rec := range r.s.history {
+		if rec.PRID == prID {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+// Store — общее состояние для всех in-memory репозиториев. Репозитории
+// ссылаются друг на друга через него так же, как таблицы через внешние ключи.
+type Store struct {
+	mu    sync.RWMutex
+	teams map[string]*models.Team
+	users map[string]*models.User
+	prs   map[string]*models.PullRequest
+
+	settings map[string]*models.TeamSettings
+
+	history       []models.AssignmentRecord
+	lastHistoryID int64
+
+	moves      []models.TeamMove
+	lastMoveID int64
+
+	absences      []*models.Absence
+	lastAbsenceID int64
+
+	// identities[платформа][логин] — user_id
+	identities map[string]map[string]string
+
+	subscriptions      []*models.WebhookSubscription
+	lastSubscriptionID int64
+	deliveries         []*models.WebhookDelivery
+	lastDeliveryID     int64
+	deadLetters        []*models.DeadLetter
+	lastDeadLetterID   int64
+	// enqueuedEvents — каким подпискам уже поставлено событие outbox
+	enqueuedEvents map[deliveryKey]bool
+
+	outbox       []models.OutboxEvent
+	lastOutboxID int64
+	// outbox[:outboxPublished[consumer]] опубликованы для consumer
+	outboxPublished map[string]int
+	// outboxRelays — получатели, для которых сейчас публикует relay
+	outboxRelays map[string]bool
+	// outboxListeners получают сигнал о новых событиях
+	outboxListeners map[chan struct{}]struct{}
+
+	notifyPrefs        map[string]models.NotificationPrefs
+	lastDigestAt       map[string]time.Time
+	notifications      []models.Notification
+	lastNotificationID int64
+	// notifiedEvents — события outbox, по которым уведомления уже поставлены
+	notifiedEvents map[int64]bool
+	// senderMu держит отправляющий уведомления
+	senderMu sync.Mutex
+}
+
+type deliveryKey struct {
+	subscriptionID int64
+	eventID        int64
+}
+
+func NewStore() *Store {
+	return &Store{
+		teams: make(map[string]*models.Team),
+		users: make(map[string]*models.User),
+		prs:   make(map[string]*models.PullRequest),
+
+		settings: make(map[string]*models.TeamSettings),
+
+		identities: make(map[string]map[string]string),
+
+		enqueuedEvents: make(map[deliveryKey]bool),
+
+		outboxListeners: make(map[chan struct{}]struct{}),
+		outboxPublished: make(map[string]int),
+		outboxRelays:    make(map[string]bool),
+
+		notifyPrefs:  make(map[string]models.NotificationPrefs),
+		lastDigestAt: make(map[string]time.Time),
+
+		notifiedEvents: make(map[int64]bool),
+	}
+}
+
+// appendHistory проставляет ID и время и дописывает записи. Вызывать под блокировкой.
+func (s *Store) appendHistory(records ...models.AssignmentRecord) {
+	now := time.Now()
+	for _, rec := range records {
+		s.lastHistoryID++
+		rec.ID = s.lastHistoryID
+		rec.CreatedAt = now
+		s.history = append(s.history, rec)
+	}
+}
+
+// appendOutbox проставляет ID и время и дописывает события. Вызывать под блокировкой.
+func (s *Store) appendOutbox(events ...models.OutboxEvent) {
+	now := time.Now()
+	for _, ev := range events {
+		s.lastOutboxID++
+		ev.ID = s.lastOutboxID
+		ev.CreatedAt = now
+		s.outbox = append(s.outbox, ev)
+	}
+	if len(events) == 0 {
+		return
+	}
+	for ch := range s.outboxListeners {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// openReviewCounts считает OPEN PR по ревьюерам. Вызывать под блокировкой.
+func (s *Store) openReviewCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, pr := range s.prs {
+		if pr.Status != models.StatusOpen {
+			continue
+		}
+		for _, id := range pr.Reviewers {
+			counts[id]++
+		}
+	}
+	return counts
+}
+
+// away сообщает, идёт ли сейчас отсутствие пользователя. Вызывать под блокировкой.
+func (s *Store) away(userID string) bool {
+	now := time.Now()
+	for _, a := range s.absences {
+		if a.UserID == userID && !a.StartsAt.After(now) && a.EndsAt.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// candidates — активные и не отсутствующие участники команды, кроме exclude,
+// в порядке user_id. Вызывать под блокировкой.
+func (s *Store) candidates(teamName string, exclude []string, counts map[string]int) []models.ReviewCandidate {
+	var out []models.ReviewCandidate
+	for _, u := range s.users {
+		if u.TeamName != teamName || !u.IsActive || s.away(u.ID) || slices.Contains(exclude, u.ID) {
+			continue
+		}
+		c := copyUser(u)
+		out = append(out, models.ReviewCandidate{
+			UserID:         u.ID,
+			OpenReviews:    counts[u.ID],
+			MaxOpenReviews: c.MaxOpenReviews,
+			WorkingHours:   c.WorkingHours,
+		})
+	}
+	slices.SortFunc(out, func(a, b models.ReviewCandidate) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+	return out
+}
+
+func copyUser(u *models.User) *models.User {
+	c := *u
+	if u.MaxOpenReviews != nil {
+		limit := *u.MaxOpenReviews
+		c.MaxOpenReviews = &limit
+	}
+	if u.WorkingHours != nil {
+		wh := *u.WorkingHours
+		c.WorkingHours = &wh
+	}
+	return &c
+}
+
+func copyPR(pr *models.PullRequest) *models.PullRequest {
+	c := *pr
+	c.Reviewers = append([]string(nil), pr.Reviewers...)
+	c.ReviewStates = maps.Clone(pr.ReviewStates)
+	c.Fallback = maps.Clone(pr.Fallback)
+	c.AssignedAt = maps.Clone(pr.AssignedAt)
+	c.Escalated = maps.Clone(pr.Escalated)
+	if pr.MergedAt != nil {
+		t := *pr.MergedAt
+		c.MergedAt = &t
+	}
+	return &c
+}
+
+// replaceAssignment убирает решение, время назначения и отметку эскалации oldID;
+// newID (если есть) назначен только что.
+func replaceAssignment(pr *models.PullRequest, oldID, newID string) {
+	delete(pr.ReviewStates, oldID)
+	delete(pr.AssignedAt, oldID)
+	delete(pr.Escalated, oldID)
+	moveFallback(pr, oldID, newID)
+	if newID != "" {
+		if pr.AssignedAt == nil {
+			pr.AssignedAt = make(map[string]time.Time)
+		}
+		pr.AssignedAt[newID] = time.Now()
+	}
+}
+
+// moveFallback переносит отметку запасного ревьюера на замену: она из той же команды.
+func moveFallback(pr *models.PullRequest, oldID, newID string) {
+	if !pr.Fallback[oldID] {
+		return
+	}
+	delete(pr.Fallback, oldID)
+	if newID != "" {
+		pr.Fallback[newID] = true
+	}
+}
+
+func copySettings(ts *models.TeamSettings) *models.TeamSettings {
+	c := *ts
+	c.FallbackTeams = slices.Clone(ts.FallbackTeams)
+	return &c
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+)
+
+type TeamRepo struct {
+	s *Store
+}
+
+func NewTeamRepo(s *Store) *TeamRepo {
+	return &TeamRepo{s: s}
+}
+
+func (r *TeamRepo) Create(ctx context.Context, team *models.Team) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.teams[team.Name]; ok {
+		return fmt.Errorf("team %s: %w", team.Name, domain.ErrAlreadyExists)
+	}
+	r.s.teams[team.Name] = &models.Team{Name: team.Name}
+	return nil
+}
+
+func (r *TeamRepo) FindByName(ctx context.Context, name string) (*models.Team, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	t, ok := r.s.teams[name]
+	if !ok {
+		return nil, fmt.Errorf("team %s: %w", name, domain.ErrNotFound)
+	}
+	return &models.Team{Name: t.Name}, nil
+}
+
+func (r *TeamRepo) Rename(ctx context.Context, oldName, newName string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	t, ok := r.s.teams[oldName]
+	if !ok {
+		return fmt.Errorf("team %s: %w", oldName, domain.ErrNotFound)
+	}
+	if _, ok := r.s.teams[newName]; ok {
+		return fmt.Errorf("team %s: %w", newName, domain.ErrAlreadyExists)
+	}
+	// Как ON UPDATE CASCADE в Postgres
+	delete(r.s.teams, oldName)
+	t.Name = newName
+	r.s.teams[newName] = t
+	for _, u := range r.s.users {
+		if u.TeamName == oldName {
+			u.TeamName = newName
+		}
+	}
+	if ts, ok := r.s.settings[oldName]; ok {
+		delete(r.s.settings, oldName)
+		ts.TeamName = newName
+		r.s.settings[newName] = ts
+	}
+	for _, ts := range r.s.settings {
+		for i, name := range ts.FallbackTeams {
+			if name == oldName {
+				ts.FallbackTeams[i] = newName
+			}
+		}
+	}
+	return nil
+}
+
+func (r *TeamRepo) Delete(ctx context.Context, name string, plan repo.ReassignPlanner, audit models.Audit) (*models.DeactivationResult, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.teams[name]; !ok {
+		return nil, fmt.Errorf("team %s: %w", name, domain.ErrNotFound)
+	}
+	result := r.s.detachMembers(name, nil, plan, audit)
+
+	delete(r.s.teams, name)
+	delete(r.s.settings, name)
+	for _, ts := range r.s.settings {
+		ts.FallbackTeams = slices.DeleteFunc(ts.FallbackTeams, func(t string) bool { return t == name })
+	}
+	return result, nil
+}
+
+func (r *TeamRepo) GetSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	ts, ok := r.s.settings[teamName]
+	if !ok {
+		return nil, fmt.Errorf("settings of team %s: %w", teamName, domain.ErrNotFound)
+	}
+	return copySettings(ts), nil
+}
+
+func (r *TeamRepo) UpsertSettings(ctx context.Context, ts *models.TeamSettings) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.teams[ts.TeamName]; !ok {
+		return fmt.Errorf("team %s: %w", ts.TeamName, domain.ErrNotFound)
+	}
+	if _, ok := r.s.users[ts.LeadUserID]; ts.LeadUserID != "" && !ok {
+		return fmt.Errorf("user %s: %w", ts.LeadUserID, domain.ErrNotFound)
+	}
+	for _, name := range ts.FallbackTeams {
+		if _, ok := r.s.teams[name]; !ok {
+			return fmt.Errorf("team %s: %w", name, domain.ErrNotFound)
+		}
+	}
+	r.s.settings[ts.TeamName] = copySettings(ts)
+	return nil
+}
@@ -0,0 +1,315 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+)
+
+type UserRepo struct {
+	s *Store
+}
+
+func NewUserRepo(s *Store) *UserRepo {
+	return &UserRepo{s: s}
+}
+
+func (r *UserRepo) Upsert(ctx context.Context, user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.teams[user.TeamName]; !ok {
+		return fmt.Errorf("team %s: %w", user.TeamName, domain.ErrNotFound)
+	}
+	u := copyUser(user)
+	// Рабочие часы задаются отдельно, Upsert их не трогает
+	u.WorkingHours = nil
+	if old, ok := r.s.users[user.ID]; ok {
+		u.WorkingHours = old.WorkingHours
+	}
+	r.s.users[user.ID] = u
+	return nil
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, id string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	u, ok := r.s.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+	return copyUser(u), nil
+}
+
+func (r *UserRepo) ListByTeam(ctx context.Context, teamName string, activeOnly bool) ([]*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var users []*models.User
+	for _, u := range r.s.users {
+		if u.TeamName != teamName || (activeOnly && !u.IsActive) {
+			continue
+		}
+		users = append(users, copyUser(u))
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+func (r *UserRepo) SetActive(ctx context.Context, id string, active bool, audit models.Audit) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[id]
+	if !ok {
+		return fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+	if u.IsActive && !active {
+		r.s.appendOutbox(models.OutboxEvent{Type: models.OutboxUserDeactivated, UserID: id, Actor: audit.Actor, Reason: audit.Reason})
+	}
+	u.IsActive = active
+	return nil
+}
+
+func (r *UserRepo) SetWorkingHours(ctx context.Context, id string, wh *models.WorkingHours) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[id]
+	if !ok {
+		return fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+	u.WorkingHours = nil
+	if wh != nil {
+		c := *wh
+		u.WorkingHours = &c
+	}
+	return nil
+}
+
+func (r *UserRepo) GetStats(ctx context.Context) ([]models.UserStat, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	total := make(map[string]int)
+	for _, pr := range r.s.prs {
+		for _, id := range pr.Reviewers {
+			total[id]++
+		}
+	}
+	open := r.s.openReviewCounts()
+
+	stats := make([]models.UserStat, 0, len(r.s.users))
+	for _, u := range r.s.users {
+		stats = append(stats, models.UserStat{
+			Username:        u.Name,
+			ReviewCount:     total[u.ID],
+			OpenReviewCount: open[u.ID],
+		})
+	}
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].ReviewCount > stats[j].ReviewCount })
+	return stats, nil
+}
+
+func (r *UserRepo) DeactivateWithReassign(ctx context.Context, teamName string, userIDs []string, plan repo.ReassignPlanner, audit models.Audit) (*models.DeactivationResult, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	result := &models.DeactivationResult{}
+	for _, u := range r.s.users {
+		if slices.Contains(userIDs, u.ID) || (teamName != "" && u.TeamName == teamName) {
+			result.Deactivated = append(result.Deactivated, u.ID)
+		}
+	}
+	sort.Strings(result.Deactivated)
+	for _, id := range result.Deactivated {
+		if u := r.s.users[id]; u.IsActive {
+			u.IsActive = false
+			r.s.appendOutbox(models.OutboxEvent{Type: models.OutboxUserDeactivated, UserID: id, Actor: audit.Actor, Reason: audit.Reason})
+		}
+	}
+
+	result.Changes = r.s.reassignReviews(result.Deactivated, plan, audit)
+	return result, nil
+}
+
+func (r *UserRepo) DetachWithReassign(ctx context.Context, teamName string, userIDs []string, plan repo.ReassignPlanner, audit models.Audit) (*models.DeactivationResult, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.s.detachMembers(teamName, userIDs, plan, audit), nil
+}
+
+func (r *UserRepo) MoveTeam(ctx context.Context, userID, toTeam string, handover bool, plan repo.ReassignPlanner, audit models.Audit) (*models.TeamMove, []models.ReviewerChange, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[userID]
+	if !ok {
+		return nil, nil, fmt.Errorf("user %s: %w", userID, domain.ErrNotFound)
+	}
+	if _, ok := r.s.teams[toTeam]; !ok {
+		return nil, nil, fmt.Errorf("team %s: %w", toTeam, domain.ErrNotFound)
+	}
+
+	r.s.lastMoveID++
+	move := models.TeamMove{
+		ID:        r.s.lastMoveID,
+		UserID:    userID,
+		FromTeam:  u.TeamName,
+		ToTeam:    toTeam,
+		Handover:  handover,
+		Actor:     audit.Actor,
+		CreatedAt: time.Now(),
+	}
+	u.TeamName = toTeam
+
+	var changes []models.ReviewerChange
+	if handover {
+		changes = r.s.reassignReviews([]string{userID}, plan, audit)
+	}
+	r.s.moves = append(r.s.moves, move)
+	return &move, changes, nil
+}
+
+func (r *UserRepo) ListMoves(ctx context.Context, userID string) ([]models.TeamMove, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var moves []models.TeamMove
+	for _, m := range r.s.moves {
+		if m.UserID == userID {
+			moves = append(moves, m)
+		}
+	}
+	return moves, nil
+}
+
+func (r *UserRepo) LinkIdentity(ctx context.Context, platform, login, userID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[userID]; !ok {
+		return fmt.Errorf("user %s: %w", userID, domain.ErrNotFound)
+	}
+	if r.s.identities[platform] == nil {
+		r.s.identities[platform] = make(map[string]string)
+	}
+	r.s.identities[platform][login] = userID
+	return nil
+}
+
+func (r *UserRepo) FindByIdentity(ctx context.Context, platform, login string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	userID, ok := r.s.identities[platform][login]
+	if !ok {
+		return nil, fmt.Errorf("%s login %s: %w", platform, login, domain.ErrNotFound)
+	}
+	return copyUser(r.s.users[userID]), nil
+}
+
+// detachMembers убирает из команды перечисленных (nil — всех) участников и
+// переназначает их ревью в OPEN PR. Вызывать под блокировкой.
+func (s *Store) detachMembers(teamName string, userIDs []string, plan repo.ReassignPlanner, audit models.Audit) *models.DeactivationResult {
+	result := &models.DeactivationResult{}
+	for _, u := range s.users {
+		if u.TeamName == teamName && (userIDs == nil || slices.Contains(userIDs, u.ID)) {
+			u.TeamName = ""
+			result.Deactivated = append(result.Deactivated, u.ID)
+		}
+	}
+	sort.Strings(result.Deactivated)
+
+	result.Changes = s.reassignReviews(result.Deactivated, plan, audit)
+	return result
+}
+
+// reassignReviews переназначает ревью userIDs в OPEN PR по plan. Замена ищется
+// в команде автора PR, а для запасного ревьюера — в запасных командах команды
+// автора. Вызывать под блокировкой.
+func (s *Store) reassignReviews(userIDs []string, plan repo.ReassignPlanner, audit models.Audit) []models.ReviewerChange {
+	var reviews []models.AffectedReview
+	for _, pr := range s.prs {
+		if pr.Status != models.StatusOpen {
+			continue
+		}
+		for _, id := range pr.Reviewers {
+			if !slices.Contains(userIDs, id) {
+				continue
+			}
+			var authorTeam string
+			if author, ok := s.users[pr.AuthorID]; ok {
+				authorTeam = author.TeamName
+			}
+			reviews = append(reviews, models.AffectedReview{
+				PRID:       pr.ID,
+				AuthorID:   pr.AuthorID,
+				ReviewerID: id,
+				AuthorTeam: authorTeam,
+				Fallback:   pr.Fallback[id],
+				Reviewers:  append([]string(nil), pr.Reviewers...),
+			})
+		}
+	}
+	if len(reviews) == 0 {
+		return nil
+	}
+	slices.SortFunc(reviews, func(a, b models.AffectedReview) int {
+		if c := strings.Compare(a.PRID, b.PRID); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ReviewerID, b.ReviewerID)
+	})
+
+	counts := s.openReviewCounts()
+	settings := make(map[string]*models.TeamSettings)
+	candidates := make(map[string][]models.ReviewCandidate)
+	for _, rv := range reviews {
+		if ts, ok := s.settings[rv.AuthorTeam]; ok {
+			settings[rv.AuthorTeam] = copySettings(ts)
+		}
+		for _, team := range models.ReplacementTeams(rv.AuthorTeam, rv.Fallback, settings[rv.AuthorTeam]) {
+			if _, ok := candidates[team]; !ok {
+				candidates[team] = s.candidates(team, nil, counts)
+			}
+		}
+	}
+
+	changes := plan(reviews, settings, candidates)
+
+	for _, c := range changes {
+		pr := s.prs[c.PRID]
+		i := slices.Index(pr.Reviewers, c.OldReviewerID)
+		if i < 0 {
+			continue
+		}
+		rec := models.AssignmentRecord{
+			PRID:          c.PRID,
+			ReviewerID:    c.OldReviewerID,
+			NewReviewerID: c.NewReviewerID,
+			Action:        models.ActionReassigned,
+			Actor:         audit.Actor,
+			Reason:        audit.Reason,
+		}
+		if c.NewReviewerID == "" {
+			rec.Action = models.ActionRemoved
+			pr.Reviewers = slices.Delete(pr.Reviewers, i, i+1)
+		} else {
+			pr.Reviewers[i] = c.NewReviewerID
+		}
+		replaceAssignment(pr, c.OldReviewerID, c.NewReviewerID)
+		s.appendHistory(rec)
+		s.appendOutbox(models.ReviewerEvents([]models.AssignmentRecord{rec})...)
+	}
+	return changes
+}
@@ -0,0 +1,274 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+type WebhookRepo struct {
+	s *Store
+}
+
+func NewWebhookRepo(s *Store) *WebhookRepo {
+	return &WebhookRepo{s: s}
+}
+
+func (r *WebhookRepo) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.lastSubscriptionID++
+	sub.ID = r.s.lastSubscriptionID
+	sub.CreatedAt = time.Now()
+	r.s.subscriptions = append(r.s.subscriptions, copySubscription(sub))
+	return nil
+}
+
+func (r *WebhookRepo) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	sub := r.s.subscription(id)
+	if sub == nil {
+		return nil, fmt.Errorf("webhook %d: %w", id, domain.ErrNotFound)
+	}
+	return copySubscription(sub), nil
+}
+
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	subs := make([]models.WebhookSubscription, len(r.s.subscriptions))
+	for i, sub := range r.s.subscriptions {
+		subs[i] = *copySubscription(sub)
+	}
+	return subs, nil
+}
+
+func (r *WebhookRepo) UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := r.s.subscription(sub.ID)
+	if stored == nil {
+		return fmt.Errorf("webhook %d: %w", sub.ID, domain.ErrNotFound)
+	}
+	createdAt := stored.CreatedAt
+	*stored = *copySubscription(sub)
+	stored.CreatedAt = createdAt
+	return nil
+}
+
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	i := slices.IndexFunc(r.s.subscriptions, func(s *models.WebhookSubscription) bool { return s.ID == id })
+	if i < 0 {
+		return fmt.Errorf("webhook %d: %w", id, domain.ErrNotFound)
+	}
+	r.s.subscriptions = slices.Delete(r.s.subscriptions, i, i+1)
+	// Как ON DELETE CASCADE
+	r.s.deliveries = slices.DeleteFunc(r.s.deliveries, func(d *models.WebhookDelivery) bool { return d.SubscriptionID == id })
+	r.s.deadLetters = slices.DeleteFunc(r.s.deadLetters, func(l *models.DeadLetter) bool { return l.SubscriptionID == id })
+	return nil
+}
+
+func (r *WebhookRepo) Enqueue(ctx context.Context, eventID int64, event string, payload []byte) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	n := 0
+	for _, sub := range r.s.subscriptions {
+		key := deliveryKey{subscriptionID: sub.ID, eventID: eventID}
+		if !sub.Wants(event) || r.s.enqueuedEvents[key] {
+			continue
+		}
+		r.s.enqueuedEvents[key] = true
+		r.s.lastDeliveryID++
+		r.s.deliveries = append(r.s.deliveries, &models.WebhookDelivery{
+			ID:             r.s.lastDeliveryID,
+			SubscriptionID: sub.ID,
+			EventID:        eventID,
+			Event:          event,
+			Payload:        slices.Clone(payload),
+			Status:         models.DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+		n++
+	}
+	return n, nil
+}
+
+func (r *WebhookRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var due []*models.WebhookDelivery
+	for _, d := range r.s.deliveries {
+		if sub := r.s.subscription(d.SubscriptionID); d.Status == models.DeliveryPending && !d.NextAttemptAt.After(now) && sub.Active {
+			due = append(due, d)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b *models.WebhookDelivery) int { return a.NextAttemptAt.Compare(b.NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	slices.SortFunc(due, func(a, b *models.WebhookDelivery) int { return cmp.Compare(a.ID, b.ID) })
+
+	out := make([]models.WebhookDelivery, len(due))
+	for i, d := range due {
+		d.NextAttemptAt = leaseUntil
+		sub := r.s.subscription(d.SubscriptionID)
+		out[i] = copyDelivery(d)
+		out[i].URL, out[i].Secret = sub.URL, sub.Secret
+	}
+	return out, nil
+}
+
+func (r *WebhookRepo) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	d := r.s.pendingDelivery(id)
+	if d == nil {
+		return fmt.Errorf("pending delivery %d: %w", id, domain.ErrNotFound)
+	}
+	now := time.Now()
+	d.Status = models.DeliveryDelivered
+	d.Attempts++
+	d.LastStatusCode = &statusCode
+	d.LastError = ""
+	d.DeliveredAt = &now
+	return nil
+}
+
+func (r *WebhookRepo) MarkFailed(ctx context.Context, id int64, statusCode *int, lastError string, next *time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	d := r.s.pendingDelivery(id)
+	if d == nil {
+		return fmt.Errorf("pending delivery %d: %w", id, domain.ErrNotFound)
+	}
+	d.Attempts++
+	d.LastStatusCode = nil
+	if statusCode != nil {
+		code := *statusCode
+		d.LastStatusCode = &code
+	}
+	d.LastError = lastError
+	if next != nil {
+		d.NextAttemptAt = *next
+		return nil
+	}
+
+	d.Status = models.DeliveryDead
+	r.s.lastDeadLetterID++
+	r.s.deadLetters = append(r.s.deadLetters, &models.DeadLetter{
+		ID:             r.s.lastDeadLetterID,
+		DeliveryID:     d.ID,
+		SubscriptionID: d.SubscriptionID,
+		Event:          d.Event,
+		Payload:        slices.Clone(d.Payload),
+		Attempts:       d.Attempts,
+		LastError:      d.LastError,
+		CreatedAt:      time.Now(),
+	})
+	return nil
+}
+
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var out []models.WebhookDelivery
+	for i := len(r.s.deliveries) - 1; i >= 0 && len(out) < limit; i-- {
+		if d := r.s.deliveries[i]; d.SubscriptionID == subscriptionID {
+			out = append(out, copyDelivery(d))
+		}
+	}
+	return out, nil
+}
+
+func (r *WebhookRepo) ListDeadLetters(ctx context.Context) ([]models.DeadLetter, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	out := make([]models.DeadLetter, len(r.s.deadLetters))
+	for i, l := range r.s.deadLetters {
+		out[i] = *l
+		out[i].Payload = slices.Clone(l.Payload)
+	}
+	return out, nil
+}
+
+func (r *WebhookRepo) Redeliver(ctx context.Context, deadLetterID int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	i := slices.IndexFunc(r.s.deadLetters, func(l *models.DeadLetter) bool { return l.ID == deadLetterID })
+	if i < 0 {
+		return fmt.Errorf("dead letter %d: %w", deadLetterID, domain.ErrNotFound)
+	}
+	deliveryID := r.s.deadLetters[i].DeliveryID
+	r.s.deadLetters = slices.Delete(r.s.deadLetters, i, i+1)
+	for _, d := range r.s.deliveries {
+		if d.ID == deliveryID {
+			d.Status = models.DeliveryPending
+			d.Attempts = 0
+			d.NextAttemptAt = time.Now()
+			d.LastStatusCode = nil
+			d.LastError = ""
+		}
+	}
+	return nil
+}
+
+// subscription ищет подписку по ID. Вызывать под блокировкой.
+func (s *Store) subscription(id int64) *models.WebhookSubscription {
+	i := slices.IndexFunc(s.subscriptions, func(sub *models.WebhookSubscription) bool { return sub.ID == id })
+	if i < 0 {
+		return nil
+	}
+	return s.subscriptions[i]
+}
+
+// pendingDelivery ищет ожидающую доставку по ID. Вызывать под блокировкой.
+func (s *Store) pendingDelivery(id int64) *models.WebhookDelivery {
+	for _, d := range s.deliveries {
+		if d.ID == id && d.Status == models.DeliveryPending {
+			return d
+		}
+	}
+	return nil
+}
+
+func copySubscription(sub *models.WebhookSubscription) *models.WebhookSubscription {
+	c := *sub
+	c.Events = slices.Clone(sub.Events)
+	return &c
+}
+
+func copyDelivery(d *models.WebhookDelivery) models.WebhookDelivery {
+	c := *d
+	c.Payload = slices.Clone(d.Payload)
+	if d.LastStatusCode != nil {
+		code := *d.LastStatusCode
+		c.LastStatusCode = &code
+	}
+	if d.DeliveredAt != nil {
+		t := *d.DeliveredAt
+		c.DeliveredAt = &t
+	}
+	return c
+}
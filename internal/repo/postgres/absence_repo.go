@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// notAway — условие на users u: пользователь сейчас не в отсутствии.
+const notAway = `NOT EXISTS (
+		      SELECT 1 FROM absences a
+		      WHERE a.user_id = u.id AND a.starts_at <= NOW() AND a.ends_at > NOW()
+		  )`
+
+type AbsenceRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAbsenceRepo(pool *pgxpool.Pool) *AbsenceRepo {
+	return &AbsenceRepo{pool: pool}
+}
+
+func (r *AbsenceRepo) Create(ctx context.Context, a *models.Absence) error {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO absences (user_id, starts_at, ends_at, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, a.UserID, a.StartsAt, a.EndsAt, a.Reason).Scan(&a.ID, &a.CreatedAt)
+	return mapMissingRef(err, ref{"absences_user_id_fkey", "user " + a.UserID})
+}
+
+func (r *AbsenceRepo) ListByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	return r.list(ctx, "WHERE user_id = $1 ORDER BY starts_at, id", userID)
+}
+
+func (r *AbsenceRepo) Delete(ctx context.Context, id int64) error {
+	cmd, err := r.pool.Exec(ctx, "DELETE FROM absences WHERE id=$1", id)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("absence %d: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *AbsenceRepo) ListStarted(ctx context.Context, now time.Time) ([]models.Absence, error) {
+	return r.list(ctx, "WHERE NOT handled AND starts_at <= $1 AND ends_at > $1 ORDER BY starts_at, id", now)
+}
+
+func (r *AbsenceRepo) MarkHandled(ctx context.Context, id int64) error {
+	_, err := r.pool.Exec(ctx, "UPDATE absences SET handled = TRUE WHERE id=$1", id)
+	return err
+}
+
+func (r *AbsenceRepo) list(ctx context.Context, where string, args ...any) ([]models.Absence, error) {
+	rows, err := r.pool.Query(ctx, "SELECT id, user_id, starts_at, ends_at, reason, handled, created_at FROM absences "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var absences []models.Absence
+	for rows.Next() {
+		var a models.Absence
+		if err := rows.Scan(&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason, &a.Handled, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		absences = append(absences, a)
+	}
+	return absences, rows.Err()
+}
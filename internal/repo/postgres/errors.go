@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+// mapError переводит ошибки Postgres в доменные, сохраняя исходную в цепочке.
+// Нарушение внешнего ключа сюда не относится: без контекста вызова это ошибка
+// хранилища, а не «не найдено» (см. mapMissingRef).
+func mapError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	if pgErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: %w", domain.ErrAlreadyExists, err)
+	}
+	return err
+}
+
+// ref — внешний ключ, через который вызов ссылается на переданную ему сущность.
+type ref struct {
+	constraint string
+	what       string
+}
+
+// mapMissingRef — как mapError, но нарушение одного из перечисленных внешних
+// ключей означает ссылку на несуществующую сущность и даёт domain.ErrNotFound.
+func mapMissingRef(err error, refs ...ref) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		for _, r := range refs {
+			if pgErr.ConstraintName == r.constraint {
+				return fmt.Errorf("%s: %w: %w", r.what, domain.ErrNotFound, err)
+			}
+		}
+	}
+	return mapError(err)
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestMapMissingRef(t *testing.T) {
+	fk := &pgconn.PgError{Code: foreignKeyViolation, ConstraintName: "users_team_name_fkey"}
+	if err := mapMissingRef(fk, ref{"users_team_name_fkey", "team ghost"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("named constraint: want ErrNotFound, got %v", err)
+	}
+	// Чужой внешний ключ — ошибка хранилища, не 404
+	if err := mapMissingRef(fk, ref{"absences_user_id_fkey", "user u1"}); errors.Is(err, domain.ErrNotFound) || !errors.Is(err, fk) {
+		t.Fatalf("other constraint: %v", err)
+	}
+	if err := mapError(fk); errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("mapError must not map FK violations: %v", err)
+	}
+	unique := &pgconn.PgError{Code: uniqueViolation}
+	if err := mapMissingRef(unique); !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Fatalf("unique: want ErrAlreadyExists, got %v", err)
+	}
+}
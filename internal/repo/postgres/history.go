@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// appendHistory пишет записи истории назначений одним запросом в рамках tx.
+func appendHistory(ctx context.Context, tx pgx.Tx, records []models.AssignmentRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	n := len(records)
+	prIDs, reviewerIDs, newIDs := make([]string, n), make([]string, n), make([]string, n)
+	actions, actors, reasons := make([]string, n), make([]string, n), make([]string, n)
+	for i, rec := range records {
+		prIDs[i] = rec.PRID
+		reviewerIDs[i] = rec.ReviewerID
+		newIDs[i] = rec.NewReviewerID
+		actions[i] = rec.Action
+		actors[i] = rec.Actor
+		reasons[i] = rec.Reason
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO reviewer_assignments (pr_id, reviewer_id, new_reviewer_id, action, actor, reason)
+		SELECT h.pr_id, h.reviewer_id, NULLIF(h.new_id, ''), h.action, h.actor, h.reason
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[])
+		     AS h(pr_id, reviewer_id, new_id, action, actor, reason)
+	`, prIDs, reviewerIDs, newIDs, actions, actors, reasons)
+	return err
+}
+
+func (r *PRRepo) ListHistory(ctx context.Context, prID string) ([]models.AssignmentRecord, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, pr_id, reviewer_id, COALESCE(new_reviewer_id, ''), action, actor, reason, created_at
+		FROM reviewer_assignments
+		WHERE pr_id = $1
+		ORDER BY id
+	`, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []models.AssignmentRecord
+	for rows.Next() {
+		var rec models.AssignmentRecord
+		if err := rows.Scan(&rec.ID, &rec.PRID, &rec.ReviewerID, &rec.NewReviewerID, &rec.Action, &rec.Actor, &rec.Reason, &rec.CreatedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type NotificationRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotificationRepo(pool *pgxpool.Pool) *NotificationRepo {
+	return &NotificationRepo{pool: pool}
+}
+
+func (r *NotificationRepo) GetPrefs(ctx context.Context, userID string) (*models.NotificationPrefs, error) {
+	p := &models.NotificationPrefs{UserID: userID, Mode: models.NotifyInstant}
+	err := r.pool.QueryRow(ctx, "SELECT mode, channel FROM notification_preferences WHERE user_id=$1", userID).Scan(&p.Mode, &p.Channel)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *NotificationRepo) SetPrefs(ctx context.Context, p *models.NotificationPrefs) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO notification_preferences (user_id, mode, channel)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			mode = EXCLUDED.mode,
+			channel = EXCLUDED.channel,
+			last_digest_at = CASE WHEN notification_preferences.mode <> 'digest' AND EXCLUDED.mode = 'digest'
+			                      THEN NOW() ELSE notification_preferences.last_digest_at END
+	`, p.UserID, p.Mode, p.Channel)
+	return mapMissingRef(err, ref{"notification_preferences_user_id_fkey", "user " + p.UserID})
+}
+
+// Enqueue отмечает событие в notification_events в той же транзакции, что и
+// уведомления: повтор события после отправки не поставит их снова.
+func (r *NotificationRepo) Enqueue(ctx context.Context, eventID int64, items []models.Notification) error {
+	if len(items) == 0 {
+		return nil
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, "INSERT INTO notification_events (event_id) VALUES ($1) ON CONFLICT DO NOTHING", eventID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+	n := len(items)
+	users, channels, texts, digests := make([]string, n), make([]string, n), make([]string, n), make([]bool, n)
+	for i, it := range items {
+		users[i], channels[i], texts[i], digests[i] = it.UserID, it.Channel, it.Text, it.Digest
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO notifications (user_id, channel, text, digest)
+		SELECT n.user_id, n.channel, n.text, n.digest
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::bool[]) WITH ORDINALITY AS n(user_id, channel, text, digest, i)
+		ORDER BY n.i
+	`, users, channels, texts, digests)
+	if err != nil {
+		return mapMissingRef(err, ref{"notifications_user_id_fkey", "recipient"})
+	}
+	return tx.Commit(ctx)
+}
+
+// SendPending держит выбранные строки заблокированными, пока идёт отправка:
+// другой экземпляр их пропустит (SKIP LOCKED).
+func (r *NotificationRepo) SendPending(ctx context.Context, digestBefore time.Time, limit, maxAttempts int, send repo.NotificationSender) (int, int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	instant, err := queryNotifications(ctx, tx, `
+		SELECT id, user_id, channel, text, digest, attempts, created_at
+		FROM notifications
+		WHERE NOT digest
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		return 0, 0, err
+	}
+	digests, err := queryNotifications(ctx, tx, `
+		SELECT n.id, n.user_id, n.channel, n.text, n.digest, n.attempts, n.created_at
+		FROM notifications n
+		JOIN notification_preferences p ON p.user_id = n.user_id
+		WHERE n.digest AND p.last_digest_at <= $1
+		ORDER BY n.user_id, n.id
+		FOR UPDATE OF n, p SKIP LOCKED
+	`, digestBefore)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var batches [][]models.Notification
+	for _, n := range instant {
+		batches = append(batches, []models.Notification{n})
+	}
+	batches = append(batches, groupByUser(digests)...)
+
+	var sentIDs, failedIDs []int64
+	var digested []string
+	sent, failed := 0, 0
+	for _, batch := range batches {
+		ids := make([]int64, len(batch))
+		for i, n := range batch {
+			ids[i] = n.ID
+		}
+		if err := send(ctx, batch); err != nil {
+			failed++
+			failedIDs = append(failedIDs, ids...)
+			continue
+		}
+		sent++
+		sentIDs = append(sentIDs, ids...)
+		if batch[0].Digest {
+			digested = append(digested, batch[0].UserID)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM notifications WHERE id = ANY($1)", sentIDs); err != nil {
+		return 0, 0, err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE notifications SET attempts = attempts + 1 WHERE id = ANY($1)", failedIDs); err != nil {
+		return 0, 0, err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM notifications WHERE id = ANY($1) AND attempts >= $2", failedIDs, maxAttempts); err != nil {
+		return 0, 0, err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE notification_preferences SET last_digest_at = NOW() WHERE user_id = ANY($1)", digested); err != nil {
+		return 0, 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, err
+	}
+	return sent, failed, nil
+}
+
+func queryNotifications(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]models.Notification, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.Notification
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Channel, &n.Text, &n.Digest, &n.Attempts, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, n)
+	}
+	return items, rows.Err()
+}
+
+// groupByUser режет уведомления, упорядоченные по пользователю, на дайджесты.
+func groupByUser(items []models.Notification) [][]models.Notification {
+	var groups [][]models.Notification
+	for i, n := range items {
+		if i == 0 || n.UserID != items[i-1].UserID {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], n)
+	}
+	return groups
+}
@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Аренда relay в outbox_relays: события для получателя публикует один экземпляр
+// за раз, иначе порядок между экземплярами не сохранится. publish получает срок
+// на outboxLeaseMargin короче аренды, чтобы успеть отметить отправленное.
+const (
+	outboxLease       = time.Minute
+	outboxLeaseMargin = 10 * time.Second
+)
+
+// outboxColumns — колонка отметки публикации для каждого получателя
+var outboxColumns = map[string]string{
+	models.OutboxConsumerInternal: "published_at",
+	models.OutboxConsumerExternal: "exported_at",
+}
+
+// outboxChannel — канал NOTIFY, в который пишет триггер на outbox
+const outboxChannel = "outbox_events"
+
+// appendOutbox пишет доменные события одним запросом в рамках tx.
+func appendOutbox(ctx context.Context, tx pgx.Tx, events []models.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	n := len(events)
+	types, prIDs, userIDs, reviewerIDs := make([]string, n), make([]string, n), make([]string, n), make([]string, n)
+	newIDs, actors, reasons := make([]string, n), make([]string, n), make([]string, n)
+	for i, ev := range events {
+		types[i] = ev.Type
+		prIDs[i] = ev.PRID
+		userIDs[i] = ev.UserID
+		reviewerIDs[i] = ev.ReviewerID
+		newIDs[i] = ev.NewReviewerID
+		actors[i] = ev.Actor
+		reasons[i] = ev.Reason
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO outbox (event_type, pr_id, user_id, reviewer_id, new_reviewer_id, actor, reason)
+		SELECT e.type, NULLIF(e.pr_id, ''), NULLIF(e.user_id, ''), NULLIF(e.reviewer_id, ''), NULLIF(e.new_id, ''), e.actor, e.reason
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[]) WITH ORDINALITY
+		     AS e(type, pr_id, user_id, reviewer_id, new_id, actor, reason, n)
+		ORDER BY e.n
+	`, types, prIDs, userIDs, reviewerIDs, newIDs, actors, reasons)
+	return err
+}
+
+type OutboxRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepo(pool *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{pool: pool}
+}
+
+func (r *OutboxRepo) PublishPending(ctx context.Context, consumer string, limit int, publish repo.OutboxPublisher) (int, error) {
+	column, ok := outboxColumns[consumer]
+	if !ok {
+		return 0, fmt.Errorf("unknown outbox consumer %q", consumer)
+	}
+
+	holder, err := newHolder()
+	if err != nil {
+		return 0, err
+	}
+	tag, err := r.pool.Exec(ctx, `
+		INSERT INTO outbox_relays (consumer, holder, leased_until)
+		VALUES ($1, $2, NOW() + make_interval(secs => $3))
+		ON CONFLICT (consumer) DO UPDATE SET holder = EXCLUDED.holder, leased_until = EXCLUDED.leased_until
+		WHERE outbox_relays.leased_until < NOW()
+	`, consumer, holder, outboxLease.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, nil
+	}
+	// Аренду снимаем и после отмены ctx, иначе следующий запуск ждёт её истечения.
+	defer func() {
+		_, _ = r.pool.Exec(context.WithoutCancel(ctx), "DELETE FROM outbox_relays WHERE consumer = $1 AND holder = $2", consumer, holder)
+	}()
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, event_type, COALESCE(pr_id, ''), COALESCE(user_id, ''), COALESCE(reviewer_id, ''),
+		       COALESCE(new_reviewer_id, ''), actor, reason, created_at
+		FROM outbox
+		WHERE `+column+` IS NULL
+		ORDER BY id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return 0, err
+	}
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var ev models.OutboxEvent
+		if err := rows.Scan(&ev.ID, &ev.Type, &ev.PRID, &ev.UserID, &ev.ReviewerID, &ev.NewReviewerID, &ev.Actor, &ev.Reason, &ev.CreatedAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	// publish не должен пережить аренду: после неё батч может взять другой экземпляр.
+	pubCtx, cancel := context.WithTimeout(ctx, outboxLease-outboxLeaseMargin)
+	n, pubErr := publish(pubCtx, events)
+	cancel()
+
+	// Отправленное помечается и при ошибке: иначе sinks получат его ещё раз.
+	// Помечаются именно выбранные id: событие с меньшим id из транзакции, которая
+	// ещё не завершилась, в выборку не попало и должно остаться неопубликованным.
+	if n > 0 {
+		ids := make([]int64, n)
+		for i := range ids {
+			ids[i] = events[i].ID
+		}
+		if _, err := r.pool.Exec(context.WithoutCancel(ctx), "UPDATE outbox SET "+column+" = NOW() WHERE id = ANY($1)", ids); err != nil {
+			return 0, err
+		}
+	}
+	return n, pubErr
+}
+
+// newHolder — случайный идентификатор владельца аренды
+func newHolder() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ListAfter берёт автора, его команду и ревьюеров из текущего состояния PR: у
+// удалённого PR они пустые.
+func (r *OutboxRepo) ListAfter(ctx context.Context, afterID int64, limit int) ([]models.FeedEvent, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT o.id, o.event_type, COALESCE(o.pr_id, ''), COALESCE(o.user_id, ''), COALESCE(o.reviewer_id, ''),
+		       COALESCE(o.new_reviewer_id, ''), o.actor, o.reason, o.created_at,
+		       COALESCE(p.author_id, ''), COALESCE(a.team_name, ''),
+		       COALESCE((SELECT array_agg(rev.reviewer_id ORDER BY rev.reviewer_id)
+		                 FROM pr_reviewers rev WHERE rev.pr_id = o.pr_id), '{}')
+		FROM outbox o
+		LEFT JOIN pull_requests p ON p.id = o.pr_id
+		LEFT JOIN users a ON a.id = p.author_id
+		WHERE o.id > $1
+		ORDER BY o.id
+		LIMIT $2
+	`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.FeedEvent
+	for rows.Next() {
+		var ev models.FeedEvent
+		if err := rows.Scan(&ev.ID, &ev.Type, &ev.PRID, &ev.UserID, &ev.ReviewerID, &ev.NewReviewerID, &ev.Actor, &ev.Reason, &ev.CreatedAt,
+			&ev.AuthorID, &ev.TeamName, &ev.Reviewers); err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+func (r *OutboxRepo) LastID(ctx context.Context) (int64, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx, "SELECT COALESCE(MAX(id), 0) FROM outbox").Scan(&id)
+	return id, err
+}
+
+// Notify держит отдельное соединение с LISTEN; в пул оно не возвращается.
+func (r *OutboxRepo) Notify(ctx context.Context) (<-chan struct{}, error) {
+	pc, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn := pc.Hijack()
+	if _, err := conn.Exec(ctx, "LISTEN "+outboxChannel); err != nil {
+		_ = conn.Close(context.Background())
+		return nil, err
+	}
+
+	ch := make(chan struct{}, 1)
+	go func() {
+		defer close(ch)
+		defer func() { _ = conn.Close(context.Background()) }()
+		for {
+			if _, err := conn.WaitForNotification(ctx); err != nil {
+				return
+			}
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
 	"github.com/humooo/avito-backend-trainee-2025/internal/models"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -18,7 +21,7 @@ func NewPRRepo(pool *pgxpool.Pool) *PRRepo {
 	return &PRRepo{pool: pool}
 }
 
-func (r *PRRepo) CreateWithReviewers(ctx context.Context, pr *models.PullRequest) error {
+func (r *PRRepo) CreateWithReviewers(ctx context.Context, pr *models.PullRequest, audit models.Audit) error {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -27,37 +30,132 @@ func (r *PRRepo) CreateWithReviewers(ctx context.Context, pr *models.PullRequest
 
 	_, err = tx.Exec(ctx, "INSERT INTO pull_requests (id, title, author_id, status) VALUES ($1, $2, $3, $4)",
 		pr.ID, pr.Title, pr.AuthorID, pr.Status)
+	if err != nil {
+		return mapMissingRef(err, ref{"pull_requests_author_id_fkey", "author " + pr.AuthorID})
+	}
+	if err := appendOutbox(ctx, tx, []models.OutboxEvent{{Type: models.OutboxPRCreated, PRID: pr.ID, UserID: pr.AuthorID, Actor: audit.Actor}}); err != nil {
+		return err
+	}
+
+	if err := assignReviewers(ctx, tx, pr, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PRRepo) OpenWithReviewers(ctx context.Context, pr *models.PullRequest, from string, audit models.Audit) error {
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, "UPDATE pull_requests SET status='OPEN' WHERE id=$1 AND status=$2", pr.ID, from)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pr %s is not %s: %w", pr.ID, from, domain.ErrInvalidState)
+	}
+
+	if err := assignReviewers(ctx, tx, pr, audit); err != nil {
+		return err
+	}
+	pr.Status = models.StatusOpen
+
+	return tx.Commit(ctx)
+}
+
+// underLimit — условие на users u: у пользователя есть место под ещё одно ревью.
+const underLimit = `(u.max_open_reviews IS NULL OR u.max_open_reviews > (
+		      SELECT COUNT(*)
+		      FROM pr_reviewers rev
+		      JOIN pull_requests p ON p.id = rev.pr_id
+		      WHERE rev.reviewer_id = u.id AND p.status = 'OPEN'
+		  ))`
+
+// assignReviewers добавляет к PR ревьюеров из pr.Reviewers и пишет историю.
+// Ревьюеров выбирает сервис, но вставляем только тех, кто всё ещё активен, не
+// отсутствует и не упёрся в лимит: состояние могло поменяться между выбором и записью.
+// В pr.Reviewers и pr.Fallback остаются только вставленные; если кого-то не вставили
+// из-за лимита, pr.ReviewerShortage — ShortageAtCapacity.
+func assignReviewers(ctx context.Context, tx pgx.Tx, pr *models.PullRequest, audit models.Audit) error {
+	reviewers, fallback := pr.Reviewers, pr.Fallback
+	pr.Reviewers, pr.Fallback, pr.ReviewerShortage = nil, nil, ""
+	if len(reviewers) == 0 {
+		return nil
+	}
 
+	var fallbackIDs []string
+	for _, id := range reviewers {
+		if fallback[id] {
+			fallbackIDs = append(fallbackIDs, id)
+		}
+	}
 	query := `
-		INSERT INTO pr_reviewers (pr_id, reviewer_id)
-		SELECT $1, u.id
+		INSERT INTO pr_reviewers (pr_id, reviewer_id, is_fallback)
+		SELECT $1, u.id, u.id = ANY($3)
 		FROM users u
-		JOIN users author ON author.id = $2
-		WHERE u.team_name = author.team_name
-		  AND u.id != $2
+		WHERE u.id = ANY($2)
 		  AND u.is_active = TRUE
-		ORDER BY RANDOM()
-		LIMIT 2
-		RETURNING reviewer_id
+		  AND ` + notAway + `
+		  AND ` + underLimit + `
+		RETURNING reviewer_id, is_fallback, assigned_at
 	`
-	rows, err := tx.Query(ctx, query, pr.ID, pr.AuthorID)
+	rows, err := tx.Query(ctx, query, pr.ID, reviewers, fallbackIDs)
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
 
+	pr.AssignedAt = make(map[string]time.Time)
 	for rows.Next() {
-		var rID string // ТЕПЕРЬ ЭТО STRING
-		if err := rows.Scan(&rID); err != nil {
+		var id string
+		var isFallback bool
+		var assignedAt time.Time
+		if err := rows.Scan(&id, &isFallback, &assignedAt); err != nil {
 			return err
 		}
-		pr.Reviewers = append(pr.Reviewers, rID)
+		pr.Reviewers = append(pr.Reviewers, id)
+		pr.AssignedAt[id] = assignedAt
+		if isFallback {
+			if pr.Fallback == nil {
+				pr.Fallback = make(map[string]bool)
+			}
+			pr.Fallback[id] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(pr.Reviewers) < len(reviewers) {
+		var capped bool
+		err := tx.QueryRow(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM users u
+				WHERE u.id = ANY($1) AND NOT u.id = ANY($2)
+				  AND u.is_active = TRUE
+				  AND `+notAway+`
+				  AND NOT `+underLimit+`
+			)
+		`, reviewers, pr.Reviewers).Scan(&capped)
+		if err != nil {
+			return err
+		}
+		if capped {
+			pr.ReviewerShortage = models.ShortageAtCapacity
+		}
 	}
 
-	return tx.Commit(ctx)
+	history := make([]models.AssignmentRecord, len(pr.Reviewers))
+	for i, id := range pr.Reviewers {
+		history[i] = models.AssignmentRecord{PRID: pr.ID, ReviewerID: id, Action: models.ActionAssigned, Actor: audit.Actor, Reason: audit.Reason}
+	}
+	if err := appendHistory(ctx, tx, history); err != nil {
+		return err
+	}
+	return appendOutbox(ctx, tx, models.ReviewerEvents(history))
 }
 
 func (r *PRRepo) GetByID(ctx context.Context, id string) (*models.PullRequest, error) {
@@ -65,40 +163,243 @@ func (r *PRRepo) GetByID(ctx context.Context, id string) (*models.PullRequest, e
 	err := r.pool.QueryRow(ctx, "SELECT id, title, author_id, status, created_at, merged_at FROM pull_requests WHERE id=$1", id).
 		Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("pr not found")
+		return nil, fmt.Errorf("pr %s: %w", id, domain.ErrNotFound)
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := r.pool.Query(ctx, "SELECT reviewer_id FROM pr_reviewers WHERE pr_id=$1", id)
+	// Порядок фиксирован, чтобы ответ (и его ETag) не менялся без изменений в PR
+	rows, err := r.pool.Query(ctx, `
+		SELECT reviewer_id, state, is_fallback, assigned_at, escalated_at IS NOT NULL
+		FROM pr_reviewers WHERE pr_id=$1
+		ORDER BY assigned_at, reviewer_id
+	`, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	pr.ReviewStates = make(map[string]string)
+	pr.Fallback = make(map[string]bool)
+	pr.AssignedAt = make(map[string]time.Time)
+	pr.Escalated = make(map[string]bool)
 	for rows.Next() {
-		var rID string
-		if err := rows.Scan(&rID); err != nil {
+		var rID, state string
+		var isFallback, escalated bool
+		var assignedAt time.Time
+		if err := rows.Scan(&rID, &state, &isFallback, &assignedAt, &escalated); err != nil {
 			return nil, err
 		}
 		pr.Reviewers = append(pr.Reviewers, rID)
+		pr.ReviewStates[rID] = state
+		pr.AssignedAt[rID] = assignedAt
+		if isFallback {
+			pr.Fallback[rID] = true
+		}
+		if escalated {
+			pr.Escalated[rID] = true
+		}
+	}
+	return pr, rows.Err()
+}
+
+func (r *PRRepo) Merge(ctx context.Context, id string, audit models.Audit) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Повторный merge ничего не меняет, в том числе merged_at и историю.
+	// DRAFT и CLOSED не мержатся — это отсекает сервис.
+	tag, err := tx.Exec(ctx, "UPDATE pull_requests SET status='MERGED', merged_at=NOW() WHERE id=$1 AND status = 'OPEN'", id)
+	if err != nil {
+		return err
 	}
-	return pr, nil
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO reviewer_assignments (pr_id, reviewer_id, action, actor, reason)
+		SELECT pr_id, reviewer_id, $2, $3, $4 FROM pr_reviewers WHERE pr_id = $1 ORDER BY reviewer_id
+	`, id, models.ActionReleased, audit.Actor, audit.Reason)
+	if err != nil {
+		return err
+	}
+	err = appendOutbox(ctx, tx, []models.OutboxEvent{{Type: models.OutboxPRMerged, PRID: id, Actor: audit.Actor, Reason: audit.Reason}})
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-func (r *PRRepo) Merge(ctx context.Context, id string) error {
-	_, err := r.pool.Exec(ctx, "UPDATE pull_requests SET status='MERGED', merged_at=NOW() WHERE id=$1", id)
-	return err
+func (r *PRRepo) Close(ctx context.Context, id string, audit models.Audit) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, "UPDATE pull_requests SET status='CLOSED' WHERE id=$1 AND status IN ('OPEN', 'DRAFT')", id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	// В отличие от merge, ревьюеры снимаются: при reopen назначение будет новым
+	_, err = tx.Exec(ctx, `
+		INSERT INTO reviewer_assignments (pr_id, reviewer_id, action, actor, reason)
+		SELECT pr_id, reviewer_id, $2, $3, $4 FROM pr_reviewers WHERE pr_id = $1 ORDER BY reviewer_id
+	`, id, models.ActionReleased, audit.Actor, audit.Reason)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM pr_reviewers WHERE pr_id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-func (r *PRRepo) ReplaceReviewer(ctx context.Context, prID, oldID, newID string) error {
-	tag, err := r.pool.Exec(ctx, "UPDATE pr_reviewers SET reviewer_id=$1 WHERE pr_id=$2 AND reviewer_id=$3", newID, prID, oldID)
+func (r *PRRepo) ReplaceReviewer(ctx context.Context, prID, oldID, newID string, audit models.Audit) error {
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Решение прежнего ревьюера к новому не переходит, срок SLA начинается заново.
+	// Замена берётся из той же команды, поэтому is_fallback остаётся как был.
+	// Как и в assignReviewers, замену пишем, только если она всё ещё активна, не
+	// отсутствует и не упёрлась в лимит.
+	tag, err := tx.Exec(ctx, `
+		UPDATE pr_reviewers
+		SET reviewer_id=u.id, state='PENDING', state_updated_at=NULL, assigned_at=NOW(), escalated_at=NULL
+		FROM users u
+		WHERE pr_reviewers.pr_id=$2 AND pr_reviewers.reviewer_id=$3
+		  AND u.id = $1
+		  AND u.is_active = TRUE
+		  AND `+notAway+`
+		  AND `+underLimit+`
+	`, newID, prID, oldID)
+	if err != nil {
+		return mapMissingRef(err, ref{"pr_reviewers_reviewer_id_fkey", "user " + newID})
+	}
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("reviewer not found")
+		var assigned, exists bool
+		err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM pr_reviewers WHERE pr_id=$1 AND reviewer_id=$2),
+			       EXISTS (SELECT 1 FROM users WHERE id=$3)
+		`, prID, oldID, newID).Scan(&assigned, &exists)
+		switch {
+		case err != nil:
+			return err
+		case !assigned:
+			return fmt.Errorf("reviewer %s on pr %s: %w", oldID, prID, domain.ErrNotFound)
+		case !exists:
+			return fmt.Errorf("user %s: %w", newID, domain.ErrNotFound)
+		}
+		return fmt.Errorf("user %s: %w", newID, domain.ErrNoCandidate)
+	}
+
+	history := []models.AssignmentRecord{{
+		PRID:          prID,
+		ReviewerID:    oldID,
+		NewReviewerID: newID,
+		Action:        models.ActionReassigned,
+		Actor:         audit.Actor,
+		Reason:        audit.Reason,
+	}}
+	if err := appendHistory(ctx, tx, history); err != nil {
+		return err
+	}
+	if err := appendOutbox(ctx, tx, models.ReviewerEvents(history)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PRRepo) SetReviewState(ctx context.Context, prID, reviewerID, state string) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE pr_reviewers SET state=$3, state_updated_at=NOW()
+		WHERE pr_id=$1 AND reviewer_id=$2
+	`, prID, reviewerID, state)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("reviewer %s on pr %s: %w", reviewerID, prID, domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *PRRepo) AddReviewer(ctx context.Context, prID, reviewerID string, audit models.Audit) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var status string
+	err = tx.QueryRow(ctx, "SELECT status FROM pull_requests WHERE id=$1 FOR UPDATE", prID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("pr %s: %w", prID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.StatusOpen {
+		return fmt.Errorf("pr %s is %s: %w", prID, status, domain.ErrInvalidState)
+	}
+
+	pr := &models.PullRequest{ID: prID, Reviewers: []string{reviewerID}}
+	if err := assignReviewers(ctx, tx, pr, audit); err != nil {
+		return mapError(err)
+	}
+	if len(pr.Reviewers) == 0 {
+		return fmt.Errorf("user %s: %w", reviewerID, domain.ErrNoCandidate)
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PRRepo) ListOverdue(ctx context.Context, teamName string, now time.Time) ([]models.OverdueReview, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT pr.id, pr.title, pr.author_id, u.team_name, rev.reviewer_id, rev.assigned_at, rev.escalated_at IS NOT NULL
+		FROM pr_reviewers rev
+		JOIN pull_requests pr ON pr.id = rev.pr_id AND pr.status = 'OPEN'
+		JOIN users u ON u.id = pr.author_id
+		JOIN team_settings s ON s.team_name = u.team_name AND s.review_sla_minutes > 0
+		WHERE rev.state = 'PENDING'
+		  AND rev.assigned_at + make_interval(mins => s.review_sla_minutes) <= $2
+		  AND ($1 = '' OR u.team_name = $1)
+		ORDER BY rev.assigned_at, pr.id, rev.reviewer_id
+	`, teamName, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var overdue []models.OverdueReview
+	for rows.Next() {
+		var o models.OverdueReview
+		if err := rows.Scan(&o.PRID, &o.Title, &o.AuthorID, &o.TeamName, &o.ReviewerID, &o.AssignedAt, &o.Escalated); err != nil {
+			return nil, err
+		}
+		overdue = append(overdue, o)
+	}
+	return overdue, rows.Err()
+}
+
+func (r *PRRepo) MarkEscalated(ctx context.Context, prID, reviewerID string) error {
+	tag, err := r.pool.Exec(ctx, "UPDATE pr_reviewers SET escalated_at=NOW() WHERE pr_id=$1 AND reviewer_id=$2", prID, reviewerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("reviewer %s on pr %s: %w", reviewerID, prID, domain.ErrNotFound)
 	}
 	return nil
 }
@@ -127,20 +428,100 @@ func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]*mode
 	return prs, nil
 }
 
-func (r *PRRepo) FindCandidateForReassign(ctx context.Context, teamName, oldReviewerID, authorID string, currentReviewers []string) (string, error) {
+func (r *PRRepo) List(ctx context.Context, filter models.PRFilter) ([]*models.PullRequest, error) {
+	var where []string
+	var args []any
+	add := func(cond string, v any) {
+		args = append(args, v)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	addTime := func(cond string, t *time.Time) {
+		if t != nil {
+			add(cond, *t)
+		}
+	}
+
+	if filter.Status != "" {
+		add("pr.status = $%d", filter.Status)
+	}
+	if filter.AuthorID != "" {
+		add("pr.author_id = $%d", filter.AuthorID)
+	}
+	if filter.ReviewerID != "" {
+		add("EXISTS (SELECT 1 FROM pr_reviewers rv WHERE rv.pr_id = pr.id AND rv.reviewer_id = $%d)", filter.ReviewerID)
+	}
+	if filter.TeamName != "" {
+		add("pr.author_id IN (SELECT id FROM users WHERE team_name = $%d)", filter.TeamName)
+	}
+	addTime("pr.created_at >= $%d", filter.CreatedFrom)
+	addTime("pr.created_at < $%d", filter.CreatedTo)
+	addTime("pr.merged_at >= $%d", filter.MergedFrom)
+	addTime("pr.merged_at < $%d", filter.MergedTo)
+	if filter.After != nil {
+		args = append(args, filter.After.CreatedAt, filter.After.ID)
+		where = append(where, fmt.Sprintf("(pr.created_at, pr.id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
 	query := `
-		SELECT id FROM users
-		WHERE team_name=$1
-		  AND is_active=TRUE
-		  AND id!=$2
-		  AND id!=$3
-		  AND id != ALL($4)
-		ORDER BY RANDOM() LIMIT 1
+		SELECT pr.id, pr.title, pr.author_id, pr.status, pr.created_at, pr.merged_at,
+		       COALESCE((SELECT array_agg(rv.reviewer_id ORDER BY rv.reviewer_id) FROM pr_reviewers rv WHERE rv.pr_id = pr.id), '{}')
+		FROM pull_requests pr
 	`
-	var newID string
-	err := r.pool.QueryRow(ctx, query, teamName, oldReviewerID, authorID, currentReviewers).Scan(&newID)
-	if errors.Is(err, pgx.ErrNoRows) {
-		return "", nil
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY pr.created_at DESC, pr.id DESC LIMIT $%d", len(args))
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []*models.PullRequest
+	for rows.Next() {
+		pr := &models.PullRequest{}
+		if err := rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.Reviewers); err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+	return prs, rows.Err()
+}
+
+func (r *PRRepo) FindCandidates(ctx context.Context, teamName string, exclude []string) ([]models.ReviewCandidate, error) {
+	if exclude == nil {
+		exclude = []string{}
+	}
+	query := `
+		SELECT u.id, COUNT(pr.id), u.max_open_reviews, u.time_zone, u.work_start, u.work_end
+		FROM users u
+		LEFT JOIN pr_reviewers rev ON rev.reviewer_id = u.id
+		LEFT JOIN pull_requests pr ON pr.id = rev.pr_id AND pr.status = 'OPEN'
+		WHERE u.team_name = $1
+		  AND u.is_active = TRUE
+		  AND ` + notAway + `
+		  AND u.id != ALL($2)
+		GROUP BY u.id
+		ORDER BY u.id
+	`
+	rows, err := r.pool.Query(ctx, query, teamName, exclude)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []models.ReviewCandidate
+	for rows.Next() {
+		var c models.ReviewCandidate
+		var tz *string
+		var start, end *int
+		if err := rows.Scan(&c.UserID, &c.OpenReviews, &c.MaxOpenReviews, &tz, &start, &end); err != nil {
+			return nil, err
+		}
+		c.WorkingHours = workingHours(tz, start, end)
+		candidates = append(candidates, c)
 	}
-	return newID, err
+	return candidates, rows.Err()
 }
@@ -3,8 +3,12 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
 	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -19,14 +23,126 @@ func NewTeamRepo(pool *pgxpool.Pool) *TeamRepo {
 
 func (r *TeamRepo) Create(ctx context.Context, team *models.Team) error {
 	_, err := r.pool.Exec(ctx, "INSERT INTO teams (name) VALUES ($1)", team.Name)
-	return err
+	return mapError(err)
 }
 
 func (r *TeamRepo) FindByName(ctx context.Context, name string) (*models.Team, error) {
 	t := &models.Team{}
 	err := r.pool.QueryRow(ctx, "SELECT name FROM teams WHERE name=$1", name).Scan(&t.Name)
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, nil
+		return nil, fmt.Errorf("team %s: %w", name, domain.ErrNotFound)
 	}
-	return t, err
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Rename меняет имя команды; users, team_settings и team_fallbacks обновляются по ON UPDATE CASCADE.
+func (r *TeamRepo) Rename(ctx context.Context, oldName, newName string) error {
+	tag, err := r.pool.Exec(ctx, "UPDATE teams SET name=$2 WHERE name=$1", oldName, newName)
+	if err != nil {
+		return mapError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("team %s: %w", oldName, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// Delete убирает всех участников из команды, переназначает их ревью в OPEN PR
+// (авторы из команды тоже убраны, так что на их PR замен нет и plan ревьюеров снимет)
+// и удаляет команду.
+func (r *TeamRepo) Delete(ctx context.Context, name string, plan repo.ReassignPlanner, audit models.Audit) (*models.DeactivationResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := detachMembers(ctx, tx, name, nil, plan, audit)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := tx.Exec(ctx, "DELETE FROM teams WHERE name=$1", name)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, fmt.Errorf("team %s: %w", name, domain.ErrNotFound)
+	}
+	return result, tx.Commit(ctx)
+}
+
+func (r *TeamRepo) GetSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	ts, err := scanSettings(r.pool.QueryRow(ctx, selectSettings+" WHERE s.team_name=$1", teamName))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("settings of team %s: %w", teamName, domain.ErrNotFound)
+	}
+	return ts, err
+}
+
+// selectSettings — настройки команд с запасными командами по порядку; условие дописывается.
+const selectSettings = `
+	SELECT s.team_name, s.reviewer_count, s.min_approvals, COALESCE(s.lead_user_id, ''), s.require_lead, s.strategy,
+	       ARRAY(SELECT f.fallback_team FROM team_fallbacks f WHERE f.team_name = s.team_name ORDER BY f.position),
+	       s.review_sla_minutes, s.sla_action, s.chat_channel
+	FROM team_settings s`
+
+func scanSettings(row pgx.Row) (*models.TeamSettings, error) {
+	ts := &models.TeamSettings{}
+	var slaMinutes int
+	err := row.Scan(&ts.TeamName, &ts.ReviewerCount, &ts.MinApprovals, &ts.LeadUserID, &ts.RequireLead, &ts.Strategy, &ts.FallbackTeams,
+		&slaMinutes, &ts.SLAAction, &ts.ChatChannel)
+	if err != nil {
+		return nil, err
+	}
+	if len(ts.FallbackTeams) == 0 {
+		ts.FallbackTeams = nil
+	}
+	ts.ReviewSLA = time.Duration(slaMinutes) * time.Minute
+	return ts, nil
+}
+
+func (r *TeamRepo) UpsertSettings(ctx context.Context, ts *models.TeamSettings) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO team_settings (team_name, reviewer_count, min_approvals, lead_user_id, require_lead, strategy, review_sla_minutes, sla_action,
+		                           chat_channel)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9)
+		ON CONFLICT (team_name) DO UPDATE SET
+			reviewer_count = EXCLUDED.reviewer_count,
+			min_approvals = EXCLUDED.min_approvals,
+			lead_user_id = EXCLUDED.lead_user_id,
+			require_lead = EXCLUDED.require_lead,
+			strategy = EXCLUDED.strategy,
+			review_sla_minutes = EXCLUDED.review_sla_minutes,
+			sla_action = EXCLUDED.sla_action,
+			chat_channel = EXCLUDED.chat_channel
+	`, ts.TeamName, ts.ReviewerCount, ts.MinApprovals, ts.LeadUserID, ts.RequireLead, ts.Strategy, int(ts.ReviewSLA/time.Minute), ts.SLAAction,
+		ts.ChatChannel)
+	if err != nil {
+		return mapMissingRef(err,
+			ref{"team_settings_team_name_fkey", "team " + ts.TeamName},
+			ref{"team_settings_lead_user_id_fkey", "user " + ts.LeadUserID})
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM team_fallbacks WHERE team_name=$1", ts.TeamName); err != nil {
+		return err
+	}
+	if len(ts.FallbackTeams) > 0 {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO team_fallbacks (team_name, fallback_team, position)
+			SELECT $1, f.name, f.position FROM unnest($2::text[]) WITH ORDINALITY AS f(name, position)
+		`, ts.TeamName, ts.FallbackTeams)
+		if err != nil {
+			return mapMissingRef(err, ref{"team_fallbacks_fallback_team_fkey", "fallback team"})
+		}
+	}
+	return tx.Commit(ctx)
 }
@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
 	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -20,23 +24,29 @@ func NewUserRepo(pool *pgxpool.Pool) *UserRepo {
 
 func (r *UserRepo) Upsert(ctx context.Context, user *models.User) error {
 	_, err := r.pool.Exec(ctx,
-		"INSERT INTO users (id, username, is_active, team_name) VALUES ($1, $2, $3, $4) ON CONFLICT (id) DO UPDATE SET username=$2, is_active=$3, team_name=$4",
-		user.ID, user.Name, user.IsActive, user.TeamName)
-	return err
+		"INSERT INTO users (id, username, is_active, team_name, max_open_reviews) VALUES ($1, $2, $3, NULLIF($4, ''), $5) ON CONFLICT (id) DO UPDATE SET username=$2, is_active=$3, team_name=NULLIF($4, ''), max_open_reviews=$5",
+		user.ID, user.Name, user.IsActive, user.TeamName, user.MaxOpenReviews)
+	return mapMissingRef(err, ref{"users_team_name_fkey", "team " + user.TeamName})
 }
 
 func (r *UserRepo) GetByID(ctx context.Context, id string) (*models.User, error) {
 	u := &models.User{}
-	err := r.pool.QueryRow(ctx, "SELECT id, username, is_active, team_name FROM users WHERE id=$1", id).
-		Scan(&u.ID, &u.Name, &u.IsActive, &u.TeamName)
+	var tz *string
+	var start, end *int
+	err := r.pool.QueryRow(ctx, "SELECT id, username, is_active, COALESCE(team_name, ''), max_open_reviews, time_zone, work_start, work_end FROM users WHERE id=$1", id).
+		Scan(&u.ID, &u.Name, &u.IsActive, &u.TeamName, &u.MaxOpenReviews, &tz, &start, &end)
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, nil
+		return nil, fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
 	}
-	return u, err
+	if err != nil {
+		return nil, err
+	}
+	u.WorkingHours = workingHours(tz, start, end)
+	return u, nil
 }
 
 func (r *UserRepo) ListByTeam(ctx context.Context, teamName string, activeOnly bool) ([]*models.User, error) {
-	query := "SELECT id, username, is_active, team_name FROM users WHERE team_name=$1"
+	query := "SELECT id, username, is_active, team_name, max_open_reviews, time_zone, work_start, work_end FROM users WHERE team_name=$1"
 	args := []any{teamName}
 
 	if activeOnly {
@@ -52,23 +62,44 @@ func (r *UserRepo) ListByTeam(ctx context.Context, teamName string, activeOnly b
 	var users []*models.User
 	for rows.Next() {
 		u := &models.User{}
-		if err := rows.Scan(&u.ID, &u.Name, &u.IsActive, &u.TeamName); err != nil {
+		var tz *string
+		var start, end *int
+		if err := rows.Scan(&u.ID, &u.Name, &u.IsActive, &u.TeamName, &u.MaxOpenReviews, &tz, &start, &end); err != nil {
 			return nil, err
 		}
+		u.WorkingHours = workingHours(tz, start, end)
 		users = append(users, u)
 	}
 	return users, nil
 }
 
-func (r *UserRepo) SetActive(ctx context.Context, id string, active bool) error {
-	cmd, err := r.pool.Exec(ctx, "UPDATE users SET is_active=$1 WHERE id=$2", active, id)
+func (r *UserRepo) SetActive(ctx context.Context, id string, active bool, audit models.Audit) error {
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	if cmd.RowsAffected() == 0 {
-		return fmt.Errorf("user not found")
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// FROM users old видит строку до UPDATE: событие только при выключении активного
+	var wasActive bool
+	err = tx.QueryRow(ctx, `
+		UPDATE users u SET is_active=$1 FROM users old
+		WHERE u.id=$2 AND old.id=u.id
+		RETURNING old.is_active
+	`, active, id).Scan(&wasActive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
 	}
-	return nil
+	if err != nil {
+		return err
+	}
+	if wasActive && !active {
+		err = appendOutbox(ctx, tx, []models.OutboxEvent{{Type: models.OutboxUserDeactivated, UserID: id, Actor: audit.Actor, Reason: audit.Reason}})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
 }
 
 type UserStat struct {
@@ -76,11 +107,56 @@ type UserStat struct {
 	ReviewCount int    `json:"review_count"`
 }
 
+func (r *UserRepo) SetWorkingHours(ctx context.Context, id string, wh *models.WorkingHours) error {
+	var tz *string
+	var start, end *int
+	if wh != nil {
+		tz, start, end = &wh.TimeZone, &wh.Start, &wh.End
+	}
+	cmd, err := r.pool.Exec(ctx, "UPDATE users SET time_zone=$2, work_start=$3, work_end=$4 WHERE id=$1", id, tz, start, end)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("user %s: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *UserRepo) LinkIdentity(ctx context.Context, platform, login, userID string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO user_identities (platform, login, user_id) VALUES ($1, $2, $3)
+		ON CONFLICT (platform, login) DO UPDATE SET user_id = EXCLUDED.user_id
+	`, platform, login, userID)
+	return mapMissingRef(err, ref{"user_identities_user_id_fkey", "user " + userID})
+}
+
+func (r *UserRepo) FindByIdentity(ctx context.Context, platform, login string) (*models.User, error) {
+	var userID string
+	err := r.pool.QueryRow(ctx, "SELECT user_id FROM user_identities WHERE platform=$1 AND login=$2", platform, login).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s login %s: %w", platform, login, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, userID)
+}
+
+// workingHours собирает рабочие часы из nullable-колонок users.
+func workingHours(tz *string, start, end *int) *models.WorkingHours {
+	if tz == nil || start == nil || end == nil {
+		return nil
+	}
+	return &models.WorkingHours{TimeZone: *tz, Start: *start, End: *end}
+}
+
 func (r *UserRepo) GetStats(ctx context.Context) ([]models.UserStat, error) {
 	query := `
-		SELECT u.username, COUNT(r.pr_id)
+		SELECT u.username, COUNT(r.pr_id), COUNT(pr.id) FILTER (WHERE pr.status = 'OPEN')
 		FROM users u
 		LEFT JOIN pr_reviewers r ON u.id = r.reviewer_id
+		LEFT JOIN pull_requests pr ON pr.id = r.pr_id
 		GROUP BY u.id, u.username
 		ORDER BY COUNT(r.pr_id) DESC
 	`
@@ -93,10 +169,309 @@ func (r *UserRepo) GetStats(ctx context.Context) ([]models.UserStat, error) {
 	var stats []models.UserStat
 	for rows.Next() {
 		var s models.UserStat
-		if err := rows.Scan(&s.Username, &s.ReviewCount); err != nil {
+		if err := rows.Scan(&s.Username, &s.ReviewCount, &s.OpenReviewCount); err != nil {
 			return nil, err
 		}
 		stats = append(stats, s)
 	}
 	return stats, nil
 }
+
+// DeactivateWithReassign делает всё в одной транзакции и фиксированным числом
+// запросов, независимо от количества пользователей и PR.
+func (r *UserRepo) DeactivateWithReassign(ctx context.Context, teamName string, userIDs []string, plan repo.ReassignPlanner, audit models.Audit) (*models.DeactivationResult, error) {
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// FROM users old видит строки до UPDATE: user.deactivated только для тех, кто был активен
+	rows, err := tx.Query(ctx, `
+		WITH updated AS (
+			UPDATE users u SET is_active = FALSE
+			FROM users old
+			WHERE old.id = u.id AND (u.id = ANY($1) OR ($2 != '' AND u.team_name = $2))
+			RETURNING u.id, old.is_active
+		)
+		SELECT id, is_active FROM updated ORDER BY id
+	`, userIDs, teamName)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.DeactivationResult{}
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var id string
+		var wasActive bool
+		if err := rows.Scan(&id, &wasActive); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		result.Deactivated = append(result.Deactivated, id)
+		if wasActive {
+			events = append(events, models.OutboxEvent{Type: models.OutboxUserDeactivated, UserID: id, Actor: audit.Actor, Reason: audit.Reason})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := appendOutbox(ctx, tx, events); err != nil {
+		return nil, err
+	}
+	if result.Changes, err = reassignReviews(ctx, tx, result.Deactivated, plan, audit); err != nil {
+		return nil, err
+	}
+	return result, tx.Commit(ctx)
+}
+
+// DetachWithReassign убирает пользователей из команды teamName (team_name = NULL)
+// и переназначает их ревью в OPEN PR на оставшихся участников команды.
+func (r *UserRepo) DetachWithReassign(ctx context.Context, teamName string, userIDs []string, plan repo.ReassignPlanner, audit models.Audit) (*models.DeactivationResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := detachMembers(ctx, tx, teamName, userIDs, plan, audit)
+	if err != nil {
+		return nil, err
+	}
+	return result, tx.Commit(ctx)
+}
+
+// detachMembers убирает из команды перечисленных (nil — всех) участников и
+// переназначает их ревью в OPEN PR в рамках tx.
+func detachMembers(ctx context.Context, tx pgx.Tx, teamName string, userIDs []string, plan repo.ReassignPlanner, audit models.Audit) (*models.DeactivationResult, error) {
+	rows, err := tx.Query(ctx, `
+		UPDATE users SET team_name = NULL
+		WHERE team_name = $1 AND ($2::text[] IS NULL OR id = ANY($2))
+		RETURNING id
+	`, teamName, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	detached, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	result := &models.DeactivationResult{Deactivated: detached}
+	if result.Changes, err = reassignReviews(ctx, tx, detached, plan, audit); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *UserRepo) MoveTeam(ctx context.Context, userID, toTeam string, handover bool, plan repo.ReassignPlanner, audit models.Audit) (*models.TeamMove, []models.ReviewerChange, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	move := &models.TeamMove{UserID: userID, ToTeam: toTeam, Handover: handover, Actor: audit.Actor}
+	err = tx.QueryRow(ctx, "SELECT COALESCE(team_name, '') FROM users WHERE id=$1 FOR UPDATE", userID).Scan(&move.FromTeam)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, fmt.Errorf("user %s: %w", userID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE users SET team_name=$2 WHERE id=$1", userID, toTeam); err != nil {
+		return nil, nil, mapMissingRef(err, ref{"users_team_name_fkey", "team " + toTeam})
+	}
+
+	var changes []models.ReviewerChange
+	if handover {
+		if changes, err = reassignReviews(ctx, tx, []string{userID}, plan, audit); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO team_moves (user_id, from_team, to_team, handover, actor)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5)
+		RETURNING id, created_at
+	`, userID, move.FromTeam, toTeam, handover, audit.Actor).Scan(&move.ID, &move.CreatedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return move, changes, tx.Commit(ctx)
+}
+
+func (r *UserRepo) ListMoves(ctx context.Context, userID string) ([]models.TeamMove, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, user_id, COALESCE(from_team, ''), to_team, handover, actor, created_at
+		FROM team_moves
+		WHERE user_id = $1
+		ORDER BY id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var moves []models.TeamMove
+	for rows.Next() {
+		var m models.TeamMove
+		if err := rows.Scan(&m.ID, &m.UserID, &m.FromTeam, &m.ToTeam, &m.Handover, &m.Actor, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		moves = append(moves, m)
+	}
+	return moves, rows.Err()
+}
+
+// reassignReviews переназначает ревью userIDs в OPEN PR фиксированным числом
+// запросов. Замена ищется, как при одиночной замене, в команде автора PR, а для
+// запасного ревьюера — в запасных командах команды автора.
+func reassignReviews(ctx context.Context, tx pgx.Tx, userIDs []string, plan repo.ReassignPlanner, audit models.Audit) ([]models.ReviewerChange, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT pr.id, pr.author_id, rev.reviewer_id, COALESCE(a.team_name, ''), rev.is_fallback,
+		       ARRAY(SELECT r2.reviewer_id FROM pr_reviewers r2 WHERE r2.pr_id = pr.id)
+		FROM pr_reviewers rev
+		JOIN pull_requests pr ON pr.id = rev.pr_id
+		JOIN users a ON a.id = pr.author_id
+		WHERE rev.reviewer_id = ANY($1)
+		  AND pr.status = 'OPEN'
+		ORDER BY pr.id, rev.reviewer_id
+		FOR UPDATE OF pr
+	`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	var reviews []models.AffectedReview
+	authorTeams := map[string]bool{}
+	for rows.Next() {
+		var a models.AffectedReview
+		if err := rows.Scan(&a.PRID, &a.AuthorID, &a.ReviewerID, &a.AuthorTeam, &a.Fallback, &a.Reviewers); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		reviews = append(reviews, a)
+		if a.AuthorTeam != "" {
+			authorTeams[a.AuthorTeam] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return nil, nil
+	}
+
+	rows, err = tx.Query(ctx, selectSettings+" WHERE s.team_name = ANY($1)", slices.Collect(maps.Keys(authorTeams)))
+	if err != nil {
+		return nil, err
+	}
+	settings := map[string]*models.TeamSettings{}
+	for rows.Next() {
+		ts, err := scanSettings(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		settings[ts.TeamName] = ts
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var teamNames []string
+	for _, rv := range reviews {
+		teamNames = append(teamNames, models.ReplacementTeams(rv.AuthorTeam, rv.Fallback, settings[rv.AuthorTeam])...)
+	}
+	rows, err = tx.Query(ctx, `
+		SELECT u.team_name, u.id, COUNT(pr.id), u.max_open_reviews, u.time_zone, u.work_start, u.work_end
+		FROM users u
+		LEFT JOIN pr_reviewers rev ON rev.reviewer_id = u.id
+		LEFT JOIN pull_requests pr ON pr.id = rev.pr_id AND pr.status = 'OPEN'
+		WHERE u.team_name = ANY($1)
+		  AND u.is_active = TRUE
+		  AND `+notAway+`
+		GROUP BY u.id
+		ORDER BY u.id
+	`, teamNames)
+	if err != nil {
+		return nil, err
+	}
+	candidates := map[string][]models.ReviewCandidate{}
+	for rows.Next() {
+		var team string
+		var c models.ReviewCandidate
+		var tz *string
+		var start, end *int
+		if err := rows.Scan(&team, &c.UserID, &c.OpenReviews, &c.MaxOpenReviews, &tz, &start, &end); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		c.WorkingHours = workingHours(tz, start, end)
+		candidates[team] = append(candidates[team], c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	changes := plan(reviews, settings, candidates)
+
+	var replPR, replOld, replNew, remPR, remOld []string
+	history := make([]models.AssignmentRecord, 0, len(changes))
+	for _, c := range changes {
+		rec := models.AssignmentRecord{
+			PRID:          c.PRID,
+			ReviewerID:    c.OldReviewerID,
+			NewReviewerID: c.NewReviewerID,
+			Action:        models.ActionReassigned,
+			Actor:         audit.Actor,
+			Reason:        audit.Reason,
+		}
+		if c.NewReviewerID == "" {
+			rec.Action = models.ActionRemoved
+			remPR = append(remPR, c.PRID)
+			remOld = append(remOld, c.OldReviewerID)
+		} else {
+			replPR = append(replPR, c.PRID)
+			replOld = append(replOld, c.OldReviewerID)
+			replNew = append(replNew, c.NewReviewerID)
+		}
+		history = append(history, rec)
+	}
+
+	if len(replPR) > 0 {
+		_, err = tx.Exec(ctx, `
+			UPDATE pr_reviewers rev SET reviewer_id = c.new_id, state = 'PENDING', state_updated_at = NULL, assigned_at = NOW(), escalated_at = NULL
+			FROM unnest($1::text[], $2::text[], $3::text[]) AS c(pr_id, old_id, new_id)
+			WHERE rev.pr_id = c.pr_id AND rev.reviewer_id = c.old_id
+		`, replPR, replOld, replNew)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(remPR) > 0 {
+		_, err = tx.Exec(ctx, `
+			DELETE FROM pr_reviewers rev
+			USING unnest($1::text[], $2::text[]) AS c(pr_id, old_id)
+			WHERE rev.pr_id = c.pr_id AND rev.reviewer_id = c.old_id
+		`, remPR, remOld)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := appendHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+	if err := appendOutbox(ctx, tx, models.ReviewerEvents(history)); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepo(pool *pgxpool.Pool) *WebhookRepo {
+	return &WebhookRepo{pool: pool}
+}
+
+func (r *WebhookRepo) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	return r.pool.QueryRow(ctx, `
+		INSERT INTO webhook_subscriptions (url, secret, events, active)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, sub.URL, sub.Secret, events(sub.Events), sub.Active).Scan(&sub.ID, &sub.CreatedAt)
+}
+
+func (r *WebhookRepo) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	subs, err := r.listSubscriptions(ctx, "WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(subs) == 0 {
+		return nil, fmt.Errorf("webhook %d: %w", id, domain.ErrNotFound)
+	}
+	return &subs[0], nil
+}
+
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return r.listSubscriptions(ctx, "ORDER BY id")
+}
+
+func (r *WebhookRepo) UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	cmd, err := r.pool.Exec(ctx, "UPDATE webhook_subscriptions SET url=$2, secret=$3, events=$4, active=$5 WHERE id=$1",
+		sub.ID, sub.URL, sub.Secret, events(sub.Events), sub.Active)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("webhook %d: %w", sub.ID, domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	cmd, err := r.pool.Exec(ctx, "DELETE FROM webhook_subscriptions WHERE id=$1", id)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("webhook %d: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *WebhookRepo) listSubscriptions(ctx context.Context, where string, args ...any) ([]models.WebhookSubscription, error) {
+	rows, err := r.pool.Query(ctx, "SELECT id, url, secret, events, active, created_at FROM webhook_subscriptions "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []models.WebhookSubscription
+	for rows.Next() {
+		var s models.WebhookSubscription
+		if err := rows.Scan(&s.ID, &s.URL, &s.Secret, &s.Events, &s.Active, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		if len(s.Events) == 0 {
+			s.Events = nil
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+func (r *WebhookRepo) Enqueue(ctx context.Context, eventID int64, event string, payload []byte) (int, error) {
+	cmd, err := r.pool.Exec(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event, payload)
+		SELECT id, $1, $2, $3 FROM webhook_subscriptions
+		WHERE active AND (cardinality(events) = 0 OR $2 = ANY(events))
+		ORDER BY id
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`, eventID, event, payload)
+	if err != nil {
+		return 0, err
+	}
+	return int(cmd.RowsAffected()), nil
+}
+
+// deliveryColumns — колонки webhook_deliveries d в порядке scanDelivery.
+const deliveryColumns = `d.id, d.subscription_id, COALESCE(d.event_id, 0), d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+		       d.last_status_code, d.last_error, d.created_at, d.delivered_at`
+
+func scanDelivery(row pgx.Row, d *models.WebhookDelivery, extra ...any) error {
+	return row.Scan(append([]any{&d.ID, &d.SubscriptionID, &d.EventID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt}, extra...)...)
+}
+
+func (r *WebhookRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	// SKIP LOCKED: экземпляры сервиса разбирают очередь, не мешая друг другу
+	rows, err := r.pool.Query(ctx, `
+		WITH due AS (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND s.active
+			ORDER BY d.next_attempt_at, d.id
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d SET next_attempt_at = $2
+		FROM due, webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING `+deliveryColumns+`, s.url, s.secret
+	`, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := scanDelivery(rows, &d, &d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(deliveries, func(a, b models.WebhookDelivery) int { return cmp.Compare(a.ID, b.ID) })
+	return deliveries, nil
+}
+
+func (r *WebhookRepo) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	cmd, err := r.pool.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', attempts = attempts + 1, last_status_code = $2, last_error = '', delivered_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`, id, statusCode)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("pending delivery %d: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *WebhookRepo) MarkFailed(ctx context.Context, id int64, statusCode *int, lastError string, next *time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, last_status_code = $2, last_error = $3,
+		    status = CASE WHEN $4::timestamptz IS NULL THEN 'dead' ELSE 'pending' END,
+		    next_attempt_at = COALESCE($4, next_attempt_at)
+		WHERE id = $1 AND status = 'pending'
+	`, id, statusCode, lastError, next)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("pending delivery %d: %w", id, domain.ErrNotFound)
+	}
+	if next == nil {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO webhook_dead_letters (delivery_id, subscription_id, event, payload, attempts, last_error)
+			SELECT id, subscription_id, event, payload, attempts, last_error FROM webhook_deliveries WHERE id = $1
+		`, id)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+deliveryColumns+" FROM webhook_deliveries d WHERE d.subscription_id = $1 ORDER BY d.id DESC LIMIT $2",
+		subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := scanDelivery(rows, &d); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *WebhookRepo) ListDeadLetters(ctx context.Context) ([]models.DeadLetter, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, delivery_id, subscription_id, event, payload, attempts, last_error, created_at
+		FROM webhook_dead_letters ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var letters []models.DeadLetter
+	for rows.Next() {
+		var l models.DeadLetter
+		if err := rows.Scan(&l.ID, &l.DeliveryID, &l.SubscriptionID, &l.Event, &l.Payload, &l.Attempts, &l.LastError, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		letters = append(letters, l)
+	}
+	return letters, rows.Err()
+}
+
+func (r *WebhookRepo) Redeliver(ctx context.Context, deadLetterID int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var deliveryID int64
+	err = tx.QueryRow(ctx, "DELETE FROM webhook_dead_letters WHERE id = $1 RETURNING delivery_id", deadLetterID).Scan(&deliveryID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("dead letter %d: %w", deadLetterID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), last_status_code = NULL, last_error = ''
+		WHERE id = $1
+	`, deliveryID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// events — массив для колонки events: NOT NULL, поэтому nil становится пустым.
+func events(e []string) []string {
+	if e == nil {
+		return []string{}
+	}
+	return e
+}
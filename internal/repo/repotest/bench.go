@@ -0,0 +1,108 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+const (
+	benchTeams       = 20
+	benchTeamSize    = 20
+	benchPRsPerTeam  = 20
+	benchDeactivated = benchTeams * benchTeamSize / 2
+)
+
+// BenchmarkDeactivate — массовая деактивация: из 400 пользователей в 20 командах
+// выключаются 200, их ревью в 400 OPEN PR переназначаются на оставшихся. Цель
+// для Postgres — не больше 100 ms на операцию.
+func BenchmarkDeactivate(b *testing.B, newRepos func(tb testing.TB) Repos) {
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		r := newRepos(b)
+		ids := seedBench(b, r)
+		b.StartTimer()
+
+		res, err := r.Users.DeactivateWithReassign(ctx, "", ids, planFirstFree, TestAudit)
+		if err != nil {
+			b.Fatalf("deactivate: %v", err)
+		}
+		if len(res.Deactivated) != benchDeactivated || len(res.Changes) == 0 {
+			b.Fatalf("deactivated %d, changes %d", len(res.Deactivated), len(res.Changes))
+		}
+	}
+}
+
+// BenchmarkListPRs — страница /pullRequest/list с фильтрами по команде, статусу
+// и ревьюеру на тех же данных.
+func BenchmarkListPRs(b *testing.B, newRepos func(tb testing.TB) Repos) {
+	ctx := context.Background()
+	r := newRepos(b)
+	seedBench(b, r)
+	filter := models.PRFilter{TeamName: "team-07", Status: models.StatusOpen, ReviewerID: "team-07-u03", Limit: 50}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prs, err := r.PRs.List(ctx, filter)
+		if err != nil {
+			b.Fatalf("list: %v", err)
+		}
+		if len(prs) == 0 {
+			b.Fatal("list: nothing found")
+		}
+	}
+}
+
+// seedBench заполняет хранилище и возвращает половину пользователей — по
+// каждому второму в каждой команде.
+func seedBench(tb testing.TB, r Repos) []string {
+	tb.Helper()
+	ctx := context.Background()
+	var deactivate []string
+	for t := 0; t < benchTeams; t++ {
+		team := fmt.Sprintf("team-%02d", t)
+		if err := r.Teams.Create(ctx, &models.Team{Name: team}); err != nil {
+			tb.Fatalf("create team: %v", err)
+		}
+		members := make([]string, benchTeamSize)
+		for u := range members {
+			members[u] = fmt.Sprintf("%s-u%02d", team, u)
+			if err := r.Users.Upsert(ctx, &models.User{ID: members[u], Name: members[u], IsActive: true, TeamName: team}); err != nil {
+				tb.Fatalf("upsert user: %v", err)
+			}
+			if u%2 == 1 {
+				deactivate = append(deactivate, members[u])
+			}
+		}
+		for p := 0; p < benchPRsPerTeam; p++ {
+			pr := &models.PullRequest{
+				ID:        fmt.Sprintf("%s-pr%02d", team, p),
+				AuthorID:  members[p%benchTeamSize],
+				Status:    models.StatusOpen,
+				Reviewers: []string{members[(p+1)%benchTeamSize], members[(p+2)%benchTeamSize]},
+			}
+			if err := r.PRs.CreateWithReviewers(ctx, pr, TestAudit); err != nil {
+				tb.Fatalf("create pr: %v", err)
+			}
+		}
+	}
+	return deactivate
+}
+
+// planFirstFree заменяет ревьюера первым кандидатом команды автора, не занятым в PR.
+func planFirstFree(reviews []models.AffectedReview, _ map[string]*models.TeamSettings, candidates map[string][]models.ReviewCandidate) []models.ReviewerChange {
+	changes := make([]models.ReviewerChange, len(reviews))
+	for i, rv := range reviews {
+		changes[i] = models.ReviewerChange{PRID: rv.PRID, OldReviewerID: rv.ReviewerID}
+		for _, c := range candidates[rv.AuthorTeam] {
+			if c.UserID != rv.AuthorID && !slices.Contains(rv.Reviewers, c.UserID) {
+				changes[i].NewReviewerID = c.UserID
+				break
+			}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,1460 @@
+// Package repotest — общий набор тестов на контракт интерфейсов из internal/repo.
+// Каждая реализация хранилища должна вызывать Run из своего _test.go.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+)
+
+// TestAudit — автор и причина изменений в тестах
+var TestAudit = models.Audit{Actor: "tester", Reason: "test"}
+
+type Repos struct {
+	Users    repo.UserRepository
+	Teams    repo.TeamRepository
+	PRs      repo.PRRepository
+	Absences repo.AbsenceRepository
+	Webhooks repo.WebhookRepository
+	Outbox   repo.OutboxRepository
+
+	Notifications repo.NotificationRepository
+}
+
+// Run запускает все проверки. newRepos должен каждый раз отдавать пустое хранилище.
+func Run(t *testing.T, newRepos func(tb testing.TB) Repos) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, r Repos)
+	}{
+		{"TeamDuplicate", testTeamDuplicate},
+		{"TeamNotFound", testTeamNotFound},
+		{"UserUpsert", testUserUpsert},
+		{"UserUnknownTeam", testUserUnknownTeam},
+		{"UserNotFound", testUserNotFound},
+		{"UserListByTeam", testUserListByTeam},
+		{"PRDuplicate", testPRDuplicate},
+		{"PRUnknownAuthor", testPRUnknownAuthor},
+		{"PRNotFound", testPRNotFound},
+		{"PRSkipsInactiveReviewers", testPRSkipsInactiveReviewers},
+		{"PRSkipsReviewersAtCapacity", testPRSkipsReviewersAtCapacity},
+		{"FindCandidates", testFindCandidates},
+		{"ReplaceReviewer", testReplaceReviewer},
+		{"ListByReviewer", testListByReviewer},
+		{"MergeIdempotent", testMergeIdempotent},
+		{"Stats", testStats},
+		{"DeactivateWithReassign", testDeactivateWithReassign},
+		{"History", testHistory},
+		{"Lifecycle", testLifecycle},
+		{"ReviewState", testReviewState},
+		{"TeamSettings", testTeamSettings},
+		{"FallbackTeams", testFallbackTeams},
+		{"TeamRename", testTeamRename},
+		{"DetachAndDeleteTeam", testDetachAndDeleteTeam},
+		{"MoveTeam", testMoveTeam},
+		{"Absences", testAbsences},
+		{"WorkingHours", testWorkingHours},
+		{"ReviewSLA", testReviewSLA},
+		{"ListPRs", testListPRs},
+		{"Identities", testIdentities},
+		{"WebhookDeliveries", testWebhookDeliveries},
+		{"Outbox", testOutbox},
+		{"Notifications", testNotifications},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepos(t))
+		})
+	}
+}
+
+// SeedTeam создаёт команду с активными участниками active и неактивными inactive.
+func SeedTeam(t *testing.T, r Repos, team string, active []string, inactive ...string) {
+	t.Helper()
+	ctx := context.Background()
+	if err := r.Teams.Create(ctx, &models.Team{Name: team}); err != nil {
+		t.Fatalf("create team %s: %v", team, err)
+	}
+	for _, id := range active {
+		MustUpsert(t, r, &models.User{ID: id, Name: "name-" + id, IsActive: true, TeamName: team})
+	}
+	for _, id := range inactive {
+		MustUpsert(t, r, &models.User{ID: id, Name: "name-" + id, IsActive: false, TeamName: team})
+	}
+}
+
+func MustUpsert(t *testing.T, r Repos, u *models.User) {
+	t.Helper()
+	if err := r.Users.Upsert(context.Background(), u); err != nil {
+		t.Fatalf("upsert user %s: %v", u.ID, err)
+	}
+}
+
+// MustCreatePR создаёт PR с заданными ревьюерами в обход выбора стратегией.
+func MustCreatePR(t *testing.T, r Repos, id, author string, reviewers ...string) *models.PullRequest {
+	t.Helper()
+	pr := &models.PullRequest{ID: id, Title: "title-" + id, AuthorID: author, Status: models.StatusOpen, Reviewers: reviewers}
+	if err := r.PRs.CreateWithReviewers(context.Background(), pr, TestAudit); err != nil {
+		t.Fatalf("create pr %s: %v", id, err)
+	}
+	return pr
+}
+
+// removeAll — планировщик, который снимает всех затронутых ревьюеров без замены.
+func removeAll(reviews []models.AffectedReview, _ map[string]*models.TeamSettings, _ map[string][]models.ReviewCandidate) []models.ReviewerChange {
+	changes := make([]models.ReviewerChange, len(reviews))
+	for i, rv := range reviews {
+		changes[i] = models.ReviewerChange{PRID: rv.PRID, OldReviewerID: rv.ReviewerID}
+	}
+	return changes
+}
+
+func Sorted(ids []string) []string {
+	out := slices.Clone(ids)
+	slices.Sort(out)
+	return out
+}
+
+func candidateIDs(cs []models.ReviewCandidate) []string {
+	ids := make([]string, len(cs))
+	for i, c := range cs {
+		ids[i] = c.UserID
+	}
+	return ids
+}
+
+func testTeamDuplicate(t *testing.T, r Repos) {
+	SeedTeam(t, r, "backend", nil)
+	err := r.Teams.Create(context.Background(), &models.Team{Name: "backend"})
+	if !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Fatalf("duplicate team: want ErrAlreadyExists, got %v", err)
+	}
+}
+
+func testTeamNotFound(t *testing.T, r Repos) {
+	team, err := r.Teams.FindByName(context.Background(), "missing")
+	if !errors.Is(err, domain.ErrNotFound) || team != nil {
+		t.Fatalf("expected (nil, ErrNotFound), got (%v, %v)", team, err)
+	}
+}
+
+func testUserUpsert(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1"})
+	SeedTeam(t, r, "frontend", nil)
+
+	limit := 3
+	MustUpsert(t, r, &models.User{ID: "u1", Name: "Renamed", IsActive: false, TeamName: "frontend", MaxOpenReviews: &limit})
+
+	u, err := r.Users.GetByID(ctx, "u1")
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if u.Name != "Renamed" || u.IsActive || u.TeamName != "frontend" {
+		t.Fatalf("user not updated: %+v", u)
+	}
+	if u.MaxOpenReviews == nil || *u.MaxOpenReviews != 3 {
+		t.Fatalf("max_open_reviews not stored: %v", u.MaxOpenReviews)
+	}
+}
+
+func testUserUnknownTeam(t *testing.T, r Repos) {
+	err := r.Users.Upsert(context.Background(), &models.User{ID: "u1", Name: "A", IsActive: true, TeamName: "missing"})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("user in unknown team: want ErrNotFound, got %v", err)
+	}
+}
+
+func testUserNotFound(t *testing.T, r Repos) {
+	ctx := context.Background()
+	u, err := r.Users.GetByID(ctx, "missing")
+	if !errors.Is(err, domain.ErrNotFound) || u != nil {
+		t.Fatalf("expected (nil, ErrNotFound), got (%v, %v)", u, err)
+	}
+	if err := r.Users.SetActive(ctx, "missing", false, TestAudit); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("SetActive for unknown user: want ErrNotFound, got %v", err)
+	}
+}
+
+func testUserListByTeam(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2"}, "u3")
+	SeedTeam(t, r, "frontend", []string{"u4"})
+
+	all, err := r.Users.ListByTeam(ctx, "backend", false)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var ids []string
+	for _, u := range all {
+		ids = append(ids, u.ID)
+	}
+	if got := Sorted(ids); !slices.Equal(got, []string{"u1", "u2", "u3"}) {
+		t.Fatalf("all members: got %v", got)
+	}
+
+	active, err := r.Users.ListByTeam(ctx, "backend", true)
+	if err != nil {
+		t.Fatalf("list active: %v", err)
+	}
+	ids = nil
+	for _, u := range active {
+		ids = append(ids, u.ID)
+	}
+	if got := Sorted(ids); !slices.Equal(got, []string{"u1", "u2"}) {
+		t.Fatalf("active members: got %v", got)
+	}
+}
+
+func testPRDuplicate(t *testing.T, r Repos) {
+	SeedTeam(t, r, "backend", []string{"u1", "u2"})
+	MustCreatePR(t, r, "pr-1", "u1", "u2")
+
+	dup := &models.PullRequest{ID: "pr-1", Title: "other", AuthorID: "u2", Status: models.StatusOpen}
+	if err := r.PRs.CreateWithReviewers(context.Background(), dup, TestAudit); !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Fatalf("duplicate pr: want ErrAlreadyExists, got %v", err)
+	}
+
+	pr, err := r.PRs.GetByID(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("get pr: %v", err)
+	}
+	if pr.AuthorID != "u1" || !slices.Equal(pr.Reviewers, []string{"u2"}) {
+		t.Fatalf("original pr changed by duplicate insert: %+v", pr)
+	}
+}
+
+func testPRUnknownAuthor(t *testing.T, r Repos) {
+	pr := &models.PullRequest{ID: "pr-1", Title: "t", AuthorID: "ghost", Status: models.StatusOpen}
+	if err := r.PRs.CreateWithReviewers(context.Background(), pr, TestAudit); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown author: want ErrNotFound, got %v", err)
+	}
+}
+
+func testPRNotFound(t *testing.T, r Repos) {
+	pr, err := r.PRs.GetByID(context.Background(), "missing")
+	if !errors.Is(err, domain.ErrNotFound) || pr != nil {
+		t.Fatalf("expected (nil, ErrNotFound), got (%v, %v)", pr, err)
+	}
+}
+
+func testPRSkipsInactiveReviewers(t *testing.T, r Repos) {
+	SeedTeam(t, r, "backend", []string{"u1", "u2"}, "u3")
+	pr := MustCreatePR(t, r, "pr-1", "u1", "u2", "u3")
+	if got := Sorted(pr.Reviewers); !slices.Equal(got, []string{"u2"}) {
+		t.Fatalf("inactive reviewer assigned: %v", got)
+	}
+	if pr.ReviewerShortage != "" {
+		t.Fatalf("inactive reviewer reported as %q", pr.ReviewerShortage)
+	}
+}
+
+func testPRSkipsReviewersAtCapacity(t *testing.T, r Repos) {
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+	limit := 1
+	MustUpsert(t, r, &models.User{ID: "u2", Name: "name-u2", IsActive: true, TeamName: "backend", MaxOpenReviews: &limit})
+
+	MustCreatePR(t, r, "pr-1", "u1", "u2")
+	pr := MustCreatePR(t, r, "pr-2", "u1", "u2", "u3")
+	if got := Sorted(pr.Reviewers); !slices.Equal(got, []string{"u3"}) {
+		t.Fatalf("reviewer at capacity assigned: %v", got)
+	}
+	if pr.ReviewerShortage != models.ShortageAtCapacity {
+		t.Fatalf("shortage %q, want %s", pr.ReviewerShortage, models.ShortageAtCapacity)
+	}
+	if pr := MustCreatePR(t, r, "pr-3", "u1", "u3"); pr.ReviewerShortage != "" {
+		t.Fatalf("nobody skipped, shortage %q", pr.ReviewerShortage)
+	}
+}
+
+func testFindCandidates(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"}, "u4")
+	SeedTeam(t, r, "frontend", []string{"u5"})
+	MustCreatePR(t, r, "pr-1", "u1", "u2")
+	MustCreatePR(t, r, "pr-2", "u1", "u2")
+	if err := r.PRs.Merge(ctx, "pr-2", TestAudit); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	cs, err := r.PRs.FindCandidates(ctx, "backend", []string{"u1"})
+	if err != nil {
+		t.Fatalf("find candidates: %v", err)
+	}
+	if got := Sorted(candidateIDs(cs)); !slices.Equal(got, []string{"u2", "u3"}) {
+		t.Fatalf("candidates: got %v, want [u2 u3]", got)
+	}
+	for _, c := range cs {
+		want := 0
+		if c.UserID == "u2" {
+			want = 1
+		}
+		if c.OpenReviews != want {
+			t.Fatalf("%s: open reviews %d, want %d (merged PRs must not count)", c.UserID, c.OpenReviews, want)
+		}
+	}
+
+	cs, err = r.PRs.FindCandidates(ctx, "backend", nil)
+	if err != nil {
+		t.Fatalf("find candidates without exclude: %v", err)
+	}
+	if got := Sorted(candidateIDs(cs)); !slices.Equal(got, []string{"u1", "u2", "u3"}) {
+		t.Fatalf("candidates without exclude: got %v", got)
+	}
+}
+
+func testReplaceReviewer(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4", "u5", "u6"})
+	MustCreatePR(t, r, "pr-1", "u1", "u2")
+
+	if err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u3", "u2", TestAudit); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("old reviewer not assigned: want ErrNotFound, got %v", err)
+	}
+	if err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "ghost", TestAudit); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown replacement: want ErrNotFound, got %v", err)
+	}
+
+	// Замену, ставшую недоступной после выбора, не пишем
+	MustUpsert(t, r, &models.User{ID: "u4", Name: "name-u4", IsActive: false, TeamName: "backend"})
+	now := time.Now()
+	if err := r.Absences.Create(ctx, &models.Absence{UserID: "u5", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("create absence: %v", err)
+	}
+	limit := 1
+	MustUpsert(t, r, &models.User{ID: "u6", Name: "name-u6", IsActive: true, TeamName: "backend", MaxOpenReviews: &limit})
+	MustCreatePR(t, r, "pr-2", "u1", "u6")
+	for _, id := range []string{"u4", "u5", "u6"} {
+		if err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", id, TestAudit); !errors.Is(err, domain.ErrNoCandidate) {
+			t.Fatalf("replace with %s: want ErrNoCandidate, got %v", id, err)
+		}
+	}
+	if err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u3", TestAudit); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	pr, err := r.PRs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("get pr: %v", err)
+	}
+	if !slices.Equal(pr.Reviewers, []string{"u3"}) {
+		t.Fatalf("reviewers after replace: %v", pr.Reviewers)
+	}
+}
+
+func testListByReviewer(t *testing.T, r Repos) {
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+	MustCreatePR(t, r, "pr-1", "u1", "u2")
+	MustCreatePR(t, r, "pr-2", "u1", "u3")
+	MustCreatePR(t, r, "pr-3", "u3", "u2")
+
+	prs, err := r.PRs.ListByReviewer(context.Background(), "u2")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var ids []string
+	for _, pr := range prs {
+		ids = append(ids, pr.ID)
+	}
+	if got := Sorted(ids); !slices.Equal(got, []string{"pr-1", "pr-3"}) {
+		t.Fatalf("prs for u2: got %v", got)
+	}
+}
+
+func testMergeIdempotent(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2"})
+	MustCreatePR(t, r, "pr-1", "u1", "u2")
+
+	if err := r.PRs.Merge(ctx, "pr-1", TestAudit); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	first, err := r.PRs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("get pr: %v", err)
+	}
+	if first.Status != models.StatusMerged || first.MergedAt == nil {
+		t.Fatalf("pr not merged: %+v", first)
+	}
+
+	if err := r.PRs.Merge(ctx, "pr-1", TestAudit); err != nil {
+		t.Fatalf("second merge: %v", err)
+	}
+	second, err := r.PRs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("get pr: %v", err)
+	}
+	if second.Status != models.StatusMerged || !second.MergedAt.Equal(*first.MergedAt) {
+		t.Fatalf("second merge changed pr: %+v -> %+v", first, second)
+	}
+}
+
+func testStats(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+	MustCreatePR(t, r, "pr-1", "u1", "u2")
+	MustCreatePR(t, r, "pr-2", "u1", "u2", "u3")
+	if err := r.PRs.Merge(ctx, "pr-1", TestAudit); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	stats, err := r.Users.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	got := map[string]models.UserStat{}
+	for _, s := range stats {
+		got[s.Username] = s
+	}
+	if s := got["name-u2"]; s.ReviewCount != 2 || s.OpenReviewCount != 1 {
+		t.Fatalf("u2 stats: %+v", s)
+	}
+	if s := got["name-u1"]; s.ReviewCount != 0 {
+		t.Fatalf("u1 stats: %+v", s)
+	}
+	if stats[0].Username != "name-u2" {
+		t.Fatalf("stats must be ordered by review count, got %+v", stats)
+	}
+}
+
+func testDeactivateWithReassign(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"})
+	MustCreatePR(t, r, "pr-1", "u1", "u2", "u3")
+	MustCreatePR(t, r, "pr-2", "u1", "u2")
+	MustCreatePR(t, r, "pr-3", "u1", "u2")
+	if err := r.PRs.Merge(ctx, "pr-3", TestAudit); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	var seen []models.AffectedReview
+	var seenCandidates []string
+	plan := func(reviews []models.AffectedReview, _ map[string]*models.TeamSettings, candidates map[string][]models.ReviewCandidate) []models.ReviewerChange {
+		seen = reviews
+		seenCandidates = Sorted(candidateIDs(candidates["backend"]))
+		return []models.ReviewerChange{
+			{PRID: "pr-1", OldReviewerID: "u2", NewReviewerID: "u4"},
+			{PRID: "pr-2", OldReviewerID: "u2"},
+		}
+	}
+
+	res, err := r.Users.DeactivateWithReassign(ctx, "", []string{"u2"}, plan, TestAudit)
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if !slices.Equal(res.Deactivated, []string{"u2"}) {
+		t.Fatalf("deactivated: %v", res.Deactivated)
+	}
+	if len(seen) != 2 || seen[0].PRID != "pr-1" || seen[1].PRID != "pr-2" {
+		t.Fatalf("affected reviews must be OPEN PRs ordered by id: %+v", seen)
+	}
+	if !slices.Equal(Sorted(seen[0].Reviewers), []string{"u2", "u3"}) || seen[0].AuthorTeam != "backend" {
+		t.Fatalf("affected review details: %+v", seen[0])
+	}
+	if !slices.Equal(seenCandidates, []string{"u1", "u3", "u4"}) {
+		t.Fatalf("candidates must be active after deactivation: %v", seenCandidates)
+	}
+
+	u, err := r.Users.GetByID(ctx, "u2")
+	if err != nil || u.IsActive {
+		t.Fatalf("u2 must be inactive: %+v, %v", u, err)
+	}
+	pr1, _ := r.PRs.GetByID(ctx, "pr-1")
+	if got := Sorted(pr1.Reviewers); !slices.Equal(got, []string{"u3", "u4"}) {
+		t.Fatalf("pr-1 reviewers: %v", got)
+	}
+	pr2, _ := r.PRs.GetByID(ctx, "pr-2")
+	if len(pr2.Reviewers) != 0 {
+		t.Fatalf("pr-2 reviewers: %v", pr2.Reviewers)
+	}
+	pr3, _ := r.PRs.GetByID(ctx, "pr-3")
+	if !slices.Equal(pr3.Reviewers, []string{"u2"}) {
+		t.Fatalf("merged pr-3 must keep reviewers: %v", pr3.Reviewers)
+	}
+
+	res, err = r.Users.DeactivateWithReassign(ctx, "backend", nil, removeAll, TestAudit)
+	if err != nil {
+		t.Fatalf("deactivate team: %v", err)
+	}
+	if got := Sorted(res.Deactivated); !slices.Equal(got, []string{"u1", "u2", "u3", "u4"}) {
+		t.Fatalf("team deactivation: %v", got)
+	}
+	pr1, _ = r.PRs.GetByID(ctx, "pr-1")
+	if len(pr1.Reviewers) != 0 {
+		t.Fatalf("pr-1 reviewers after team deactivation: %v", pr1.Reviewers)
+	}
+}
+
+func testHistory(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"})
+	MustCreatePR(t, r, "pr-1", "u1", "u2", "u3")
+	MustCreatePR(t, r, "pr-2", "u1", "u2")
+
+	if err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u4", models.Audit{Actor: "alice", Reason: "vacation"}); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := r.PRs.Merge(ctx, "pr-1", TestAudit); err != nil {
+			t.Fatalf("merge: %v", err)
+		}
+	}
+
+	recs, err := r.PRs.ListHistory(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	type entry struct{ action, reviewer, newReviewer string }
+	var got []entry
+	for _, rec := range recs {
+		got = append(got, entry{rec.Action, rec.ReviewerID, rec.NewReviewerID})
+	}
+	if len(got) != 5 {
+		t.Fatalf("want 2 ASSIGNED + 1 REASSIGNED + 2 RELEASED (merge twice), got %+v", got)
+	}
+	assigned := Sorted([]string{got[0].reviewer, got[1].reviewer})
+	if got[0].action != models.ActionAssigned || got[1].action != models.ActionAssigned || !slices.Equal(assigned, []string{"u2", "u3"}) {
+		t.Fatalf("assigned records: %+v", got[:2])
+	}
+	if got[2] != (entry{models.ActionReassigned, "u2", "u4"}) || recs[2].Actor != "alice" || recs[2].Reason != "vacation" {
+		t.Fatalf("reassigned record: %+v", recs[2])
+	}
+	released := Sorted([]string{got[3].reviewer, got[4].reviewer})
+	if got[3].action != models.ActionReleased || got[4].action != models.ActionReleased || !slices.Equal(released, []string{"u3", "u4"}) {
+		t.Fatalf("released records: %+v", got[3:])
+	}
+	for i := 1; i < len(recs); i++ {
+		if recs[i].ID <= recs[i-1].ID {
+			t.Fatalf("history must be ordered by id: %+v", recs)
+		}
+	}
+
+	if _, err := r.Users.DeactivateWithReassign(ctx, "", []string{"u2"}, removeAll, TestAudit); err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	recs, err = r.PRs.ListHistory(ctx, "pr-2")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(recs) != 2 || recs[1].Action != models.ActionRemoved || recs[1].ReviewerID != "u2" || recs[1].Actor != "tester" {
+		t.Fatalf("pr-2 history after deactivation: %+v", recs)
+	}
+}
+
+// Проверяет контракт вместе с сервисом: автор и неактивные никогда не назначаются.
+func testLifecycle(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+
+	draft := &models.PullRequest{ID: "pr-1", Title: "t", AuthorID: "u1", Status: models.StatusDraft}
+	if err := r.PRs.CreateWithReviewers(ctx, draft, TestAudit); err != nil {
+		t.Fatalf("create draft: %v", err)
+	}
+	if err := r.PRs.OpenWithReviewers(ctx, &models.PullRequest{ID: "pr-1", Reviewers: []string{"u2"}}, models.StatusClosed, TestAudit); !errors.Is(err, domain.ErrInvalidState) {
+		t.Fatalf("open from wrong status: want ErrInvalidState, got %v", err)
+	}
+
+	ready := &models.PullRequest{ID: "pr-1", Reviewers: []string{"u2"}}
+	if err := r.PRs.OpenWithReviewers(ctx, ready, models.StatusDraft, TestAudit); err != nil {
+		t.Fatalf("ready: %v", err)
+	}
+	got, _ := r.PRs.GetByID(ctx, "pr-1")
+	if got.Status != models.StatusOpen || !slices.Equal(got.Reviewers, []string{"u2"}) {
+		t.Fatalf("after ready: %+v", got)
+	}
+
+	if err := r.PRs.Close(ctx, "pr-1", TestAudit); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	got, _ = r.PRs.GetByID(ctx, "pr-1")
+	if got.Status != models.StatusClosed || len(got.Reviewers) != 0 {
+		t.Fatalf("close must release reviewers: %+v", got)
+	}
+	// Закрытый PR не мержится и не трогается повторным close
+	if err := r.PRs.Merge(ctx, "pr-1", TestAudit); err != nil {
+		t.Fatalf("merge closed: %v", err)
+	}
+	if err := r.PRs.Close(ctx, "pr-1", TestAudit); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+	got, _ = r.PRs.GetByID(ctx, "pr-1")
+	if got.Status != models.StatusClosed || got.MergedAt != nil {
+		t.Fatalf("closed pr changed: %+v", got)
+	}
+
+	reopened := &models.PullRequest{ID: "pr-1", Reviewers: []string{"u3"}}
+	if err := r.PRs.OpenWithReviewers(ctx, reopened, models.StatusClosed, TestAudit); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, _ = r.PRs.GetByID(ctx, "pr-1")
+	if got.Status != models.StatusOpen || !slices.Equal(got.Reviewers, []string{"u3"}) {
+		t.Fatalf("after reopen: %+v", got)
+	}
+
+	recs, err := r.PRs.ListHistory(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	var actions []string
+	for _, rec := range recs {
+		actions = append(actions, rec.Action+":"+rec.ReviewerID)
+	}
+	want := []string{"ASSIGNED:u2", "RELEASED:u2", "ASSIGNED:u3"}
+	if !slices.Equal(actions, want) {
+		t.Fatalf("history %v, want %v", actions, want)
+	}
+}
+
+func testReviewState(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"})
+	MustCreatePR(t, r, "pr-1", "u1", "u2", "u3")
+
+	got, _ := r.PRs.GetByID(ctx, "pr-1")
+	if got.ReviewState("u2") != models.ReviewPending || got.Approvals() != 0 {
+		t.Fatalf("new reviewers must be PENDING: %+v", got.ReviewStates)
+	}
+
+	if err := r.PRs.SetReviewState(ctx, "pr-1", "u2", models.ReviewApproved); err != nil {
+		t.Fatalf("set state: %v", err)
+	}
+	if err := r.PRs.SetReviewState(ctx, "pr-1", "u3", models.ReviewChangesRequested); err != nil {
+		t.Fatalf("set state: %v", err)
+	}
+	if err := r.PRs.SetReviewState(ctx, "pr-1", "u4", models.ReviewApproved); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unassigned reviewer: want ErrNotFound, got %v", err)
+	}
+	got, _ = r.PRs.GetByID(ctx, "pr-1")
+	if got.ReviewState("u2") != models.ReviewApproved || got.ReviewState("u3") != models.ReviewChangesRequested || got.Approvals() != 1 {
+		t.Fatalf("states after review: %+v", got.ReviewStates)
+	}
+
+	// Замена не наследует решение прежнего ревьюера
+	if err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u4", TestAudit); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	got, _ = r.PRs.GetByID(ctx, "pr-1")
+	if got.ReviewState("u4") != models.ReviewPending || got.Approvals() != 0 {
+		t.Fatalf("replacement must start PENDING: %+v", got.ReviewStates)
+	}
+
+}
+
+func testTeamSettings(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"lead", "u1", "u2", "u3", "u4"})
+
+	if _, err := r.Teams.GetSettings(ctx, "backend"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("settings not set: want ErrNotFound, got %v", err)
+	}
+	bad := &models.TeamSettings{TeamName: "ghost", ReviewerCount: 1}
+	if err := r.Teams.UpsertSettings(ctx, bad); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown team: want ErrNotFound, got %v", err)
+	}
+
+	want := models.TeamSettings{TeamName: "backend", ReviewerCount: 3, MinApprovals: 1, LeadUserID: "lead", RequireLead: true, Strategy: "round_robin"}
+	if err := r.Teams.UpsertSettings(ctx, &want); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	got, err := r.Teams.GetSettings(ctx, "backend")
+	if err != nil || !reflect.DeepEqual(*got, want) {
+		t.Fatalf("settings %+v, %v; want %+v", got, err, want)
+	}
+
+}
+
+func testFallbackTeams(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "small", []string{"s1", "s2"})
+	SeedTeam(t, r, "partner", []string{"p1", "p2"})
+
+	bad := &models.TeamSettings{TeamName: "small", ReviewerCount: 2, FallbackTeams: []string{"ghost"}}
+	if err := r.Teams.UpsertSettings(ctx, bad); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown fallback team: want ErrNotFound, got %v", err)
+	}
+	settings := &models.TeamSettings{TeamName: "small", ReviewerCount: 2, FallbackTeams: []string{"partner"}}
+	if err := r.Teams.UpsertSettings(ctx, settings); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+
+	pr := &models.PullRequest{
+		ID: "pr-1", Title: "t", AuthorID: "s1", Status: models.StatusOpen,
+		Reviewers: []string{"s2", "p1"}, Fallback: map[string]bool{"p1": true},
+	}
+	if err := r.PRs.CreateWithReviewers(ctx, pr, TestAudit); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	stored, _ := r.PRs.GetByID(ctx, "pr-1")
+	fb := "p1"
+	if !stored.Fallback[fb] || stored.Fallback["s2"] {
+		t.Fatalf("stored fallback flags: %v", stored.Fallback)
+	}
+
+	// Замена из той же запасной команды остаётся запасной
+	other := "p2"
+	if err := r.PRs.ReplaceReviewer(ctx, "pr-1", fb, other, TestAudit); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	stored, _ = r.PRs.GetByID(ctx, "pr-1")
+	if !stored.Fallback[other] || stored.Fallback[fb] {
+		t.Fatalf("fallback flag after replace: %v", stored.Fallback)
+	}
+	// Передача ревью запасного ревьюера: замена из запасных команд команды автора,
+	// а не из прежней команды ревьюера
+	SeedTeam(t, r, "design", []string{"d1"})
+	var seen []models.AffectedReview
+	plan := func(reviews []models.AffectedReview, settings map[string]*models.TeamSettings, candidates map[string][]models.ReviewCandidate) []models.ReviewerChange {
+		seen = reviews
+		if ts := settings["small"]; ts == nil || !slices.Equal(ts.FallbackTeams, []string{"partner"}) {
+			t.Errorf("settings of the author team: %+v", ts)
+		}
+		if ids := candidateIDs(candidates["partner"]); !slices.Equal(ids, []string{"p1"}) || candidates["small"] != nil {
+			t.Errorf("candidates: %v", candidates)
+		}
+		return []models.ReviewerChange{{PRID: "pr-1", OldReviewerID: other, NewReviewerID: fb}}
+	}
+	if _, _, err := r.Users.MoveTeam(ctx, other, "design", true, plan, TestAudit); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if len(seen) != 1 || seen[0].AuthorTeam != "small" || !seen[0].Fallback {
+		t.Fatalf("affected reviews: %+v", seen)
+	}
+	stored, _ = r.PRs.GetByID(ctx, "pr-1")
+	if !stored.Fallback[fb] || stored.Fallback["s2"] {
+		t.Fatalf("fallback flag after handover: %v", stored.Fallback)
+	}
+
+}
+
+func testTeamRename(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2"})
+	SeedTeam(t, r, "frontend", []string{"u3"})
+	settings := &models.TeamSettings{TeamName: "frontend", ReviewerCount: 1, FallbackTeams: []string{"backend"}}
+	if err := r.Teams.UpsertSettings(ctx, settings); err != nil {
+		t.Fatalf("upsert settings: %v", err)
+	}
+	if err := r.Teams.UpsertSettings(ctx, &models.TeamSettings{TeamName: "backend", ReviewerCount: 3}); err != nil {
+		t.Fatalf("upsert settings: %v", err)
+	}
+
+	if err := r.Teams.Rename(ctx, "backend", "frontend"); !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Fatalf("rename to taken name: want ErrAlreadyExists, got %v", err)
+	}
+	if err := r.Teams.Rename(ctx, "ghost", "x"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("rename unknown: want ErrNotFound, got %v", err)
+	}
+	if err := r.Teams.Rename(ctx, "backend", "platform"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+
+	if _, err := r.Teams.FindByName(ctx, "backend"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("old name still resolves: %v", err)
+	}
+	members, _ := r.Users.ListByTeam(ctx, "platform", false)
+	if len(members) != 2 {
+		t.Fatalf("members must follow the team: %v", members)
+	}
+	own, err := r.Teams.GetSettings(ctx, "platform")
+	if err != nil || own.ReviewerCount != 3 {
+		t.Fatalf("settings must follow the team: %+v, %v", own, err)
+	}
+	fe, _ := r.Teams.GetSettings(ctx, "frontend")
+	if !slices.Equal(fe.FallbackTeams, []string{"platform"}) {
+		t.Fatalf("fallback reference not renamed: %v", fe.FallbackTeams)
+	}
+}
+
+func testDetachAndDeleteTeam(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"})
+	SeedTeam(t, r, "frontend", []string{"f1"})
+	MustCreatePR(t, r, "pr-1", "u1", "u2", "u3")
+	MustCreatePR(t, r, "pr-2", "f1", "u2")
+
+	plan := func(reviews []models.AffectedReview, _ map[string]*models.TeamSettings, candidates map[string][]models.ReviewCandidate) []models.ReviewerChange {
+		var changes []models.ReviewerChange
+		for _, rv := range reviews {
+			c := models.ReviewerChange{PRID: rv.PRID, OldReviewerID: rv.ReviewerID}
+			if ids := candidateIDs(candidates[rv.AuthorTeam]); slices.Contains(ids, "u4") && rv.PRID == "pr-1" {
+				c.NewReviewerID = "u4"
+			}
+			changes = append(changes, c)
+		}
+		return changes
+	}
+
+	res, err := r.Users.DetachWithReassign(ctx, "backend", []string{"u2", "f1"}, plan, TestAudit)
+	if err != nil {
+		t.Fatalf("detach: %v", err)
+	}
+	if !slices.Equal(res.Deactivated, []string{"u2"}) {
+		t.Fatalf("only backend members are detached, got %v", res.Deactivated)
+	}
+	u2, _ := r.Users.GetByID(ctx, "u2")
+	if u2.TeamName != "" || !u2.IsActive {
+		t.Fatalf("detached user: %+v", u2)
+	}
+	pr1, _ := r.PRs.GetByID(ctx, "pr-1")
+	pr2, _ := r.PRs.GetByID(ctx, "pr-2")
+	if !slices.Equal(Sorted(pr1.Reviewers), []string{"u3", "u4"}) || len(pr2.Reviewers) != 0 {
+		t.Fatalf("reviewers after detach: pr-1 %v, pr-2 %v", pr1.Reviewers, pr2.Reviewers)
+	}
+	cands, _ := r.PRs.FindCandidates(ctx, "backend", nil)
+	if slices.Contains(candidateIDs(cands), "u2") {
+		t.Fatalf("detached user is still a candidate: %v", candidateIDs(cands))
+	}
+
+	res, err = r.Teams.Delete(ctx, "backend", removeAll, TestAudit)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if !slices.Equal(res.Deactivated, []string{"u1", "u3", "u4"}) || len(res.Changes) != 2 {
+		t.Fatalf("delete result: %+v", res)
+	}
+	if _, err := r.Teams.FindByName(ctx, "backend"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("team still exists: %v", err)
+	}
+	pr1, err = r.PRs.GetByID(ctx, "pr-1")
+	if err != nil || len(pr1.Reviewers) != 0 || pr1.Status != models.StatusOpen {
+		t.Fatalf("pr of deleted team: %+v, %v", pr1, err)
+	}
+	if _, err := r.Teams.Delete(ctx, "backend", removeAll, TestAudit); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("second delete: want ErrNotFound, got %v", err)
+	}
+}
+
+func testMoveTeam(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"})
+	SeedTeam(t, r, "frontend", []string{"f1"})
+	MustCreatePR(t, r, "pr-1", "u1", "u2", "u3")
+	MustCreatePR(t, r, "pr-2", "u1", "u4")
+
+	// Без передачи ревью пользователь остаётся ревьювером в PR прежней команды
+	move, changes, err := r.Users.MoveTeam(ctx, "u4", "frontend", false, removeAll, TestAudit)
+	if err != nil {
+		t.Fatalf("move u4: %v", err)
+	}
+	if move.FromTeam != "backend" || move.ToTeam != "frontend" || move.Handover || move.Actor != TestAudit.Actor || len(changes) != 0 {
+		t.Fatalf("move u4: %+v, changes %v", move, changes)
+	}
+	pr2, _ := r.PRs.GetByID(ctx, "pr-2")
+	if !slices.Equal(pr2.Reviewers, []string{"u4"}) {
+		t.Fatalf("kept review: %v", pr2.Reviewers)
+	}
+
+	// С передачей замена ищется в команде автора PR, а не в новой команде пользователя
+	plan := func(reviews []models.AffectedReview, _ map[string]*models.TeamSettings, candidates map[string][]models.ReviewCandidate) []models.ReviewerChange {
+		var changes []models.ReviewerChange
+		for _, rv := range reviews {
+			if rv.AuthorTeam != "backend" || slices.Contains(candidateIDs(candidates[rv.AuthorTeam]), "u2") {
+				t.Errorf("review %s planned with team %s, candidates %v", rv.PRID, rv.AuthorTeam, candidateIDs(candidates[rv.AuthorTeam]))
+			}
+			changes = append(changes, models.ReviewerChange{PRID: rv.PRID, OldReviewerID: rv.ReviewerID, NewReviewerID: "u1"})
+		}
+		return changes
+	}
+	move, changes, err = r.Users.MoveTeam(ctx, "u2", "frontend", true, plan, TestAudit)
+	if err != nil {
+		t.Fatalf("move u2: %v", err)
+	}
+	if !move.Handover || len(changes) != 1 {
+		t.Fatalf("move u2: %+v, changes %v", move, changes)
+	}
+	u2, _ := r.Users.GetByID(ctx, "u2")
+	if u2.TeamName != "frontend" {
+		t.Fatalf("moved user team: %q", u2.TeamName)
+	}
+	pr1, _ := r.PRs.GetByID(ctx, "pr-1")
+	if slices.Contains(pr1.Reviewers, "u2") {
+		t.Fatalf("handed over review is still assigned: %v", pr1.Reviewers)
+	}
+
+	if _, _, err := r.Users.MoveTeam(ctx, "u2", "nope", true, removeAll, TestAudit); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown team: want ErrNotFound, got %v", err)
+	}
+	if _, _, err := r.Users.MoveTeam(ctx, "ghost", "backend", true, removeAll, TestAudit); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown user: want ErrNotFound, got %v", err)
+	}
+
+	moves, err := r.Users.ListMoves(ctx, "u2")
+	if err != nil || len(moves) != 1 || moves[0].ID != move.ID || moves[0].FromTeam != "backend" {
+		t.Fatalf("moves of u2: %+v, %v", moves, err)
+	}
+}
+
+func testAbsences(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+	now := time.Now()
+
+	current := &models.Absence{UserID: "u2", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Reason: "vacation"}
+	future := &models.Absence{UserID: "u3", StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)}
+	for _, a := range []*models.Absence{current, future} {
+		if err := r.Absences.Create(ctx, a); err != nil {
+			t.Fatalf("create absence: %v", err)
+		}
+	}
+	if err := r.Absences.Create(ctx, &models.Absence{UserID: "ghost", StartsAt: now, EndsAt: now.Add(time.Hour)}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown user: want ErrNotFound, got %v", err)
+	}
+
+	cands, err := r.PRs.FindCandidates(ctx, "backend", []string{"u1"})
+	if err != nil {
+		t.Fatalf("find candidates: %v", err)
+	}
+	if got := candidateIDs(cands); !slices.Equal(got, []string{"u3"}) {
+		t.Fatalf("candidates %v, want [u3]: u2 is away, u3 is not yet", got)
+	}
+	pr := &models.PullRequest{ID: "pr-1", Title: "t", AuthorID: "u1", Reviewers: []string{"u2", "u3"}}
+	if err := r.PRs.CreateWithReviewers(ctx, pr, TestAudit); err != nil {
+		t.Fatalf("create pr: %v", err)
+	}
+	if !slices.Equal(pr.Reviewers, []string{"u3"}) {
+		t.Fatalf("away user was assigned: %v", pr.Reviewers)
+	}
+
+	started, err := r.Absences.ListStarted(ctx, now)
+	if err != nil || len(started) != 1 || started[0].ID != current.ID || started[0].Reason != "vacation" {
+		t.Fatalf("started: %+v, %v", started, err)
+	}
+	if err := r.Absences.MarkHandled(ctx, current.ID); err != nil {
+		t.Fatalf("mark handled: %v", err)
+	}
+	if started, _ := r.Absences.ListStarted(ctx, now); len(started) != 0 {
+		t.Fatalf("handled absence is listed again: %+v", started)
+	}
+	if started, _ := r.Absences.ListStarted(ctx, now.Add(90*time.Minute)); len(started) != 1 || started[0].ID != future.ID {
+		t.Fatalf("started later: %+v", started)
+	}
+
+	if err := r.Absences.Delete(ctx, current.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := r.Absences.Delete(ctx, current.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("second delete: want ErrNotFound, got %v", err)
+	}
+	list, err := r.Absences.ListByUser(ctx, "u2")
+	if err != nil || len(list) != 0 {
+		t.Fatalf("absences of u2 after delete: %+v, %v", list, err)
+	}
+	cands, _ = r.PRs.FindCandidates(ctx, "backend", []string{"u1"})
+	if got := candidateIDs(cands); !slices.Equal(got, []string{"u2", "u3"}) {
+		t.Fatalf("candidates after delete: %v", got)
+	}
+}
+
+func testWorkingHours(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"})
+
+	// Окна считаются от текущего времени в UTC, чтобы тест не зависел от часа запуска
+	now := time.Now().UTC()
+	minute := now.Hour()*60 + now.Minute()
+	at := func(offset int) int { return ((minute+offset)%1440 + 1440) % 1440 }
+	working := &models.WorkingHours{TimeZone: "UTC", Start: at(-60), End: at(60)}
+	offHours := &models.WorkingHours{TimeZone: "UTC", Start: at(120), End: at(180)}
+
+	if err := r.Users.SetWorkingHours(ctx, "u2", offHours); err != nil {
+		t.Fatalf("set hours u2: %v", err)
+	}
+	if err := r.Users.SetWorkingHours(ctx, "u3", offHours); err != nil {
+		t.Fatalf("set hours u3: %v", err)
+	}
+	if err := r.Users.SetWorkingHours(ctx, "u4", working); err != nil {
+		t.Fatalf("set hours u4: %v", err)
+	}
+	if err := r.Users.SetWorkingHours(ctx, "ghost", working); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown user: want ErrNotFound, got %v", err)
+	}
+
+	// Повторный /team/add не сбрасывает часы
+	MustUpsert(t, r, &models.User{ID: "u2", Name: "renamed", IsActive: true, TeamName: "backend"})
+	u2, _ := r.Users.GetByID(ctx, "u2")
+	if u2.WorkingHours == nil || *u2.WorkingHours != *offHours {
+		t.Fatalf("hours after upsert: %+v", u2.WorkingHours)
+	}
+
+	cands, _ := r.PRs.FindCandidates(ctx, "backend", []string{"u1"})
+	for _, c := range cands {
+		if c.Working(now) != (c.UserID == "u4") {
+			t.Fatalf("candidate %s: working=%v, hours %+v", c.UserID, c.Working(now), c.WorkingHours)
+		}
+	}
+
+	if err := r.Users.SetWorkingHours(ctx, "u2", nil); err != nil {
+		t.Fatalf("reset hours: %v", err)
+	}
+	if u2, _ := r.Users.GetByID(ctx, "u2"); u2.WorkingHours != nil {
+		t.Fatalf("hours after reset: %+v", u2.WorkingHours)
+	}
+}
+
+func testReviewSLA(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"}, "u5")
+	SeedTeam(t, r, "frontend", []string{"f1", "f2"})
+	before := time.Now()
+	pr := MustCreatePR(t, r, "pr-1", "u1", "u2", "u3")
+	MustCreatePR(t, r, "pr-2", "f1", "f2")
+
+	if at := pr.AssignedAt["u2"]; at.Before(before.Add(-time.Second)) || at.After(time.Now().Add(time.Second)) {
+		t.Fatalf("assigned_at on create: %v", pr.AssignedAt)
+	}
+	got, _ := r.PRs.GetByID(ctx, "pr-1")
+	if len(got.AssignedAt) != 2 {
+		t.Fatalf("assigned_at after load: %v", got.AssignedAt)
+	}
+
+	later := time.Now().Add(2 * time.Hour)
+	if overdue, _ := r.PRs.ListOverdue(ctx, "", later); len(overdue) != 0 {
+		t.Fatalf("no SLA configured, got %+v", overdue)
+	}
+	settings := models.TeamSettings{TeamName: "backend", ReviewerCount: 2, ReviewSLA: time.Hour, SLAAction: models.SLAActionAddReviewer}
+	if err := r.Teams.UpsertSettings(ctx, &settings); err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+	if ts, _ := r.Teams.GetSettings(ctx, "backend"); ts.ReviewSLA != time.Hour || ts.SLAAction != models.SLAActionAddReviewer {
+		t.Fatalf("settings round trip: %+v", ts)
+	}
+	if overdue, _ := r.PRs.ListOverdue(ctx, "backend", time.Now()); len(overdue) != 0 {
+		t.Fatalf("nothing is overdue yet, got %+v", overdue)
+	}
+
+	if err := r.PRs.SetReviewState(ctx, "pr-1", "u3", models.ReviewApproved); err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	overdue, err := r.PRs.ListOverdue(ctx, "", later)
+	if err != nil || len(overdue) != 1 {
+		t.Fatalf("overdue: %+v, %v", overdue, err)
+	}
+	if o := overdue[0]; o.PRID != "pr-1" || o.ReviewerID != "u2" || o.TeamName != "backend" || o.AuthorID != "u1" || o.Escalated {
+		t.Fatalf("overdue review: %+v", o)
+	}
+
+	if err := r.PRs.MarkEscalated(ctx, "pr-1", "u2"); err != nil {
+		t.Fatalf("mark escalated: %v", err)
+	}
+	if overdue, _ := r.PRs.ListOverdue(ctx, "backend", later); len(overdue) != 1 || !overdue[0].Escalated {
+		t.Fatalf("escalated flag: %+v", overdue)
+	}
+	if err := r.PRs.MarkEscalated(ctx, "pr-1", "u4"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("mark unassigned: want ErrNotFound, got %v", err)
+	}
+
+	// Замена начинает срок заново и снимает отметку
+	if err := r.PRs.ReplaceReviewer(ctx, "pr-1", "u2", "u4", TestAudit); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if overdue, _ := r.PRs.ListOverdue(ctx, "backend", time.Now()); len(overdue) != 0 {
+		t.Fatalf("replacement is not overdue yet, got %+v", overdue)
+	}
+	if overdue, _ := r.PRs.ListOverdue(ctx, "backend", later); len(overdue) != 1 || overdue[0].ReviewerID != "u4" || overdue[0].Escalated {
+		t.Fatalf("replacement later: %+v", overdue)
+	}
+
+	if err := r.PRs.AddReviewer(ctx, "pr-1", "u2", TestAudit); err != nil {
+		t.Fatalf("add reviewer: %v", err)
+	}
+	got, _ = r.PRs.GetByID(ctx, "pr-1")
+	if !slices.Equal(Sorted(got.Reviewers), []string{"u2", "u3", "u4"}) || got.AssignedAt["u2"].IsZero() {
+		t.Fatalf("after add: %v, %v", got.Reviewers, got.AssignedAt)
+	}
+	if err := r.PRs.AddReviewer(ctx, "pr-1", "u2", TestAudit); !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Fatalf("add twice: want ErrAlreadyExists, got %v", err)
+	}
+	if err := r.PRs.AddReviewer(ctx, "pr-1", "u5", TestAudit); !errors.Is(err, domain.ErrNoCandidate) {
+		t.Fatalf("add inactive: want ErrNoCandidate, got %v", err)
+	}
+	if err := r.PRs.Merge(ctx, "pr-1", TestAudit); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if err := r.PRs.AddReviewer(ctx, "pr-1", "u1", TestAudit); !errors.Is(err, domain.ErrInvalidState) {
+		t.Fatalf("add to merged: want ErrInvalidState, got %v", err)
+	}
+	if overdue, _ := r.PRs.ListOverdue(ctx, "", later); len(overdue) != 0 {
+		t.Fatalf("merged PR is not overdue, got %+v", overdue)
+	}
+}
+
+func prIDs(prs []*models.PullRequest) []string {
+	ids := make([]string, len(prs))
+	for i, pr := range prs {
+		ids[i] = pr.ID
+	}
+	return ids
+}
+
+func testListPRs(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+	SeedTeam(t, r, "frontend", []string{"f1", "f2"})
+	MustCreatePR(t, r, "pr-1", "u1", "u3", "u2")
+	MustCreatePR(t, r, "pr-2", "u2", "u3")
+	MustCreatePR(t, r, "pr-3", "u1")
+	MustCreatePR(t, r, "pr-4", "f1", "f2")
+	beforeMerge := time.Now().Add(-time.Second)
+	if err := r.PRs.Merge(ctx, "pr-1", TestAudit); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	list := func(f models.PRFilter) []*models.PullRequest {
+		t.Helper()
+		if f.Limit == 0 {
+			f.Limit = 10
+		}
+		prs, err := r.PRs.List(ctx, f)
+		if err != nil {
+			t.Fatalf("list %+v: %v", f, err)
+		}
+		return prs
+	}
+
+	all := list(models.PRFilter{})
+	if got := prIDs(all); !slices.Equal(got, []string{"pr-4", "pr-3", "pr-2", "pr-1"}) {
+		t.Fatalf("newest first: %v", got)
+	}
+	if pr1 := all[3]; !slices.Equal(pr1.Reviewers, []string{"u2", "u3"}) || pr1.Status != models.StatusMerged || pr1.MergedAt == nil {
+		t.Fatalf("pr-1 in list: %+v", pr1)
+	}
+
+	for name, tc := range map[string]struct {
+		filter models.PRFilter
+		want   []string
+	}{
+		"status":   {models.PRFilter{Status: models.StatusOpen}, []string{"pr-4", "pr-3", "pr-2"}},
+		"author":   {models.PRFilter{AuthorID: "u1"}, []string{"pr-3", "pr-1"}},
+		"reviewer": {models.PRFilter{ReviewerID: "u3"}, []string{"pr-2", "pr-1"}},
+		"team":     {models.PRFilter{TeamName: "frontend"}, []string{"pr-4"}},
+		"combined": {models.PRFilter{TeamName: "backend", Status: models.StatusOpen, ReviewerID: "u3"}, []string{"pr-2"}},
+		"created":  {models.PRFilter{CreatedFrom: &all[1].CreatedAt, CreatedTo: &all[0].CreatedAt}, []string{"pr-3"}},
+		"merged":   {models.PRFilter{MergedFrom: &beforeMerge}, []string{"pr-1"}},
+		"unmerged": {models.PRFilter{MergedTo: &beforeMerge}, nil},
+	} {
+		if got := prIDs(list(tc.filter)); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", name, got, tc.want)
+		}
+	}
+
+	page := list(models.PRFilter{Limit: 2})
+	if got := prIDs(page); !slices.Equal(got, []string{"pr-4", "pr-3"}) {
+		t.Fatalf("first page: %v", got)
+	}
+	after := &models.PRCursor{CreatedAt: page[1].CreatedAt, ID: page[1].ID}
+	if got := prIDs(list(models.PRFilter{Limit: 2, After: after})); !slices.Equal(got, []string{"pr-2", "pr-1"}) {
+		t.Fatalf("second page: %v", got)
+	}
+	if got := list(models.PRFilter{After: &models.PRCursor{CreatedAt: all[3].CreatedAt, ID: all[3].ID}}); len(got) != 0 {
+		t.Fatalf("after the last PR: %v", prIDs(got))
+	}
+}
+
+func testIdentities(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2"})
+
+	if _, err := r.Users.FindByIdentity(ctx, models.PlatformGitHub, "alice-dev"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unlinked: want ErrNotFound, got %v", err)
+	}
+	if err := r.Users.LinkIdentity(ctx, models.PlatformGitHub, "alice-dev", "u1"); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if u, err := r.Users.FindByIdentity(ctx, models.PlatformGitHub, "alice-dev"); err != nil || u.ID != "u1" || u.TeamName != "backend" {
+		t.Fatalf("find: %+v, %v", u, err)
+	}
+	if _, err := r.Users.FindByIdentity(ctx, models.PlatformGitLab, "alice-dev"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("other platform: want ErrNotFound, got %v", err)
+	}
+
+	// Логин переходит к другому пользователю
+	if err := r.Users.LinkIdentity(ctx, models.PlatformGitHub, "alice-dev", "u2"); err != nil {
+		t.Fatalf("relink: %v", err)
+	}
+	if u, _ := r.Users.FindByIdentity(ctx, models.PlatformGitHub, "alice-dev"); u == nil || u.ID != "u2" {
+		t.Fatalf("relinked to %+v", u)
+	}
+	if err := r.Users.LinkIdentity(ctx, models.PlatformGitHub, "ghost", "nope"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown user: want ErrNotFound, got %v", err)
+	}
+}
+
+func testWebhookDeliveries(t *testing.T, r Repos) {
+	ctx := context.Background()
+	all := &models.WebhookSubscription{URL: "http://a.test/hook", Secret: "a", Active: true}
+	merged := &models.WebhookSubscription{URL: "http://b.test/hook", Secret: "b", Events: []string{models.EventPRMerged}, Active: true}
+	off := &models.WebhookSubscription{URL: "http://c.test/hook", Secret: "c", Active: false}
+	for _, sub := range []*models.WebhookSubscription{all, merged, off} {
+		if err := r.Webhooks.CreateSubscription(ctx, sub); err != nil {
+			t.Fatalf("create subscription: %v", err)
+		}
+	}
+	if got, err := r.Webhooks.GetSubscription(ctx, merged.ID); err != nil || !reflect.DeepEqual(got.Events, merged.Events) || got.Secret != "b" {
+		t.Fatalf("get: %+v, %v", got, err)
+	}
+	if _, err := r.Webhooks.GetSubscription(ctx, 0); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown subscription: want ErrNotFound, got %v", err)
+	}
+
+	// Неактивная подписка и подписка на другие события доставок не получают
+	if n, err := r.Webhooks.Enqueue(ctx, 1, models.EventPRAssigned, []byte(`{"n":1}`)); err != nil || n != 1 {
+		t.Fatalf("enqueue assigned: %d, %v", n, err)
+	}
+	if n, err := r.Webhooks.Enqueue(ctx, 2, models.EventPRMerged, []byte(`{"n":2}`)); err != nil || n != 2 {
+		t.Fatalf("enqueue merged: %d, %v", n, err)
+	}
+	// Повтор события outbox доставок не добавляет
+	if n, err := r.Webhooks.Enqueue(ctx, 2, models.EventPRMerged, []byte(`{"n":2}`)); err != nil || n != 0 {
+		t.Fatalf("enqueue merged again: %d, %v", n, err)
+	}
+
+	now := time.Now().Add(time.Second)
+	due, err := r.Webhooks.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+	if err != nil || len(due) != 3 {
+		t.Fatalf("claim: %d deliveries, %v", len(due), err)
+	}
+	first, second, third := due[0], due[1], due[2]
+	if first.SubscriptionID != all.ID || first.EventID != 1 || first.Event != models.EventPRAssigned || first.URL != all.URL || first.Secret != "a" ||
+		string(first.Payload) != `{"n":1}` || first.Status != models.DeliveryPending {
+		t.Fatalf("first delivery: %+v", first)
+	}
+	// Взятые доставки скрыты до конца аренды
+	if again, _ := r.Webhooks.ClaimDue(ctx, now, now.Add(time.Minute), 10); len(again) != 0 {
+		t.Fatalf("claimed twice: %+v", again)
+	}
+	if again, _ := r.Webhooks.ClaimDue(ctx, now.Add(2*time.Minute), now.Add(3*time.Minute), 10); len(again) != 3 {
+		t.Fatalf("lease expired: %d deliveries, want 3", len(again))
+	}
+
+	if err := r.Webhooks.MarkDelivered(ctx, first.ID, 204); err != nil {
+		t.Fatalf("mark delivered: %v", err)
+	}
+	if err := r.Webhooks.MarkDelivered(ctx, first.ID, 204); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("delivered twice: want ErrNotFound, got %v", err)
+	}
+
+	code := 503
+	retryAt := now.Add(10 * time.Minute)
+	if err := r.Webhooks.MarkFailed(ctx, second.ID, &code, "unavailable", &retryAt); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+	if err := r.Webhooks.MarkFailed(ctx, third.ID, nil, "timeout", nil); err != nil {
+		t.Fatalf("mark dead: %v", err)
+	}
+
+	log, err := r.Webhooks.ListDeliveries(ctx, all.ID, 10)
+	if err != nil || len(log) != 2 {
+		t.Fatalf("deliveries: %+v, %v", log, err)
+	}
+	if log[0].ID != second.ID || log[0].Status != models.DeliveryPending || log[0].Attempts != 1 ||
+		log[0].LastStatusCode == nil || *log[0].LastStatusCode != 503 || log[0].LastError != "unavailable" {
+		t.Fatalf("failed delivery: %+v", log[0])
+	}
+	if log[1].ID != first.ID || log[1].Status != models.DeliveryDelivered || log[1].Attempts != 1 || log[1].DeliveredAt == nil {
+		t.Fatalf("delivered: %+v", log[1])
+	}
+	if limited, _ := r.Webhooks.ListDeliveries(ctx, all.ID, 1); len(limited) != 1 || limited[0].ID != second.ID {
+		t.Fatalf("limit: %+v", limited)
+	}
+
+	letters, err := r.Webhooks.ListDeadLetters(ctx)
+	if err != nil || len(letters) != 1 {
+		t.Fatalf("dead letters: %+v, %v", letters, err)
+	}
+	if l := letters[0]; l.DeliveryID != third.ID || l.SubscriptionID != merged.ID || l.Event != models.EventPRMerged ||
+		string(l.Payload) != `{"n":2}` || l.Attempts != 1 || l.LastError != "timeout" {
+		t.Fatalf("dead letter: %+v", l)
+	}
+	if due, _ := r.Webhooks.ClaimDue(ctx, now.Add(5*time.Minute), now.Add(6*time.Minute), 10); len(due) != 0 {
+		t.Fatalf("dead or delayed deliveries claimed: %+v", due)
+	}
+
+	if err := r.Webhooks.Redeliver(ctx, letters[0].ID); err != nil {
+		t.Fatalf("redeliver: %v", err)
+	}
+	if err := r.Webhooks.Redeliver(ctx, letters[0].ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("redeliver twice: want ErrNotFound, got %v", err)
+	}
+	if letters, _ := r.Webhooks.ListDeadLetters(ctx); len(letters) != 0 {
+		t.Fatalf("dead letters after redeliver: %+v", letters)
+	}
+	due, _ = r.Webhooks.ClaimDue(ctx, now.Add(5*time.Minute), now.Add(6*time.Minute), 10)
+	if len(due) != 1 || due[0].ID != third.ID || due[0].Attempts != 0 || due[0].LastError != "" {
+		t.Fatalf("redelivered: %+v", due)
+	}
+
+	// Доставки выключенной подписки не отправляются
+	merged.Active = false
+	if err := r.Webhooks.UpdateSubscription(ctx, merged); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if due, _ := r.Webhooks.ClaimDue(ctx, now.Add(10*time.Minute), now.Add(11*time.Minute), 10); len(due) != 1 || due[0].ID != second.ID {
+		t.Fatalf("claim after deactivation: %+v", due)
+	}
+
+	if err := r.Webhooks.DeleteSubscription(ctx, all.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := r.Webhooks.DeleteSubscription(ctx, all.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("delete twice: want ErrNotFound, got %v", err)
+	}
+	if log, _ := r.Webhooks.ListDeliveries(ctx, all.ID, 10); len(log) != 0 {
+		t.Fatalf("deliveries of deleted subscription: %+v", log)
+	}
+	if subs, _ := r.Webhooks.ListSubscriptions(ctx); len(subs) != 2 || subs[0].ID != merged.ID || subs[1].ID != off.ID {
+		t.Fatalf("subscriptions: %+v", subs)
+	}
+}
+
+func testOutbox(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"})
+
+	pr := &models.PullRequest{ID: "pr-1", Title: "Search", AuthorID: "u1", Status: models.StatusOpen, Reviewers: []string{"u2", "u3"}}
+	if err := r.PRs.CreateWithReviewers(ctx, pr, models.Audit{Actor: "alice"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	dup := &models.PullRequest{ID: "pr-1", Title: "Search", AuthorID: "u1", Status: models.StatusOpen}
+	if err := r.PRs.CreateWithReviewers(ctx, dup, models.Audit{Actor: "alice"}); !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Fatalf("duplicate: want ErrAlreadyExists, got %v", err)
+	}
+	old, replacement := "u2", "u4"
+	if err := r.PRs.ReplaceReviewer(ctx, "pr-1", old, replacement, models.Audit{Actor: "bob"}); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if _, err := r.Users.DeactivateWithReassign(ctx, "", []string{replacement}, planFirstFree, models.Audit{Actor: "bob", Reason: "reviewer deactivated"}); err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if err := r.PRs.Merge(ctx, "pr-1", models.Audit{Actor: "carol"}); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	// Повторное выключение события не даёт
+	for range 2 {
+		if err := r.Users.SetActive(ctx, "u1", false, TestAudit); err != nil {
+			t.Fatalf("set inactive: %v", err)
+		}
+	}
+
+	// publish, не подтвердивший ни одного события, ничего не помечает
+	var pending []models.OutboxEvent
+	peek := func(ctx context.Context, events []models.OutboxEvent) (int, error) {
+		pending = events
+		return 0, nil
+	}
+	if n, err := r.Outbox.PublishPending(ctx, models.OutboxConsumerInternal, 100, peek); n != 0 || err != nil {
+		t.Fatalf("peek: %d, %v", n, err)
+	}
+	var types []string
+	for i, ev := range pending {
+		types = append(types, ev.Type)
+		if i > 0 && ev.ID <= pending[i-1].ID {
+			t.Fatalf("events out of order: %+v", pending)
+		}
+	}
+	want := []string{
+		models.OutboxPRCreated, models.OutboxReviewerAssigned, models.OutboxReviewerAssigned,
+		models.OutboxReviewerReplaced, models.OutboxUserDeactivated, models.OutboxReviewerReplaced,
+		models.OutboxPRMerged, models.OutboxUserDeactivated,
+	}
+	if !slices.Equal(types, want) {
+		t.Fatalf("event types %v, want %v", types, want)
+	}
+	if ev := pending[0]; ev.PRID != "pr-1" || ev.UserID != "u1" || ev.Actor != "alice" || ev.CreatedAt.IsZero() {
+		t.Fatalf("pr.created: %+v", ev)
+	}
+	if ev := pending[3]; ev.PRID != "pr-1" || ev.ReviewerID != old || ev.NewReviewerID != replacement || ev.Actor != "bob" {
+		t.Fatalf("reviewer.replaced: %+v", ev)
+	}
+	if ev := pending[4]; ev.UserID != replacement || ev.Actor != "bob" || ev.Reason != "reviewer deactivated" {
+		t.Fatalf("user.deactivated: %+v", ev)
+	}
+	if ev := pending[5]; ev.ReviewerID != replacement {
+		t.Fatalf("replaced after deactivation: %+v", ev)
+	}
+	if ev := pending[6]; ev.PRID != "pr-1" || ev.Actor != "carol" {
+		t.Fatalf("pr.merged: %+v", ev)
+	}
+
+	// Помечаются только подтверждённые, ошибка publish возвращается
+	errStop := errors.New("stop")
+	n, err := r.Outbox.PublishPending(ctx, models.OutboxConsumerInternal, 100, func(ctx context.Context, events []models.OutboxEvent) (int, error) {
+		return 2, errStop
+	})
+	if n != 2 || !errors.Is(err, errStop) {
+		t.Fatalf("partial publish: %d, %v", n, err)
+	}
+	if _, err := r.Outbox.PublishPending(ctx, models.OutboxConsumerInternal, 3, peek); err != nil || len(pending) != 3 || pending[0].Type != models.OutboxReviewerAssigned {
+		t.Fatalf("after partial publish: %+v, %v", pending, err)
+	}
+
+	// У внешнего получателя своя отметка: чужая публикация её не сдвигает
+	if _, err := r.Outbox.PublishPending(ctx, models.OutboxConsumerExternal, 100, peek); err != nil || len(pending) != len(want) || pending[0].Type != models.OutboxPRCreated {
+		t.Fatalf("external consumer: %+v, %v", pending, err)
+	}
+	if _, err := r.Outbox.PublishPending(ctx, "unknown", 100, peek); err == nil {
+		t.Fatal("unknown consumer must fail")
+	}
+
+	// Пока relay получателя публикует, второй ничего не берёт
+	blocked := func(ctx context.Context, events []models.OutboxEvent) (int, error) {
+		if n, err := r.Outbox.PublishPending(ctx, models.OutboxConsumerExternal, 100, peek); n != 0 || err != nil {
+			t.Errorf("concurrent relay: %d, %v", n, err)
+		}
+		return len(events), nil
+	}
+	pending = nil
+	if n, err := r.Outbox.PublishPending(ctx, models.OutboxConsumerExternal, 100, blocked); n != len(want) || err != nil || pending != nil {
+		t.Fatalf("leased publish: %d, %v, %+v", n, err, pending)
+	}
+	if n, err := r.Outbox.PublishPending(ctx, models.OutboxConsumerExternal, 100, peek); n != 0 || err != nil {
+		t.Fatalf("after lease released: %d, %v", n, err)
+	}
+}
+
+func testNotifications(t *testing.T, r Repos) {
+	ctx := context.Background()
+	SeedTeam(t, r, "backend", []string{"u1", "u2"})
+
+	items := []models.Notification{{UserID: "u1", Channel: "#backend", Text: "first"}, {UserID: "u2", Text: "second"}}
+	if err := r.Notifications.Enqueue(ctx, 1, items); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	var got []string
+	send := func(ctx context.Context, batch []models.Notification) error {
+		for _, n := range batch {
+			got = append(got, n.Text)
+		}
+		return nil
+	}
+	if sent, failed, err := r.Notifications.SendPending(ctx, time.Now(), 10, 3, send); err != nil || sent != 2 || failed != 0 {
+		t.Fatalf("send: %d sent, %d failed, %v", sent, failed, err)
+	}
+	// Повтор события после отправки не ставит уведомления снова
+	if err := r.Notifications.Enqueue(ctx, 1, items); err != nil {
+		t.Fatalf("repeated enqueue: %v", err)
+	}
+	if sent, _, err := r.Notifications.SendPending(ctx, time.Now(), 10, 3, send); err != nil || sent != 0 {
+		t.Fatalf("repeated event sent %d, %v", sent, err)
+	}
+	if !slices.Equal(got, []string{"first", "second"}) {
+		t.Fatalf("sent %v", got)
+	}
+
+	// Неизвестный получатель — ErrNotFound, событие не отмечается
+	if err := r.Notifications.Enqueue(ctx, 2, []models.Notification{{UserID: "ghost", Text: "lost"}}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown user: want ErrNotFound, got %v", err)
+	}
+	if err := r.Notifications.Enqueue(ctx, 2, []models.Notification{{UserID: "u2", Text: "third"}}); err != nil {
+		t.Fatalf("enqueue after failure: %v", err)
+	}
+	if sent, _, err := r.Notifications.SendPending(ctx, time.Now(), 10, 3, send); err != nil || sent != 1 {
+		t.Fatalf("after failure sent %d, %v", sent, err)
+	}
+}
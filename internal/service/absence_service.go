@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+)
+
+type AbsenceService struct {
+	absenceRepo repo.AbsenceRepository
+	userRepo    repo.UserRepository
+	prRepo      repo.PRRepository
+	prService   *PRService
+}
+
+func NewAbsenceService(absenceRepo repo.AbsenceRepository, userRepo repo.UserRepository, prRepo repo.PRRepository, prService *PRService) *AbsenceService {
+	return &AbsenceService{absenceRepo: absenceRepo, userRepo: userRepo, prRepo: prRepo, prService: prService}
+}
+
+// Add регистрирует отсутствие. Уже закончившееся не принимается.
+func (s *AbsenceService) Add(ctx context.Context, a *models.Absence) error {
+	if !a.EndsAt.After(a.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at: %w", domain.ErrInvalidInput)
+	}
+	if !a.EndsAt.After(time.Now()) {
+		return fmt.Errorf("absence is already over: %w", domain.ErrInvalidInput)
+	}
+	return s.absenceRepo.Create(ctx, a)
+}
+
+func (s *AbsenceService) List(ctx context.Context, userID string) ([]models.Absence, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.absenceRepo.ListByUser(ctx, userID)
+}
+
+func (s *AbsenceService) Delete(ctx context.Context, id int64) error {
+	return s.absenceRepo.Delete(ctx, id)
+}
+
+// HandleStarted переназначает ревью в OPEN PR тех, чьё отсутствие началось к now,
+// по правилам /pullRequest/reassign. Если замены нет, ревьюер пока остаётся, а
+// отсутствие — необработанным: следующий запуск попробует снова (уже заменённых
+// ревью к тому времени у пользователя нет), пока отсутствие не закончится.
+func (s *AbsenceService) HandleStarted(ctx context.Context, now time.Time) ([]models.ReviewerChange, error) {
+	absences, err := s.absenceRepo.ListStarted(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []models.ReviewerChange
+	for _, a := range absences {
+		prs, err := s.prRepo.ListByReviewer(ctx, a.UserID)
+		if err != nil {
+			return changes, err
+		}
+		unreplaced := false
+		for _, pr := range prs {
+			if pr.Status != models.StatusOpen {
+				continue
+			}
+			_, newID, err := s.prService.Reassign(ctx, pr.ID, a.UserID, systemActor, "out of office")
+			switch {
+			case err == nil:
+				changes = append(changes, models.ReviewerChange{PRID: pr.ID, OldReviewerID: a.UserID, NewReviewerID: newID})
+			case errors.Is(err, domain.ErrNoCandidate):
+				unreplaced = true
+			case errors.Is(err, domain.ErrNotAssigned),
+				errors.Is(err, domain.ErrInvalidState),
+				errors.Is(err, domain.ErrPRMerged):
+				// PR успел измениться
+			default:
+				return changes, err
+			}
+		}
+		if unreplaced {
+			continue
+		}
+		if err := s.absenceRepo.MarkHandled(ctx, a.ID); err != nil {
+			return changes, err
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+)
+
+func TestHandleStartedReassignsAbsent(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"})
+	repotest.SeedTeam(t, r, "solo", []string{"s1", "s2"})
+	repotest.MustCreatePR(t, r, "pr-1", "u1", "u2")
+	repotest.MustCreatePR(t, r, "pr-2", "s1", "s2")
+
+	selector, _ := NewReviewerSelector(StrategyRoundRobin, nil)
+	prs := NewPRService(r.PRs, r.Users, r.Teams, selector, DefaultTeamSettings(), nil)
+	svc := NewAbsenceService(r.Absences, r.Users, r.PRs, prs)
+
+	now := time.Now()
+	for _, id := range []string{"u2", "u3", "s2"} {
+		a := &models.Absence{UserID: id, StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)}
+		if err := svc.Add(ctx, a); err != nil {
+			t.Fatalf("add absence %s: %v", id, err)
+		}
+	}
+	if err := svc.Add(ctx, &models.Absence{UserID: "u2", StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("past absence: want ErrInvalidInput, got %v", err)
+	}
+
+	changes, err := svc.HandleStarted(ctx, now)
+	if err != nil {
+		t.Fatalf("handle started: %v", err)
+	}
+	want := []models.ReviewerChange{{PRID: "pr-1", OldReviewerID: "u2", NewReviewerID: "u4"}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes %+v, want %+v", changes, want)
+	}
+	// В solo заменить некем — ревьюер остаётся
+	pr2, _ := r.PRs.GetByID(ctx, "pr-2")
+	if !slices.Equal(pr2.Reviewers, []string{"s2"}) {
+		t.Fatalf("pr-2 reviewers: %v", pr2.Reviewers)
+	}
+	history, _ := r.PRs.ListHistory(ctx, "pr-1")
+	if last := history[len(history)-1]; last.Action != models.ActionReassigned || last.Reason != "out of office" {
+		t.Fatalf("history: %+v", last)
+	}
+
+	if changes, err := svc.HandleStarted(ctx, now); err != nil || len(changes) != 0 {
+		t.Fatalf("second run must be a no-op: %+v, %v", changes, err)
+	}
+
+	// Отсутствие без замены остаётся необработанным и добирается, когда замена появилась
+	pending, err := r.Absences.ListStarted(ctx, now)
+	if err != nil || len(pending) != 1 || pending[0].UserID != "s2" {
+		t.Fatalf("unhandled absences: %+v, %v", pending, err)
+	}
+	repotest.MustUpsert(t, r, &models.User{ID: "s3", Name: "name-s3", IsActive: true, TeamName: "solo"})
+	changes, err = svc.HandleStarted(ctx, now)
+	want = []models.ReviewerChange{{PRID: "pr-2", OldReviewerID: "s2", NewReviewerID: "s3"}}
+	if err != nil || !reflect.DeepEqual(changes, want) {
+		t.Fatalf("retry: %+v, %v; want %+v", changes, err, want)
+	}
+	if pending, _ := r.Absences.ListStarted(ctx, now); len(pending) != 0 {
+		t.Fatalf("absences left after retry: %+v", pending)
+	}
+}
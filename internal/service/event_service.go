@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+	"github.com/humooo/avito-backend-trainee-2025/internal/webhook"
+)
+
+// EventTypes — события, на которые можно подписаться.
+var EventTypes = []string{models.EventPRAssigned, models.EventPRReassigned, models.EventPRMerged}
+
+// webhookEvents — под какими именами события outbox уходят подписчикам; прочие не рассылаются.
+var webhookEvents = map[string]string{
+	models.OutboxReviewerAssigned: models.EventPRAssigned,
+	models.OutboxReviewerReplaced: models.EventPRReassigned,
+	models.OutboxPRMerged:         models.EventPRMerged,
+}
+
+const (
+	// deliveryBatch — сколько доставок отправляется за один запуск
+	deliveryBatch = 50
+	// deliveryLease — на сколько взятая доставка скрыта от других экземпляров;
+	// должна быть дольше таймаута HTTP-клиента
+	deliveryLease  = time.Minute
+	maxDeliveryLog = 100
+)
+
+// RetryPolicy — сколько раз и с какими паузами повторять доставку.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy — 8 попыток с паузами от 30 секунд, в сумме около часа.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 8, BaseDelay: 30 * time.Second, MaxDelay: time.Hour}
+}
+
+// Delay — пауза после attempt неудачных попыток: BaseDelay·2^(attempt-1), не больше MaxDelay.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxDelay)
+}
+
+// EventService рассылает события о PR подписчикам исходящих вебхуков: получает
+// их из outbox (как outbox.Sink), ставит доставки в очередь и отправляет с повторами.
+type EventService struct {
+	repo   repo.WebhookRepository
+	prRepo repo.PRRepository
+	client *http.Client
+	retry  RetryPolicy
+}
+
+func NewEventService(repo repo.WebhookRepository, prRepo repo.PRRepository, client *http.Client, retry RetryPolicy) *EventService {
+	return &EventService{repo: repo, prRepo: prRepo, client: client, retry: retry}
+}
+
+// CreateSubscription подписывает url на события (пустой список — на все). Пустой
+// secret генерируется; он возвращается только здесь.
+func (s *EventService) CreateSubscription(ctx context.Context, rawURL string, events []string, secret string) (*models.WebhookSubscription, error) {
+	sub := &models.WebhookSubscription{URL: rawURL, Events: events, Secret: secret, Active: true}
+	if err := validateSubscription(sub); err != nil {
+		return nil, err
+	}
+	if sub.Secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		sub.Secret = hex.EncodeToString(b)
+	}
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *EventService) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// SubscriptionUpdate — изменяемые поля подписки; nil — не менять.
+type SubscriptionUpdate struct {
+	URL    *string
+	Events *[]string
+	Secret *string
+	Active *bool
+}
+
+func (s *EventService) UpdateSubscription(ctx context.Context, id int64, upd SubscriptionUpdate) (*models.WebhookSubscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if upd.URL != nil {
+		sub.URL = *upd.URL
+	}
+	if upd.Events != nil {
+		sub.Events = *upd.Events
+	}
+	if upd.Secret != nil {
+		if *upd.Secret == "" {
+			return nil, fmt.Errorf("secret must not be empty: %w", domain.ErrInvalidInput)
+		}
+		sub.Secret = *upd.Secret
+	}
+	if upd.Active != nil {
+		sub.Active = *upd.Active
+	}
+	if err := validateSubscription(sub); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *EventService) DeleteSubscription(ctx context.Context, id int64) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+func validateSubscription(sub *models.WebhookSubscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http(s) URL: %w", domain.ErrInvalidInput)
+	}
+	for _, e := range sub.Events {
+		if !slices.Contains(EventTypes, e) {
+			return fmt.Errorf("unknown event %q: %w", e, domain.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// eventPayload — тело исходящего вебхука.
+type eventPayload struct {
+	// id события outbox: по нему получатель отбрасывает повторы
+	EventID       int64      `json:"event_id"`
+	Event         string     `json:"event"`
+	OccurredAt    time.Time  `json:"occurred_at"`
+	Actor         string     `json:"actor"`
+	Reason        string     `json:"reason,omitempty"`
+	PullRequest   prSnapshot `json:"pull_request"`
+	OldReviewerID string     `json:"old_reviewer_id,omitempty"`
+	NewReviewerID string     `json:"new_reviewer_id,omitempty"`
+}
+
+type prSnapshot struct {
+	ID                string     `json:"pull_request_id"`
+	Name              string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	MergedAt          *time.Time `json:"merged_at"`
+}
+
+// Publish ставит событие outbox в очередь подписчикам. Доставки пишутся по id
+// события, так что повтор от relay не даёт дублей. PR в теле — его состояние на
+// момент постановки в очередь, а не сразу после изменения.
+func (s *EventService) Publish(ctx context.Context, ev models.OutboxEvent) error {
+	event, ok := webhookEvents[ev.Type]
+	if !ok {
+		return nil
+	}
+	pr, err := s.prRepo.GetByID(ctx, ev.PRID)
+	if errors.Is(err, domain.ErrNotFound) {
+		// PR удалён вместе с автором — рассылать нечего
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	payload := eventPayload{
+		EventID:    ev.ID,
+		Event:      event,
+		OccurredAt: ev.CreatedAt.UTC(),
+		Actor:      ev.Actor,
+		Reason:     ev.Reason,
+		PullRequest: prSnapshot{
+			ID:                pr.ID,
+			Name:              pr.Title,
+			AuthorID:          pr.AuthorID,
+			Status:            pr.Status,
+			AssignedReviewers: append([]string{}, pr.Reviewers...),
+			MergedAt:          pr.MergedAt,
+		},
+	}
+	switch ev.Type {
+	case models.OutboxReviewerAssigned:
+		payload.NewReviewerID = ev.ReviewerID
+	case models.OutboxReviewerReplaced:
+		payload.OldReviewerID = ev.ReviewerID
+		payload.NewReviewerID = ev.NewReviewerID
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.Enqueue(ctx, ev.ID, event, body)
+	return err
+}
+
+// DeliverDue отправляет доставки, чей срок наступил к now. Неудачная попытка
+// откладывается по RetryPolicy, после последней доставка уходит в dead letters.
+func (s *EventService) DeliverDue(ctx context.Context, now time.Time) (delivered, failed int, err error) {
+	due, err := s.repo.ClaimDue(ctx, now, now.Add(deliveryLease), deliveryBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, d := range due {
+		code, sendErr := webhook.Deliver(ctx, s.client, d)
+		if sendErr == nil {
+			if err := s.repo.MarkDelivered(ctx, d.ID, code); err != nil {
+				return delivered, failed, err
+			}
+			delivered++
+			continue
+		}
+
+		var statusCode *int
+		if code != 0 {
+			statusCode = &code
+		}
+		var next *time.Time
+		if attempts := d.Attempts + 1; attempts < s.retry.MaxAttempts {
+			at := now.Add(s.retry.Delay(attempts))
+			next = &at
+		}
+		if err := s.repo.MarkFailed(ctx, d.ID, statusCode, sendErr.Error(), next); err != nil {
+			return delivered, failed, err
+		}
+		failed++
+	}
+	return delivered, failed, nil
+}
+
+// Deliveries — журнал доставок подписки, новые первыми.
+func (s *EventService) Deliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := s.repo.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxDeliveryLog {
+		limit = maxDeliveryLog
+	}
+	return s.repo.ListDeliveries(ctx, subscriptionID, limit)
+}
+
+func (s *EventService) DeadLetters(ctx context.Context) ([]models.DeadLetter, error) {
+	return s.repo.ListDeadLetters(ctx)
+}
+
+// Redeliver возвращает доставку из dead letters в очередь; она уйдёт при следующем запуске.
+func (s *EventService) Redeliver(ctx context.Context, deadLetterID int64) error {
+	return s.repo.Redeliver(ctx, deadLetterID)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+	"github.com/humooo/avito-backend-trainee-2025/internal/webhook"
+)
+
+func TestDeliverWebhooks(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"})
+
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	var (
+		mu       sync.Mutex
+		got      []received
+		failures = 2
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, received{req.Header.Clone(), body})
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer flaky.Close()
+
+	events := NewEventService(r.Webhooks, r.PRs, receiver.Client(), RetryPolicy{MaxAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour})
+	if _, err := events.CreateSubscription(ctx, "ftp://example.com", nil, ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("bad url: want ErrInvalidInput, got %v", err)
+	}
+	if _, err := events.CreateSubscription(ctx, receiver.URL, []string{"pr.opened"}, ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("unknown event: want ErrInvalidInput, got %v", err)
+	}
+	sub, err := events.CreateSubscription(ctx, receiver.URL, nil, "")
+	if err != nil || sub.Secret == "" {
+		t.Fatalf("subscribe: %+v, %v", sub, err)
+	}
+	flakySub, err := events.CreateSubscription(ctx, flaky.URL, []string{models.EventPRMerged}, "flaky-secret")
+	if err != nil {
+		t.Fatalf("subscribe flaky: %v", err)
+	}
+
+	selector, _ := NewReviewerSelector(StrategyLeastLoaded, nil)
+	prs := NewPRService(r.PRs, r.Users, r.Teams, selector, DefaultTeamSettings(), nil)
+	relay := NewOutboxRelay(r.Outbox, models.OutboxConsumerInternal, events)
+
+	pr, err := prs.Create(ctx, "pr-1", "Search", "u1", false, "alice")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	old := pr.Reviewers[0]
+	if _, _, err := prs.Reassign(ctx, "pr-1", old, "", ""); err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	// Доставки появляются только из outbox: pr.created подписчикам не уходит,
+	// каждое назначение — отдельное pr.assigned
+	now := time.Now().Add(time.Second)
+	if delivered, _, _ := events.DeliverDue(ctx, now); delivered != 0 {
+		t.Fatalf("delivered %d before relay", delivered)
+	}
+	if _, err := relay.RelayPending(ctx); err != nil {
+		t.Fatalf("relay: %v", err)
+	}
+	if delivered, failed, err := events.DeliverDue(ctx, now); err != nil || delivered != 3 || failed != 0 {
+		t.Fatalf("deliver: %d delivered, %d failed, %v", delivered, failed, err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("receiver got %d requests, want 3", len(got))
+	}
+	for _, req := range got {
+		if sig := req.header.Get(webhook.HeaderSignature); sig != webhook.Sign(req.body, sub.Secret) {
+			t.Fatalf("signature %q does not match body", sig)
+		}
+	}
+	var payload struct {
+		EventID       int64  `json:"event_id"`
+		Event         string `json:"event"`
+		Actor         string `json:"actor"`
+		OldReviewerID string `json:"old_reviewer_id"`
+		NewReviewerID string `json:"new_reviewer_id"`
+		PullRequest   struct {
+			ID        string   `json:"pull_request_id"`
+			Status    string   `json:"status"`
+			Reviewers []string `json:"assigned_reviewers"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(got[0].body, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if got[0].header.Get(webhook.HeaderEvent) != models.EventPRAssigned || payload.Event != models.EventPRAssigned || payload.EventID == 0 ||
+		payload.Actor != "alice" || payload.PullRequest.ID != "pr-1" || !slices.Contains(pr.Reviewers, payload.NewReviewerID) {
+		t.Fatalf("assigned: %s %+v", got[0].header.Get(webhook.HeaderEvent), payload)
+	}
+	if err := json.Unmarshal(got[2].body, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.Event != models.EventPRReassigned || payload.Actor != "system" || payload.OldReviewerID != old ||
+		payload.NewReviewerID == "" || slices.Contains(payload.PullRequest.Reviewers, old) {
+		t.Fatalf("reassigned: %+v", payload)
+	}
+
+	if _, err := prs.Merge(ctx, "pr-1", ""); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	// Следующий sink не принял событие, relay повторит его — доставка не задвоится
+	failing := NewOutboxRelay(r.Outbox, models.OutboxConsumerInternal, events, &recordingSink{fail: 1})
+	if _, err := failing.RelayPending(ctx); err == nil {
+		t.Fatal("relay with failing sink must fail")
+	}
+	if _, err := relay.RelayPending(ctx); err != nil {
+		t.Fatalf("relay merge: %v", err)
+	}
+	// flaky отвечает 503: после первой попытки — пауза, после второй (последней) — dead letter
+	if delivered, failed, _ := events.DeliverDue(ctx, now); delivered != 1 || failed != 1 {
+		t.Fatalf("merge delivery: %d delivered, %d failed", delivered, failed)
+	}
+	if delivered, failed, _ := events.DeliverDue(ctx, now.Add(30*time.Second)); delivered+failed != 0 {
+		t.Fatalf("retried before backoff: %d delivered, %d failed", delivered, failed)
+	}
+	if _, failed, _ := events.DeliverDue(ctx, now.Add(2*time.Minute)); failed != 1 {
+		t.Fatalf("second attempt: %d failed, want 1", failed)
+	}
+	if delivered, failed, _ := events.DeliverDue(ctx, now.Add(time.Hour)); delivered+failed != 0 {
+		t.Fatalf("dead delivery retried: %d delivered, %d failed", delivered, failed)
+	}
+
+	log, err := events.Deliveries(ctx, flakySub.ID, 0)
+	if err != nil || len(log) != 1 {
+		t.Fatalf("flaky deliveries: %+v, %v", log, err)
+	}
+	if d := log[0]; d.Status != models.DeliveryDead || d.Attempts != 2 || d.LastStatusCode == nil || *d.LastStatusCode != 503 ||
+		!strings.Contains(d.LastError, "try later") {
+		t.Fatalf("dead delivery: %+v", d)
+	}
+	letters, _ := events.DeadLetters(ctx)
+	if len(letters) != 1 || letters[0].Event != models.EventPRMerged {
+		t.Fatalf("dead letters: %+v", letters)
+	}
+
+	// Получатель починился: событие из dead letters доходит с первой попытки
+	if err := events.Redeliver(ctx, letters[0].ID); err != nil {
+		t.Fatalf("redeliver: %v", err)
+	}
+	if delivered, _, err := events.DeliverDue(ctx, time.Now().Add(time.Second)); err != nil || delivered != 1 {
+		t.Fatalf("redelivery: %d delivered, %v", delivered, err)
+	}
+	if log, _ := events.Deliveries(ctx, flakySub.ID, 0); log[0].Status != models.DeliveryDelivered || log[0].Attempts != 1 {
+		t.Fatalf("after redelivery: %+v", log[0])
+	}
+	if _, err := events.Deliveries(ctx, 0, 0); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown subscription: want ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+)
+
+const (
+	// streamBatch — сколько событий поток читает из outbox за раз
+	streamBatch = 100
+	// streamPollInterval — опрос outbox на случай потерянных уведомлений
+	streamPollInterval = 2 * time.Second
+	// streamGapTimeout — сколько ждать событие с пропущенным id: его транзакция могла
+	// ещё не завершиться, а могла откатиться
+	streamGapTimeout = 10 * time.Second
+	// streamBuffer — очередь подписчика; кто не успевает её разбирать, отключается
+	// и догоняет по Last-Event-ID
+	streamBuffer = 256
+)
+
+// streamTypes — события, которые уходят в поток
+var streamTypes = map[string]bool{
+	models.OutboxReviewerAssigned: true,
+	models.OutboxReviewerReplaced: true,
+	models.OutboxPRMerged:         true,
+}
+
+// StreamFilter отбирает события потока; пустое поле не ограничивает.
+type StreamFilter struct {
+	// Команда автора PR
+	TeamName string
+	// Автор PR, назначенный или снятый ревьюер; для merge — любой ревьюер PR
+	UserID string
+}
+
+func (f StreamFilter) Match(ev models.FeedEvent) bool {
+	if !streamTypes[ev.Type] {
+		return false
+	}
+	if f.TeamName != "" && ev.TeamName != f.TeamName {
+		return false
+	}
+	if f.UserID == "" || ev.AuthorID == f.UserID || ev.ReviewerID == f.UserID || ev.NewReviewerID == f.UserID {
+		return true
+	}
+	return ev.Type == models.OutboxPRMerged && slices.Contains(ev.Reviewers, f.UserID)
+}
+
+// Subscription — подписка на поток. C закрывается, когда поток остановлен или
+// подписчик отстал.
+type Subscription struct {
+	C      <-chan models.FeedEvent
+	ch     chan models.FeedEvent
+	filter StreamFilter
+}
+
+// EventStream рассылает подписчикам новые события из outbox. Outbox общий для
+// всех экземпляров сервиса, так что подписчик получает и события, записанные
+// другими экземплярами.
+type EventStream struct {
+	repo repo.OutboxRepository
+
+	mu      sync.Mutex
+	subs    map[*Subscription]struct{}
+	stopped bool
+
+	// Состояние чтения, меняется только в run. События с id <= cursor уже
+	// разосланы; sent — разосланные после cursor, когда перед ними пропуск.
+	cursor   int64
+	sent     map[int64]bool
+	gapSince time.Time
+}
+
+func NewEventStream(repo repo.OutboxRepository) *EventStream {
+	return &EventStream{
+		repo: repo,
+		subs: make(map[*Subscription]struct{}),
+		sent: make(map[int64]bool),
+	}
+}
+
+// Start запоминает последнее событие outbox и в фоне рассылает всё, что записано
+// после него, пока не отменён ctx; при остановке закрывает все подписки.
+func (s *EventStream) Start(ctx context.Context) error {
+	id, err := s.repo.LastID(ctx)
+	if err != nil {
+		return err
+	}
+	s.cursor = id
+	// Слушать до возврата: иначе уведомление о сразу записанном событии потеряется
+	go s.run(ctx, s.listen(ctx))
+	return nil
+}
+
+// run читает outbox по уведомлениям и по таймеру.
+func (s *EventStream) run(ctx context.Context, notify <-chan struct{}) {
+	defer s.stop()
+
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-notify:
+			if !ok {
+				if ctx.Err() == nil {
+					log.Printf("Event stream: notifications lost, polling every %s", streamPollInterval)
+				}
+				notify = nil
+				continue
+			}
+		case <-ticker.C:
+			if notify == nil {
+				notify = s.listen(ctx)
+			}
+		}
+		if err := s.poll(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("Event stream: reading outbox failed: %v", err)
+		}
+	}
+}
+
+// listen: nil-канал при ошибке — поток работает на опросе.
+func (s *EventStream) listen(ctx context.Context) <-chan struct{} {
+	ch, err := s.repo.Notify(ctx)
+	if err != nil {
+		log.Printf("Event stream: listen failed: %v", err)
+		return nil
+	}
+	return ch
+}
+
+func (s *EventStream) poll(ctx context.Context, now time.Time) error {
+	after := s.cursor
+	for {
+		events, err := s.repo.ListAfter(ctx, after, streamBatch)
+		if err != nil {
+			return err
+		}
+		for _, ev := range events {
+			if !s.sent[ev.ID] {
+				s.sent[ev.ID] = true
+				s.skipSent()
+				ev.Cursor = s.cursor
+				s.broadcast(ev)
+			}
+		}
+		if len(events) < streamBatch {
+			break
+		}
+		after = events[len(events)-1].ID
+	}
+	s.advance(now)
+	return nil
+}
+
+// skipSent сдвигает cursor по разосланным подряд событиям.
+func (s *EventStream) skipSent() {
+	for s.sent[s.cursor+1] {
+		delete(s.sent, s.cursor+1)
+		s.cursor++
+	}
+}
+
+// advance сдвигает cursor по разосланным подряд событиям. Пропуск, который не
+// закрылся за streamGapTimeout, считается откатом.
+func (s *EventStream) advance(now time.Time) {
+	s.skipSent()
+	if len(s.sent) == 0 {
+		s.gapSince = time.Time{}
+		return
+	}
+	if s.gapSince.IsZero() {
+		s.gapSince = now
+		return
+	}
+	if now.Sub(s.gapSince) < streamGapTimeout {
+		return
+	}
+	next := int64(-1)
+	for id := range s.sent {
+		if next < 0 || id < next {
+			next = id
+		}
+	}
+	s.cursor = next - 1
+	s.gapSince = time.Time{}
+	s.advance(now)
+}
+
+func (s *EventStream) broadcast(ev models.FeedEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subs {
+		if !sub.filter.Match(ev) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			delete(s.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+func (s *EventStream) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	for sub := range s.subs {
+		delete(s.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Subscribe подписывает на новые события по фильтру. Подписку нужно снять через
+// Unsubscribe.
+func (s *EventStream) Subscribe(filter StreamFilter) *Subscription {
+	ch := make(chan models.FeedEvent, streamBuffer)
+	sub := &Subscription{C: ch, ch: ch, filter: filter}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		close(ch)
+		return sub
+	}
+	s.subs[sub] = struct{}{}
+	return sub
+}
+
+func (s *EventStream) Unsubscribe(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subs[sub]; ok {
+		delete(s.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Replay передаёт fn подходящие под фильтр события с id > afterID из outbox по
+// порядку — для продолжения потока с Last-Event-ID. Cursor, как и в живом потоке,
+// не проходит пропуск в id, пока тот моложе streamGapTimeout: событие из ещё не
+// завершённой транзакции клиент получит при следующем переподключении.
+func (s *EventStream) Replay(ctx context.Context, afterID int64, filter StreamFilter, fn func(models.FeedEvent) error) error {
+	cursor, after := afterID, afterID
+	for {
+		events, err := s.repo.ListAfter(ctx, after, streamBatch)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, ev := range events {
+			// Событие записано позже пропуска, так что пропуск старше события
+			if ev.ID == cursor+1 || now.Sub(ev.CreatedAt) >= streamGapTimeout {
+				cursor = ev.ID
+			}
+			if !filter.Match(ev) {
+				continue
+			}
+			ev.Cursor = cursor
+			if err := fn(ev); err != nil {
+				return err
+			}
+		}
+		if len(events) < streamBatch {
+			return nil
+		}
+		after = events[len(events)-1].ID
+	}
+}
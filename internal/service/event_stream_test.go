@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+)
+
+// recvEvents ждёт n событий подписки.
+func recvEvents(t *testing.T, sub *Subscription, n int) []models.FeedEvent {
+	t.Helper()
+	var events []models.FeedEvent
+	for len(events) < n {
+		select {
+		case ev, ok := <-sub.C:
+			if !ok {
+				t.Fatalf("subscription closed after %d events", len(events))
+			}
+			events = append(events, ev)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d of %d events: %+v", len(events), n, events)
+		}
+	}
+	return events
+}
+
+func feedTypes(events []models.FeedEvent) []string {
+	types := make([]string, len(events))
+	for i, ev := range events {
+		types[i] = ev.Type
+	}
+	return types
+}
+
+func TestEventStream(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+	repotest.SeedTeam(t, r, "frontend", []string{"u5", "u6", "u7", "u8"})
+	selector, _ := NewReviewerSelector(StrategyLeastLoaded, nil)
+	prs := NewPRService(r.PRs, r.Users, r.Teams, selector, DefaultTeamSettings(), nil)
+
+	// Записанное до запуска в поток не попадает, но доступно для догонки
+	if _, err := prs.Create(ctx, "pr-0", "Old", "u5", false, ""); err != nil {
+		t.Fatalf("create pr-0: %v", err)
+	}
+
+	streamCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	stream := NewEventStream(r.Outbox)
+	if err := stream.Start(streamCtx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	all := stream.Subscribe(StreamFilter{})
+	backend := stream.Subscribe(StreamFilter{TeamName: "backend"})
+	author := stream.Subscribe(StreamFilter{UserID: "u5"})
+	reviewer := stream.Subscribe(StreamFilter{UserID: "u2"})
+	unsubscribed := stream.Subscribe(StreamFilter{})
+	stream.Unsubscribe(unsubscribed)
+
+	if _, err := prs.Create(ctx, "pr-1", "Search", "u1", false, "alice"); err != nil {
+		t.Fatalf("create pr-1: %v", err)
+	}
+	pr2, err := prs.Create(ctx, "pr-2", "Layout", "u5", false, "alice")
+	if err != nil {
+		t.Fatalf("create pr-2: %v", err)
+	}
+	old := pr2.Reviewers[0]
+	_, replacement, err := prs.Reassign(ctx, "pr-2", old, "bob", "")
+	if err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	if _, err := prs.Merge(ctx, "pr-1", "carol"); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	got := recvEvents(t, all, 6)
+	want := []string{
+		models.OutboxReviewerAssigned, models.OutboxReviewerAssigned, models.OutboxReviewerAssigned,
+		models.OutboxReviewerAssigned, models.OutboxReviewerReplaced, models.OutboxPRMerged,
+	}
+	if !slices.Equal(feedTypes(got), want) {
+		t.Fatalf("all: %v, want %v", feedTypes(got), want)
+	}
+	if ev := got[4]; ev.PRID != "pr-2" || ev.ReviewerID != old || ev.NewReviewerID != replacement ||
+		ev.AuthorID != "u5" || ev.TeamName != "frontend" || !slices.Contains(ev.Reviewers, replacement) || ev.Actor != "bob" {
+		t.Fatalf("replaced: %+v", ev)
+	}
+	if ev := got[5]; ev.PRID != "pr-1" || ev.TeamName != "backend" || !slices.Equal(ev.Reviewers, []string{"u2", "u3"}) {
+		t.Fatalf("merged: %+v", ev)
+	}
+
+	for _, tt := range []struct {
+		name string
+		sub  *Subscription
+		want []string
+		pr   string
+	}{
+		{"team", backend, []string{models.OutboxReviewerAssigned, models.OutboxReviewerAssigned, models.OutboxPRMerged}, "pr-1"},
+		{"author", author, []string{models.OutboxReviewerAssigned, models.OutboxReviewerAssigned, models.OutboxReviewerReplaced}, "pr-2"},
+		{"reviewer", reviewer, []string{models.OutboxReviewerAssigned, models.OutboxPRMerged}, "pr-1"},
+	} {
+		events := recvEvents(t, tt.sub, len(tt.want))
+		if !slices.Equal(feedTypes(events), tt.want) {
+			t.Fatalf("%s: %v, want %v", tt.name, feedTypes(events), tt.want)
+		}
+		for _, ev := range events {
+			if ev.PRID != tt.pr {
+				t.Fatalf("%s: unexpected event %+v", tt.name, ev)
+			}
+		}
+	}
+	if _, ok := <-unsubscribed.C; ok {
+		t.Fatal("unsubscribed channel is still open")
+	}
+
+	// Догонка по Last-Event-ID: с начала outbox и с середины потока
+	var replayed []models.FeedEvent
+	collect := func(ev models.FeedEvent) error {
+		replayed = append(replayed, ev)
+		return nil
+	}
+	if err := stream.Replay(ctx, 0, StreamFilter{TeamName: "frontend"}, collect); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(replayed) != 5 || replayed[0].PRID != "pr-0" || replayed[4].ID != got[4].ID {
+		t.Fatalf("replay from start: %+v", replayed)
+	}
+	replayed = nil
+	if err := stream.Replay(ctx, got[3].ID, StreamFilter{}, collect); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(replayed) != 2 || replayed[0].ID != got[4].ID || replayed[1].ID != got[5].ID {
+		t.Fatalf("replay after %d: %+v", got[3].ID, replayed)
+	}
+
+	// Остановка закрывает подписки, новые подписки сразу закрыты
+	stop()
+	select {
+	case _, ok := <-all.C:
+		if ok {
+			t.Fatal("unexpected event after stop")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not closed on stop")
+	}
+	if _, ok := <-stream.Subscribe(StreamFilter{}).C; ok {
+		t.Fatal("subscription after stop is open")
+	}
+}
+
+// gappedOutbox отдаёт заданные события; пропуск в id — транзакция, которая ещё
+// не завершилась.
+type gappedOutbox struct {
+	repo.OutboxRepository
+	events []models.FeedEvent
+}
+
+func (r *gappedOutbox) ListAfter(ctx context.Context, afterID int64, limit int) ([]models.FeedEvent, error) {
+	var out []models.FeedEvent
+	for _, ev := range r.events {
+		if ev.ID > afterID && len(out) < limit {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
+
+func TestEventStreamCursorHoldsAtGap(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	feed := func(id int64, createdAt time.Time) models.FeedEvent {
+		return models.FeedEvent{OutboxEvent: models.OutboxEvent{ID: id, Type: models.OutboxPRMerged, CreatedAt: createdAt}}
+	}
+	cursors := func(events []models.FeedEvent) []int64 {
+		out := make([]int64, len(events))
+		for i, ev := range events {
+			out[i] = ev.Cursor
+		}
+		return out
+	}
+	outbox := &gappedOutbox{events: []models.FeedEvent{feed(1, now), feed(2, now), feed(4, now)}}
+	stream := NewEventStream(outbox)
+
+	// Живой поток
+	sub := stream.Subscribe(StreamFilter{})
+	if err := stream.poll(ctx, now); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if got := cursors(recvEvents(t, sub, 3)); !slices.Equal(got, []int64{1, 2, 2}) {
+		t.Fatalf("live cursors %v, want [1 2 2]", got)
+	}
+
+	// Догонка не проходит свежий пропуск
+	var replayed []models.FeedEvent
+	collect := func(ev models.FeedEvent) error {
+		replayed = append(replayed, ev)
+		return nil
+	}
+	if err := stream.Replay(ctx, 0, StreamFilter{}, collect); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if got := cursors(replayed); !slices.Equal(got, []int64{1, 2, 2}) {
+		t.Fatalf("replay cursors %v, want [1 2 2]", got)
+	}
+
+	// Завершившаяся транзакция закрывает пропуск
+	outbox.events = []models.FeedEvent{feed(1, now), feed(2, now), feed(3, now), feed(4, now)}
+	if err := stream.poll(ctx, now); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if ev := recvEvents(t, sub, 1)[0]; ev.ID != 3 || ev.Cursor != 4 {
+		t.Fatalf("late event: id %d, cursor %d", ev.ID, ev.Cursor)
+	}
+	replayed = nil
+	if err := stream.Replay(ctx, 2, StreamFilter{}, collect); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if got := cursors(replayed); !slices.Equal(got, []int64{3, 4}) {
+		t.Fatalf("replay after gap closed: %v", got)
+	}
+
+	// Пропуск старше streamGapTimeout считается откатом
+	outbox.events = []models.FeedEvent{feed(1, now), feed(3, now.Add(-time.Minute))}
+	replayed = nil
+	if err := stream.Replay(ctx, 0, StreamFilter{}, collect); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if got := cursors(replayed); !slices.Equal(got, []int64{1, 3}) {
+		t.Fatalf("replay over rolled back id: %v", got)
+	}
+}
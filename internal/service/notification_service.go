@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/notify"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+)
+
+const (
+	// notifyBatch — сколько мгновенных уведомлений отправляется за раз
+	notifyBatch = 100
+	// notifyMaxAttempts — после стольких неудачных отправок уведомление выбрасывается
+	notifyMaxAttempts = 5
+)
+
+// NotificationService сообщает ревьюерам в чат о назначениях: сразу, дайджестом
+// или никак — по настройкам пользователя.
+type NotificationService struct {
+	repo     repo.NotificationRepository
+	userRepo repo.UserRepository
+	teamRepo repo.TeamRepository
+	prRepo   repo.PRRepository
+	notifier notify.Notifier
+	// digestEvery — период дайджеста
+	digestEvery time.Duration
+}
+
+// NewNotificationService: notifier nil — уведомления не копятся и не отправляются,
+// но настройки пользователей хранятся.
+func NewNotificationService(repo repo.NotificationRepository, userRepo repo.UserRepository, teamRepo repo.TeamRepository,
+	prRepo repo.PRRepository, notifier notify.Notifier, digestEvery time.Duration) *NotificationService {
+	return &NotificationService{
+		repo:        repo,
+		userRepo:    userRepo,
+		teamRepo:    teamRepo,
+		prRepo:      prRepo,
+		notifier:    notifier,
+		digestEvery: digestEvery,
+	}
+}
+
+func (s *NotificationService) Prefs(ctx context.Context, userID string) (*models.NotificationPrefs, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPrefs(ctx, userID)
+}
+
+func (s *NotificationService) SetPrefs(ctx context.Context, p *models.NotificationPrefs) error {
+	switch p.Mode {
+	case models.NotifyInstant, models.NotifyDigest, models.NotifyMute:
+	default:
+		return fmt.Errorf("unknown notification mode %q: %w", p.Mode, domain.ErrInvalidInput)
+	}
+	if _, err := s.userRepo.GetByID(ctx, p.UserID); err != nil {
+		return err
+	}
+	return s.repo.SetPrefs(ctx, p)
+}
+
+// Publish — sink outbox: ставит в очередь сообщение назначенному ревьюеру или
+// пришедшему на замену. Ошибка возвращается relay, и он повторит событие; повтор
+// уведомление не задвоит.
+func (s *NotificationService) Publish(ctx context.Context, ev models.OutboxEvent) error {
+	if s.notifier == nil {
+		return nil
+	}
+	reviewerID := ev.ReviewerID
+	switch ev.Type {
+	case models.OutboxReviewerAssigned:
+	case models.OutboxReviewerReplaced:
+		reviewerID = ev.NewReviewerID
+	default:
+		return nil
+	}
+	if reviewerID == "" {
+		return nil
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, ev.PRID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	n, err := s.notification(ctx, reviewerID, pr, ev)
+	if err == nil && n != nil {
+		err = s.repo.Enqueue(ctx, ev.ID, []models.Notification{*n})
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		// Ревьюера уже нет — повтор не поможет
+		return nil
+	}
+	return err
+}
+
+// notification — сообщение ревьюеру; nil, если он отключил уведомления.
+// Без личного канала сообщение уходит в канал команды ревьюера.
+func (s *NotificationService) notification(ctx context.Context, reviewerID string, pr *models.PullRequest, ev models.OutboxEvent) (*models.Notification, error) {
+	prefs, err := s.repo.GetPrefs(ctx, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs.Mode == models.NotifyMute {
+		return nil, nil
+	}
+	reviewer, err := s.userRepo.GetByID(ctx, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	channel := prefs.Channel
+	if channel == "" && reviewer.TeamName != "" {
+		ts, err := s.teamRepo.GetSettings(ctx, reviewer.TeamName)
+		switch {
+		case err == nil:
+			channel = ts.ChatChannel
+		case !errors.Is(err, domain.ErrNotFound):
+			return nil, err
+		}
+	}
+
+	text := fmt.Sprintf("%s, you are assigned to review %s %q by %s", reviewer.Name, pr.ID, pr.Title, s.userName(ctx, pr.AuthorID))
+	if ev.Type == models.OutboxReviewerReplaced {
+		text += " instead of " + s.userName(ctx, ev.ReviewerID)
+	}
+	return &models.Notification{
+		UserID:  reviewerID,
+		Channel: channel,
+		Text:    text + ".",
+		Digest:  prefs.Mode == models.NotifyDigest,
+	}, nil
+}
+
+// userName — имя пользователя для текста; если его не найти, id.
+func (s *NotificationService) userName(ctx context.Context, id string) string {
+	if u, err := s.userRepo.GetByID(ctx, id); err == nil && u.Name != "" {
+		return u.Name
+	}
+	return id
+}
+
+// SendPending отправляет мгновенные уведомления и дайджесты, период которых
+// прошёл к now.
+func (s *NotificationService) SendPending(ctx context.Context, now time.Time) (sent, failed int, err error) {
+	if s.notifier == nil {
+		return 0, 0, nil
+	}
+	return s.repo.SendPending(ctx, now.Add(-s.digestEvery), notifyBatch, notifyMaxAttempts, s.send)
+}
+
+func (s *NotificationService) send(ctx context.Context, items []models.Notification) error {
+	last := items[len(items)-1]
+	if !last.Digest {
+		return s.notifier.Notify(ctx, notify.Message{Channel: last.Channel, Text: last.Text})
+	}
+	texts := make([]string, len(items))
+	for i, n := range items {
+		texts[i] = n.Text
+	}
+	text := fmt.Sprintf("Review digest (%d):\n• %s", len(items), strings.Join(texts, "\n• "))
+	return s.notifier.Notify(ctx, notify.Message{Channel: last.Channel, Text: text})
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/notify"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+)
+
+// chatStub — Slack-совместимый incoming webhook, запоминающий сообщения.
+type chatStub struct {
+	mu       sync.Mutex
+	messages []notify.Message
+	status   int
+}
+
+func (c *chatStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var msg notify.Message
+	var payload struct{ Channel, Text string }
+	if err := json.NewDecoder(r.Body).Decode(&payload); err == nil {
+		msg = notify.Message{Channel: payload.Channel, Text: payload.Text}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.status != 0 {
+		w.WriteHeader(c.status)
+		return
+	}
+	c.messages = append(c.messages, msg)
+}
+
+// take возвращает полученные сообщения и очищает список.
+func (c *chatStub) take() []notify.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	msgs := c.messages
+	c.messages = nil
+	return msgs
+}
+
+func (c *chatStub) fail(status int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = status
+}
+
+func TestNotifyReviewers(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+	settings := DefaultTeamSettings()
+	settings.TeamName, settings.ChatChannel = "backend", "#backend"
+	if err := r.Teams.UpsertSettings(ctx, &settings); err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+
+	chat := &chatStub{}
+	srv := httptest.NewServer(chat)
+	defer srv.Close()
+	notifier, err := notify.NewSlackNotifier(srv.URL, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewNotificationService(r.Notifications, r.Users, r.Teams, r.PRs, notifier, time.Hour)
+	selector, _ := NewReviewerSelector(StrategyLeastLoaded, nil)
+	prs := NewPRService(r.PRs, r.Users, r.Teams, selector, DefaultTeamSettings(), nil)
+	relay := NewOutboxRelay(r.Outbox, models.OutboxConsumerInternal, svc)
+
+	setPrefs := func(userID, mode, channel string) {
+		t.Helper()
+		if err := svc.SetPrefs(ctx, &models.NotificationPrefs{UserID: userID, Mode: mode, Channel: channel}); err != nil {
+			t.Fatalf("prefs of %s: %v", userID, err)
+		}
+	}
+	// Уведомления ставятся в очередь из outbox
+	sendPending := func(now time.Time, wantSent, wantFailed int) []notify.Message {
+		t.Helper()
+		if _, err := relay.RelayPending(ctx); err != nil {
+			t.Fatalf("relay: %v", err)
+		}
+		sent, failed, err := svc.SendPending(ctx, now)
+		if err != nil || sent != wantSent || failed != wantFailed {
+			t.Fatalf("send: %d sent, %d failed, %v; want %d, %d", sent, failed, err, wantSent, wantFailed)
+		}
+		return chat.take()
+	}
+	reassign := func(old, wantNew string) {
+		t.Helper()
+		if _, newID, err := prs.Reassign(ctx, "pr-1", old, "", ""); err != nil || newID != wantNew {
+			t.Fatalf("reassign %s: got %s, %v; want %s", old, newID, err, wantNew)
+		}
+	}
+
+	if p, err := svc.Prefs(ctx, "u3"); err != nil || p.Mode != models.NotifyInstant || p.Channel != "" {
+		t.Fatalf("default prefs: %+v, %v", p, err)
+	}
+	if err := svc.SetPrefs(ctx, &models.NotificationPrefs{UserID: "u2", Mode: "loud"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("unknown mode: want ErrInvalidInput, got %v", err)
+	}
+	if _, err := svc.Prefs(ctx, "ghost"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown user: want ErrNotFound, got %v", err)
+	}
+	setPrefs("u2", models.NotifyInstant, "@bob")
+	if p, err := svc.Prefs(ctx, "u2"); err != nil || p.Channel != "@bob" {
+		t.Fatalf("saved prefs: %+v, %v", p, err)
+	}
+
+	// Создание: каждому ревьюеру сразу — в личку или в канал команды
+	if _, err := prs.Create(ctx, "pr-1", "Search", "u1", false, ""); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	now := time.Now()
+	msgs := sendPending(now, 2, 0)
+	want := map[string]string{
+		"@bob":     `name-u2, you are assigned to review pr-1 "Search" by name-u1.`,
+		"#backend": `name-u3, you are assigned to review pr-1 "Search" by name-u1.`,
+	}
+	for _, m := range msgs {
+		if want[m.Channel] != m.Text {
+			t.Fatalf("unexpected message %+v", m)
+		}
+	}
+	sendPending(now, 0, 0)
+
+	// Relay повторяет событие, которое не принял следующий sink, — уведомление не задваивается
+	repotest.MustUpsert(t, r, &models.User{ID: "u4", Name: "name-u4", IsActive: true, TeamName: "backend"})
+	if _, _, err := prs.Reassign(ctx, "pr-1", "u2", "", ""); err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	if _, err := NewOutboxRelay(r.Outbox, models.OutboxConsumerInternal, svc, &recordingSink{fail: 1}).RelayPending(ctx); err == nil {
+		t.Fatal("relay with failing sink must fail")
+	}
+	if msgs := sendPending(now, 1, 0); len(msgs) != 1 || msgs[0].Channel != "#backend" {
+		t.Fatalf("repeated event: %+v", msgs)
+	}
+	if _, _, err := prs.Reassign(ctx, "pr-1", "u4", "", ""); err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	sendPending(now, 1, 0)
+
+	// Замена: digest копится, mute не получает ничего
+	setPrefs("u4", models.NotifyDigest, "")
+	setPrefs("u3", models.NotifyMute, "")
+	reassign("u2", "u4")
+	reassign("u3", "u2")
+	reassign("u4", "u3")
+	msgs = sendPending(now, 1, 0)
+	if len(msgs) != 1 || msgs[0].Channel != "@bob" || !strings.HasSuffix(msgs[0].Text, "by name-u1 instead of name-u3.") {
+		t.Fatalf("reassign messages: %+v", msgs)
+	}
+	reassign("u2", "u4")
+	msgs = sendPending(now.Add(2*time.Hour), 1, 0)
+	if len(msgs) != 1 || msgs[0].Channel != "#backend" || !strings.HasPrefix(msgs[0].Text, "Review digest (2):\n• name-u4, ") ||
+		!strings.HasSuffix(msgs[0].Text, "instead of name-u2.") {
+		t.Fatalf("digest: %+v", msgs)
+	}
+	sendPending(now.Add(2*time.Hour), 0, 0)
+
+	// Неотправленное повторяется, после notifyMaxAttempts выбрасывается
+	chat.fail(http.StatusInternalServerError)
+	reassign("u4", "u2")
+	sendPending(now, 0, 1)
+	chat.fail(0)
+	if msgs := sendPending(now, 1, 0); len(msgs) != 1 || msgs[0].Channel != "@bob" {
+		t.Fatalf("retry: %+v", msgs)
+	}
+	chat.fail(http.StatusNotFound)
+	reassign("u2", "u4")
+	reassign("u3", "u2")
+	for range 5 {
+		sendPending(now.Add(2*time.Hour), 0, 2)
+	}
+	chat.fail(0)
+	sendPending(now.Add(2*time.Hour), 0, 0)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/outbox"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+)
+
+// outboxBatch — сколько событий relay берёт из outbox за раз
+const outboxBatch = 100
+
+// OutboxRelay публикует доменные события из outbox во все sinks одного получателя
+// (models.OutboxConsumer*). У каждого получателя своя отметка публикации, так что
+// relay внешних sinks не задерживает relay внутренних.
+type OutboxRelay struct {
+	repo     repo.OutboxRepository
+	consumer string
+	sinks    []outbox.Sink
+}
+
+func NewOutboxRelay(repo repo.OutboxRepository, consumer string, sinks ...outbox.Sink) *OutboxRelay {
+	return &OutboxRelay{repo: repo, consumer: consumer, sinks: sinks}
+}
+
+// RelayPending публикует накопившиеся события по порядку. Событие опубликовано, когда
+// его приняли все sinks; на ошибке relay останавливается и при следующем запуске
+// начнёт с этого же события, так что sinks, уже принявшие его, получат его снова.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := r.repo.PublishPending(ctx, r.consumer, outboxBatch, r.publish)
+		total += n
+		if err != nil || n < outboxBatch {
+			return total, err
+		}
+	}
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, events []models.OutboxEvent) (int, error) {
+	for i, ev := range events {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		for _, sink := range r.sinks {
+			if err := sink.Publish(ctx, ev); err != nil {
+				return i, err
+			}
+		}
+	}
+	return len(events), nil
+}
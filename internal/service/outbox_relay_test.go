@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+)
+
+// recordingSink запоминает события; первые fail вызовов завершаются ошибкой.
+type recordingSink struct {
+	fail   int
+	events []models.OutboxEvent
+}
+
+func (s *recordingSink) Publish(ctx context.Context, ev models.OutboxEvent) error {
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("sink unavailable")
+	}
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func TestOutboxRelay(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+	repotest.MustCreatePR(t, r, "pr-1", "u1", "u2")
+	if err := r.PRs.Merge(ctx, "pr-1", repotest.TestAudit); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	// Relay останавливается на событии, которое не принял один из sinks, и повторяет его
+	logged := &recordingSink{}
+	flaky := &recordingSink{fail: 1}
+	relay := NewOutboxRelay(r.Outbox, models.OutboxConsumerInternal, logged, flaky)
+	if n, err := relay.RelayPending(ctx); n != 0 || err == nil {
+		t.Fatalf("relay with failing sink: %d, %v", n, err)
+	}
+	if n, err := relay.RelayPending(ctx); n != 3 || err != nil {
+		t.Fatalf("relay: %d, %v", n, err)
+	}
+	if len(flaky.events) != 3 || len(logged.events) != 4 || logged.events[0].ID != logged.events[1].ID {
+		t.Fatalf("sinks got %d and %d events", len(logged.events), len(flaky.events))
+	}
+	var types []string
+	for _, ev := range flaky.events {
+		types = append(types, ev.Type)
+	}
+	if want := []string{models.OutboxPRCreated, models.OutboxReviewerAssigned, models.OutboxPRMerged}; !slices.Equal(types, want) {
+		t.Fatalf("relayed %v, want %v", types, want)
+	}
+	if n, err := relay.RelayPending(ctx); n != 0 || err != nil {
+		t.Fatalf("nothing left: %d, %v", n, err)
+	}
+
+	// Недоступный внешний sink не задерживает внутренних получателей
+	down := &recordingSink{fail: 100}
+	external := NewOutboxRelay(r.Outbox, models.OutboxConsumerExternal, down)
+	repotest.MustCreatePR(t, r, "pr-2", "u1")
+	if _, err := external.RelayPending(ctx); err == nil {
+		t.Fatal("relay with unavailable sink must fail")
+	}
+	if n, err := relay.RelayPending(ctx); n != 1 || err != nil {
+		t.Fatalf("internal relay behind failing external: %d, %v", n, err)
+	}
+	down.fail = 0
+	if n, err := external.RelayPending(ctx); n != 4 || err != nil || len(down.events) != 4 {
+		t.Fatalf("external relay after recovery: %d, %v", n, err)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+// Размер страницы списка PR.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// List возвращает страницу PR по фильтру и курсор следующей ("" — страниц больше нет).
+// cursor — значение, выданное предыдущим вызовом с тем же фильтром.
+func (s *PRService) List(ctx context.Context, filter models.PRFilter, cursor string) ([]*models.PullRequest, string, error) {
+	switch filter.Status {
+	case "", models.StatusDraft, models.StatusOpen, models.StatusMerged, models.StatusClosed:
+	default:
+		return nil, "", fmt.Errorf("unknown status %q: %w", filter.Status, domain.ErrInvalidInput)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit < 0 || filter.Limit > maxPageSize {
+		return nil, "", fmt.Errorf("limit must be in 1..%d: %w", maxPageSize, domain.ErrInvalidInput)
+	}
+	if cursor != "" {
+		after, err := decodePRCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		filter.After = after
+	}
+
+	// Лишний PR показывает, есть ли следующая страница
+	limit := filter.Limit
+	filter.Limit++
+	prs, err := s.prRepo.List(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(prs) <= limit {
+		return prs, "", nil
+	}
+	prs = prs[:limit]
+	last := prs[limit-1]
+	return prs, encodePRCursor(models.PRCursor{CreatedAt: last.CreatedAt, ID: last.ID}), nil
+}
+
+// Курсор непрозрачен для клиента: base64 от "created_at|id".
+func encodePRCursor(c models.PRCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+func decodePRCursor(s string) (*models.PRCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", domain.ErrInvalidInput)
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, fmt.Errorf("invalid cursor: %w", domain.ErrInvalidInput)
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", domain.ErrInvalidInput)
+	}
+	return &models.PRCursor{CreatedAt: createdAt, ID: id}, nil
+}
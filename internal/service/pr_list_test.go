@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+)
+
+func TestListPRs(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2"})
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		repotest.MustCreatePR(t, r, id, "u1", "u2")
+	}
+
+	selector, _ := NewReviewerSelector(StrategyLeastLoaded, nil)
+	svc := NewPRService(r.PRs, r.Users, r.Teams, selector, DefaultTeamSettings(), nil)
+
+	var got []string
+	cursor := ""
+	for page := 0; ; page++ {
+		prs, next, err := svc.List(ctx, models.PRFilter{Limit: 2}, cursor)
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		got = append(got, prIDs(prs)...)
+		if next == "" {
+			break
+		}
+		if page > 2 {
+			t.Fatalf("pagination does not stop: %v", got)
+		}
+		cursor = next
+	}
+	if !slices.Equal(got, []string{"pr-3", "pr-2", "pr-1"}) {
+		t.Fatalf("pages: %v", got)
+	}
+
+	// Ровно limit PR — следующей страницы нет
+	if prs, next, err := svc.List(ctx, models.PRFilter{Limit: 3}, ""); err != nil || len(prs) != 3 || next != "" {
+		t.Fatalf("exact page: %v, %q, %v", prIDs(prs), next, err)
+	}
+	for name, call := range map[string]func() error{
+		"cursor": func() error { _, _, err := svc.List(ctx, models.PRFilter{}, "not a cursor"); return err },
+		"status": func() error { _, _, err := svc.List(ctx, models.PRFilter{Status: "WIP"}, ""); return err },
+		"limit":  func() error { _, _, err := svc.List(ctx, models.PRFilter{Limit: 1000}, ""); return err },
+	} {
+		if err := call(); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("bad %s: want ErrInvalidInput, got %v", name, err)
+		}
+	}
+}
@@ -2,72 +2,288 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"slices"
 	"time"
 
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
 	"github.com/humooo/avito-backend-trainee-2025/internal/models"
 	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
 )
 
+const systemActor = "system"
+
 type PRService struct {
 	prRepo   repo.PRRepository
 	userRepo repo.UserRepository
 	teamRepo repo.TeamRepository
+	// selector — стратегия по умолчанию, selectors — заданные в настройках команд.
+	// Экземпляры общие, чтобы round robin помнил позицию между запросами.
+	selector  ReviewerSelector
+	selectors map[string]ReviewerSelector
+	defaults  models.TeamSettings
+}
+
+// NewPRService: now — часы для стратегий команд (working_hours), nil — time.Now.
+func NewPRService(prRepo repo.PRRepository, userRepo repo.UserRepository, teamRepo repo.TeamRepository, selector ReviewerSelector, defaults models.TeamSettings, now func() time.Time) *PRService {
+	return &PRService{
+		prRepo:   prRepo,
+		userRepo: userRepo,
+		teamRepo: teamRepo,
+		selector: selector,
+		selectors: map[string]ReviewerSelector{
+			StrategyRandom:       &RandomSelector{},
+			StrategyRoundRobin:   NewRoundRobinSelector(),
+			StrategyLeastLoaded:  &LeastLoadedSelector{},
+			StrategyWorkingHours: NewWorkingHoursSelector(now, nil),
+		},
+		defaults: defaults,
+	}
+}
+
+// transitions — допустимые переходы статусов PR. MERGED конечный.
+var transitions = map[string][]string{
+	models.StatusDraft:  {models.StatusOpen, models.StatusClosed},
+	models.StatusOpen:   {models.StatusMerged, models.StatusClosed},
+	models.StatusClosed: {models.StatusOpen},
 }
 
-func NewPRService(prRepo repo.PRRepository, userRepo repo.UserRepository, teamRepo repo.TeamRepository) *PRService {
-	return &PRService{prRepo: prRepo, userRepo: userRepo, teamRepo: teamRepo}
+func checkTransition(pr *models.PullRequest, to string) error {
+	if slices.Contains(transitions[pr.Status], to) {
+		return nil
+	}
+	if pr.Status == models.StatusMerged {
+		return fmt.Errorf("pr %s: %w", pr.ID, domain.ErrPRMerged)
+	}
+	return fmt.Errorf("pr %s: %s -> %s: %w", pr.ID, pr.Status, to, domain.ErrInvalidState)
 }
 
-func (s *PRService) Create(ctx context.Context, id, title, authorID string) (*models.PullRequest, error) {
+// Create создаёт PR. Черновик (draft) создаётся без ревьюеров — они назначаются в MarkReady.
+// actor — кто инициировал операцию, пишется в историю назначений (пусто — system).
+func (s *PRService) Create(ctx context.Context, id, title, authorID string, draft bool, actor string) (*models.PullRequest, error) {
 	author, err := s.userRepo.GetByID(ctx, authorID)
-	if err != nil || author == nil {
-		return nil, fmt.Errorf("author not found")
+	if err != nil {
+		return nil, fmt.Errorf("author: %w", err)
 	}
 
 	pr := &models.PullRequest{
 		ID:       id,
 		Title:    title,
 		AuthorID: authorID,
-		Status:   "OPEN",
+		Status:   models.StatusOpen,
 	}
 
-	if err := s.prRepo.CreateWithReviewers(ctx, pr); err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "duplicate") {
-			return nil, fmt.Errorf("pr exists")
+	settings, err := effectiveSettings(ctx, s.teamRepo, author.TeamName, s.defaults)
+	if err != nil {
+		return nil, err
+	}
+	var capped bool
+	if draft {
+		pr.Status = models.StatusDraft
+	} else if capped, err = s.selectReviewers(ctx, pr, settings); err != nil {
+		return nil, err
+	}
+
+	if err := s.prRepo.CreateWithReviewers(ctx, pr, audit(actor, "auto-assigned on create")); err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return nil, fmt.Errorf("pr %s: %w", id, domain.ErrPRExists)
 		}
 		return nil, err
 	}
+
+	if !draft {
+		pr.ReviewerShortage = reviewerShortage(pr, capped, settings.ReviewerCount)
+	}
 	return pr, nil
 }
 
-func (s *PRService) Merge(ctx context.Context, prID string) (*models.PullRequest, error) {
+// MarkReady переводит черновик в OPEN и назначает ревьюеров так же, как Create.
+func (s *PRService) MarkReady(ctx context.Context, prID, actor string) (*models.PullRequest, error) {
+	return s.open(ctx, prID, models.StatusDraft, audit(actor, "auto-assigned when ready for review"))
+}
+
+// Reopen возвращает закрытый PR в OPEN со свежим назначением ревьюеров.
+func (s *PRService) Reopen(ctx context.Context, prID, actor string) (*models.PullRequest, error) {
+	return s.open(ctx, prID, models.StatusClosed, audit(actor, "auto-assigned on reopen"))
+}
+
+func (s *PRService) open(ctx context.Context, prID, from string, a models.Audit) (*models.PullRequest, error) {
 	pr, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
-	if pr.Status == "MERGED" {
+	if pr.Status == models.StatusMerged {
+		return nil, fmt.Errorf("pr %s: %w", prID, domain.ErrPRMerged)
+	}
+	if pr.Status != from {
+		return nil, fmt.Errorf("pr %s is %s, not %s: %w", prID, pr.Status, from, domain.ErrInvalidState)
+	}
+
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, fmt.Errorf("author: %w", err)
+	}
+	settings, err := effectiveSettings(ctx, s.teamRepo, author.TeamName, s.defaults)
+	if err != nil {
+		return nil, err
+	}
+	capped, err := s.selectReviewers(ctx, pr, settings)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.prRepo.OpenWithReviewers(ctx, pr, from, a); err != nil {
+		return nil, err
+	}
+	pr.ReviewerShortage = reviewerShortage(pr, capped, settings.ReviewerCount)
+	return pr, nil
+}
+
+// selectReviewers выбирает ревьюеров в pr.Reviewers по настройкам команды автора.
+// capped — часть кандидатов отсеяна из-за лимита открытых ревью.
+func (s *PRService) selectReviewers(ctx context.Context, pr *models.PullRequest, settings *models.TeamSettings) (capped bool, err error) {
+	candidates, err := s.prRepo.FindCandidates(ctx, settings.TeamName, []string{pr.AuthorID})
+	if err != nil {
+		return false, err
+	}
+	available := underCapacity(candidates)
+	capped = len(available) < len(candidates)
+
+	var reviewers []string
+	if settings.RequireLead && settings.ReviewerCount > 0 {
+		isLead := func(c models.ReviewCandidate) bool { return c.UserID == settings.LeadUserID }
+		if i := slices.IndexFunc(available, isLead); i >= 0 {
+			reviewers = append(reviewers, settings.LeadUserID)
+			available = slices.Delete(slices.Clone(available), i, i+1)
+		}
+	}
+	selector := s.selectorFor(settings)
+	reviewers = append(reviewers, selector.Select(settings.TeamName, available, settings.ReviewerCount-len(reviewers))...)
+
+	// Недостающих добираем из запасных команд по порядку
+	pr.Fallback = nil
+	for _, team := range settings.FallbackTeams {
+		if len(reviewers) >= settings.ReviewerCount {
+			break
+		}
+		candidates, err := s.prRepo.FindCandidates(ctx, team, append([]string{pr.AuthorID}, reviewers...))
+		if err != nil {
+			return false, err
+		}
+		available := underCapacity(candidates)
+		capped = capped || len(available) < len(candidates)
+
+		for _, id := range selector.Select(team, available, settings.ReviewerCount-len(reviewers)) {
+			if pr.Fallback == nil {
+				pr.Fallback = make(map[string]bool)
+			}
+			pr.Fallback[id] = true
+			reviewers = append(reviewers, id)
+		}
+	}
+
+	pr.Reviewers = reviewers
+	return capped, nil
+}
+
+func (s *PRService) selectorFor(settings *models.TeamSettings) ReviewerSelector {
+	if sel, ok := s.selectors[settings.Strategy]; ok {
+		return sel
+	}
+	return s.selector
+}
+
+// reviewerShortage — почему после записи ревьюеров меньше нужного (пусто — хватило).
+// capped — лимит отсеял кандидатов при выборе; при записи репозиторий сам отмечает
+// в pr.ReviewerShortage тех, кто упёрся в лимит уже после выбора.
+func reviewerShortage(pr *models.PullRequest, capped bool, want int) string {
+	switch {
+	case len(pr.Reviewers) >= want:
+		return ""
+	case capped || pr.ReviewerShortage == models.ShortageAtCapacity:
+		return models.ShortageAtCapacity
+	default:
+		return models.ShortageNotEnoughTeammates
+	}
+}
+
+func (s *PRService) Merge(ctx context.Context, prID, actor string) (*models.PullRequest, error) {
+	return s.merge(ctx, prID, audit(actor, "pr merged"), true)
+}
+
+// RecordMerge отмечает PR, уже смерженный на платформе: отменить это нельзя,
+// поэтому min_approvals не проверяется.
+func (s *PRService) RecordMerge(ctx context.Context, prID, actor, reason string) (*models.PullRequest, error) {
+	return s.merge(ctx, prID, audit(actor, reason), false)
+}
+
+func (s *PRService) merge(ctx context.Context, prID string, a models.Audit, checkApprovals bool) (*models.PullRequest, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status == models.StatusMerged {
 		return pr, nil
 	}
-	if err := s.prRepo.Merge(ctx, prID); err != nil {
+	if err := checkTransition(pr, models.StatusMerged); err != nil {
+		return nil, err
+	}
+	if checkApprovals {
+		author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+		if err != nil {
+			return nil, fmt.Errorf("author: %w", err)
+		}
+		settings, err := effectiveSettings(ctx, s.teamRepo, author.TeamName, s.defaults)
+		if err != nil {
+			return nil, err
+		}
+		if got := pr.Approvals(); got < settings.MinApprovals {
+			return nil, fmt.Errorf("pr %s: %d of %d: %w", prID, got, settings.MinApprovals, domain.ErrNotApproved)
+		}
+	}
+	if err := s.prRepo.Merge(ctx, prID, a); err != nil {
 		return nil, err
 	}
 
 	now := time.Now()
-	pr.Status = "MERGED"
+	pr.Status = models.StatusMerged
 	pr.MergedAt = &now
+	return pr, nil
+}
+
+// Close закрывает PR без merge и снимает ревьюеров. Повторный close ничего не меняет.
+func (s *PRService) Close(ctx context.Context, prID, actor string) (*models.PullRequest, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status == models.StatusClosed {
+		return pr, nil
+	}
+	if err := checkTransition(pr, models.StatusClosed); err != nil {
+		return nil, err
+	}
+	if err := s.prRepo.Close(ctx, prID, audit(actor, "pr closed")); err != nil {
+		return nil, err
+	}
 
+	pr.Status = models.StatusClosed
+	pr.Reviewers = nil
+	pr.ReviewStates = nil
 	return pr, nil
 }
 
-func (s *PRService) Reassign(ctx context.Context, prID, oldReviewerID string) (*models.PullRequest, string, error) {
+// Reassign заменяет ревьюера участником команды PR, выбранным стратегией этой команды.
+func (s *PRService) Reassign(ctx context.Context, prID, oldReviewerID, actor, reason string) (*models.PullRequest, string, error) {
 	pr, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
-		return nil, "", fmt.Errorf("pr not found")
+		return nil, "", err
 	}
-	if pr.Status == "MERGED" {
-		return nil, "", fmt.Errorf("pr merged")
+	if pr.Status == models.StatusMerged {
+		return nil, "", fmt.Errorf("pr %s: %w", prID, domain.ErrPRMerged)
+	}
+	if pr.Status != models.StatusOpen {
+		return nil, "", fmt.Errorf("pr %s is %s: %w", prID, pr.Status, domain.ErrInvalidState)
 	}
 
 	isAssigned := false
@@ -78,23 +294,21 @@ func (s *PRService) Reassign(ctx context.Context, prID, oldReviewerID string) (*
 		}
 	}
 	if !isAssigned {
-		return nil, "", fmt.Errorf("reviewer not assigned")
-	}
-
-	oldUser, err := s.userRepo.GetByID(ctx, oldReviewerID)
-	if err != nil || oldUser == nil {
-		return nil, "", fmt.Errorf("old reviewer not found")
+		return nil, "", fmt.Errorf("user %s: %w", oldReviewerID, domain.ErrNotAssigned)
 	}
 
-	newID, err := s.prRepo.FindCandidateForReassign(ctx, oldUser.TeamName, oldReviewerID, pr.AuthorID, pr.Reviewers)
+	newID, err := s.pickForPR(ctx, pr, oldReviewerID)
 	if err != nil {
 		return nil, "", err
 	}
 	if newID == "" {
-		return nil, "", fmt.Errorf("no candidates")
+		return nil, "", domain.ErrNoCandidate
 	}
 
-	if err := s.prRepo.ReplaceReviewer(ctx, prID, oldReviewerID, newID); err != nil {
+	if reason == "" {
+		reason = "manual reassign"
+	}
+	if err := s.prRepo.ReplaceReviewer(ctx, prID, oldReviewerID, newID, audit(actor, reason)); err != nil {
 		return nil, "", err
 	}
 
@@ -104,6 +318,251 @@ func (s *PRService) Reassign(ctx context.Context, prID, oldReviewerID string) (*
 			break
 		}
 	}
-
+	delete(pr.ReviewStates, oldReviewerID)
+	delete(pr.AssignedAt, oldReviewerID)
+	delete(pr.Escalated, oldReviewerID)
 	return pr, newID, nil
 }
+
+// Review записывает решение назначенного ревьюера по OPEN PR.
+func (s *PRService) Review(ctx context.Context, prID, reviewerID, state string) (*models.PullRequest, error) {
+	switch state {
+	case models.ReviewApproved, models.ReviewChangesRequested, models.ReviewCommented:
+	default:
+		return nil, fmt.Errorf("review state %q: %w", state, domain.ErrInvalidInput)
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status == models.StatusMerged {
+		return nil, fmt.Errorf("pr %s: %w", prID, domain.ErrPRMerged)
+	}
+	if pr.Status != models.StatusOpen {
+		return nil, fmt.Errorf("pr %s is %s: %w", prID, pr.Status, domain.ErrInvalidState)
+	}
+	if !slices.Contains(pr.Reviewers, reviewerID) {
+		return nil, fmt.Errorf("user %s: %w", reviewerID, domain.ErrNotAssigned)
+	}
+
+	if err := s.prRepo.SetReviewState(ctx, prID, reviewerID, state); err != nil {
+		// Ревьюера успели заменить между чтением и записью
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("user %s: %w", reviewerID, domain.ErrNotAssigned)
+		}
+		return nil, err
+	}
+
+	if pr.ReviewStates == nil {
+		pr.ReviewStates = make(map[string]string)
+	}
+	pr.ReviewStates[reviewerID] = state
+	return pr, nil
+}
+
+// DeactivateUsers выключает пользователей (перечисленных и/или всю команду) и
+// переназначает их открытые ревью по тем же правилам, что и Reassign.
+func (s *PRService) DeactivateUsers(ctx context.Context, teamName string, userIDs []string, actor string) (*models.DeactivationResult, error) {
+	if teamName == "" && len(userIDs) == 0 {
+		return nil, fmt.Errorf("team_name or user_ids required: %w", domain.ErrInvalidInput)
+	}
+	if teamName != "" {
+		if _, err := s.teamRepo.FindByName(ctx, teamName); err != nil {
+			return nil, err
+		}
+	}
+	res, err := s.userRepo.DeactivateWithReassign(ctx, teamName, userIDs, s.planReassignments, audit(actor, "reviewer deactivated"))
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RemoveTeamMembers убирает пользователей из команды и переназначает их ревью
+// в OPEN PR на оставшихся участников; если замены нет, ревьюер снимается.
+func (s *PRService) RemoveTeamMembers(ctx context.Context, teamName string, userIDs []string, actor string) (*models.DeactivationResult, error) {
+	if len(userIDs) == 0 {
+		return nil, fmt.Errorf("user_ids required: %w", domain.ErrInvalidInput)
+	}
+	if _, err := s.teamRepo.FindByName(ctx, teamName); err != nil {
+		return nil, err
+	}
+	for _, id := range userIDs {
+		u, err := s.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if u.TeamName != teamName {
+			return nil, fmt.Errorf("user %s is not in team %s: %w", id, teamName, domain.ErrInvalidInput)
+		}
+	}
+	res, err := s.userRepo.DetachWithReassign(ctx, teamName, userIDs, s.planReassignments, audit(actor, "removed from team"))
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteTeam удаляет команду. Участники остаются без команды, их ревью в OPEN PR
+// снимаются (заменить некем), PR авторов команды не трогаются.
+func (s *PRService) DeleteTeam(ctx context.Context, teamName, actor string) (*models.DeactivationResult, error) {
+	res, err := s.teamRepo.Delete(ctx, teamName, s.planReassignments, audit(actor, "team deleted"))
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// MoveUser переводит пользователя в другую команду. При handover его ревью в
+// OPEN PR передаются участникам прежней команды, иначе остаются за ним.
+func (s *PRService) MoveUser(ctx context.Context, userID, toTeam string, handover bool, actor string) (*models.TeamMove, []models.ReviewerChange, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.teamRepo.FindByName(ctx, toTeam); err != nil {
+		return nil, nil, err
+	}
+	if u.TeamName == toTeam {
+		return nil, nil, fmt.Errorf("user %s is already in team %s: %w", userID, toTeam, domain.ErrInvalidInput)
+	}
+	move, changes, err := s.userRepo.MoveTeam(ctx, userID, toTeam, handover, s.planReassignments, audit(actor, "moved to team "+toTeam))
+	if err != nil {
+		return nil, nil, err
+	}
+	return move, changes, nil
+}
+
+// pickForPR выбирает замену ревьюеру из команды, под которую он назначался: из
+// команды автора, а для запасного ревьюера — из запасных команд по порядку.
+// Текущая команда самого ревьюера не важна: после перевода без передачи ревью
+// он остаётся на PR прежней команды. Пустая строка — заменить некем.
+func (s *PRService) pickForPR(ctx context.Context, pr *models.PullRequest, oldReviewerID string) (string, error) {
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return "", fmt.Errorf("author: %w", err)
+	}
+	if author.TeamName == "" {
+		return "", nil
+	}
+	settings, err := effectiveSettings(ctx, s.teamRepo, author.TeamName, s.defaults)
+	if err != nil {
+		return "", err
+	}
+
+	exclude := append([]string{pr.AuthorID}, pr.Reviewers...)
+	for _, team := range models.ReplacementTeams(author.TeamName, pr.Fallback[oldReviewerID], settings) {
+		candidates, err := s.prRepo.FindCandidates(ctx, team, exclude)
+		if err != nil {
+			return "", err
+		}
+		if id := s.pickReplacement(s.selectorFor(settings), team, candidates, nil); id != "" {
+			return id, nil
+		}
+	}
+	return "", nil
+}
+
+// Get возвращает текущее состояние PR с ревьюерами и их решениями.
+func (s *PRService) Get(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return s.prRepo.GetByID(ctx, prID)
+}
+
+// History возвращает историю назначений ревьюеров PR в порядке записи.
+func (s *PRService) History(ctx context.Context, prID string) ([]models.AssignmentRecord, error) {
+	if _, err := s.prRepo.GetByID(ctx, prID); err != nil {
+		return nil, err
+	}
+	return s.prRepo.ListHistory(ctx, prID)
+}
+
+// planReassignments выбирает замены по тем же правилам, что и pickForPR: команды
+// из models.ReplacementTeams и стратегия команды автора.
+func (s *PRService) planReassignments(reviews []models.AffectedReview, settings map[string]*models.TeamSettings,
+	candidates map[string][]models.ReviewCandidate) []models.ReviewerChange {
+	// Ревьюеры PR с учётом уже сделанных в этом батче замен
+	current := make(map[string][]string)
+	changes := make([]models.ReviewerChange, 0, len(reviews))
+
+	for _, rv := range reviews {
+		reviewers, ok := current[rv.PRID]
+		if !ok {
+			reviewers = rv.Reviewers
+		}
+
+		// План строится внутри транзакции репозитория: настройки команд приходят из
+		// неё же, без записи — настройки по умолчанию
+		ts := settings[rv.AuthorTeam]
+		if ts == nil {
+			defaults := s.defaults
+			ts = &defaults
+		}
+		exclude := append([]string{rv.AuthorID}, reviewers...)
+		var newID, newTeam string
+		for _, team := range models.ReplacementTeams(rv.AuthorTeam, rv.Fallback, ts) {
+			if newID = s.pickReplacement(s.selectorFor(ts), team, candidates[team], exclude); newID != "" {
+				newTeam = team
+				break
+			}
+		}
+
+		next := make([]string, 0, len(reviewers))
+		for _, id := range reviewers {
+			if id != rv.ReviewerID {
+				next = append(next, id)
+			}
+		}
+		if newID != "" {
+			next = append(next, newID)
+			// Учитываем новую нагрузку для следующих PR в батче
+			team := candidates[newTeam]
+			for i := range team {
+				if team[i].UserID == newID {
+					team[i].OpenReviews++
+				}
+			}
+		}
+		current[rv.PRID] = next
+
+		changes = append(changes, models.ReviewerChange{
+			PRID:          rv.PRID,
+			OldReviewerID: rv.ReviewerID,
+			NewReviewerID: newID,
+		})
+	}
+	return changes
+}
+
+// pickReplacement возвращает одного ревьюера из candidates, выбранного selector,
+// пропуская exclude и тех, кто упёрся в лимит. Пустая строка — кандидатов нет.
+func (s *PRService) pickReplacement(selector ReviewerSelector, teamName string, candidates []models.ReviewCandidate, exclude []string) string {
+	var available []models.ReviewCandidate
+	for _, c := range underCapacity(candidates) {
+		if !slices.Contains(exclude, c.UserID) {
+			available = append(available, c)
+		}
+	}
+	picked := selector.Select(teamName, available, 1)
+	if len(picked) == 0 {
+		return ""
+	}
+	return picked[0]
+}
+
+func underCapacity(candidates []models.ReviewCandidate) []models.ReviewCandidate {
+	var out []models.ReviewCandidate
+	for _, c := range candidates {
+		if !c.AtCapacity() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func audit(actor, reason string) models.Audit {
+	if actor == "" {
+		actor = systemActor
+	}
+	return models.Audit{Actor: actor, Reason: reason}
+}
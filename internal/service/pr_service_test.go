@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+)
+
+// staleCandidates отдаёт кандидатов без учёта открытых ревью, как если бы их
+// нагрузка выросла между выбором и записью.
+type staleCandidates struct {
+	repo.PRRepository
+}
+
+func (r staleCandidates) FindCandidates(ctx context.Context, teamName string, exclude []string) ([]models.ReviewCandidate, error) {
+	cs, err := r.PRRepository.FindCandidates(ctx, teamName, exclude)
+	for i := range cs {
+		cs[i].OpenReviews = 0
+	}
+	return cs, err
+}
+
+func TestCreateReportsShortageFromWrite(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos()
+	repotest.SeedTeam(t, r, "backend", []string{"author", "u1", "u2"})
+	limit := 1
+	repotest.MustUpsert(t, r, &models.User{ID: "u2", Name: "name-u2", IsActive: true, TeamName: "backend", MaxOpenReviews: &limit})
+	repotest.MustCreatePR(t, r, "busy", "u1", "u2")
+
+	prs := NewPRService(staleCandidates{r.PRs}, r.Users, r.Teams, &RandomSelector{}, DefaultTeamSettings(), nil)
+	pr, err := prs.Create(ctx, "pr-1", "title", "author", false, "")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(pr.Reviewers) != 1 || pr.Reviewers[0] != "u1" {
+		t.Fatalf("reviewers %v, want [u1]", pr.Reviewers)
+	}
+	if pr.ReviewerShortage != models.ShortageAtCapacity {
+		t.Fatalf("shortage %q, want %s", pr.ReviewerShortage, models.ShortageAtCapacity)
+	}
+
+	// Ревьюеров меньше из-за размера команды, а не лимита
+	repotest.SeedTeam(t, r, "tiny", []string{"lonely", "mate"})
+	pr, err = prs.Create(ctx, "pr-2", "title", "lonely", false, "")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if pr.ReviewerShortage != models.ShortageNotEnoughTeammates {
+		t.Fatalf("shortage %q, want %s", pr.ReviewerShortage, models.ShortageNotEnoughTeammates)
+	}
+}
+
+func TestCreateExcludesAuthorAndInactive(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2"}, "u3", "u4")
+	repotest.SeedTeam(t, r, "frontend", []string{"u5"})
+
+	for _, strategy := range []string{StrategyRandom, StrategyRoundRobin, StrategyLeastLoaded, StrategyWorkingHours} {
+		selector, err := NewReviewerSelector(strategy, nil)
+		if err != nil {
+			t.Fatalf("selector %s: %v", strategy, err)
+		}
+		svc := NewPRService(r.PRs, r.Users, r.Teams, selector, DefaultTeamSettings(), nil)
+
+		for i := 0; i < 5; i++ {
+			id := strategy + "-" + string(rune('a'+i))
+			pr, err := svc.Create(ctx, id, "t", "u1", false, "")
+			if err != nil {
+				t.Fatalf("%s: create: %v", strategy, err)
+			}
+			if !slices.Equal(pr.Reviewers, []string{"u2"}) {
+				t.Fatalf("%s: reviewers %v, want only active teammate u2", strategy, pr.Reviewers)
+			}
+
+			if _, _, err := svc.Reassign(ctx, id, "u2", "", ""); !errors.Is(err, domain.ErrNoCandidate) {
+				t.Fatalf("%s: reassign must find no candidates, got %v", strategy, err)
+			}
+		}
+	}
+}
+
+func TestMergeRequiresApprovals(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+	repotest.MustCreatePR(t, r, "pr-1", "u1", "u2", "u3")
+
+	defaults := DefaultTeamSettings()
+	defaults.MinApprovals = 1
+	svc := NewPRService(r.PRs, r.Users, r.Teams, NewLeastLoadedSelector(nil), defaults, nil)
+	if _, err := svc.Review(ctx, "pr-1", "u3", models.ReviewChangesRequested); err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if _, err := svc.Merge(ctx, "pr-1", ""); !errors.Is(err, domain.ErrNotApproved) {
+		t.Fatalf("merge without approvals: want ErrNotApproved, got %v", err)
+	}
+	if _, err := svc.Review(ctx, "pr-1", "u2", models.ReviewApproved); err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if _, err := svc.Merge(ctx, "pr-1", ""); err != nil {
+		t.Fatalf("merge with approval: %v", err)
+	}
+	if _, err := svc.Review(ctx, "pr-1", "u3", models.ReviewApproved); !errors.Is(err, domain.ErrPRMerged) {
+		t.Fatalf("review after merge: want ErrPRMerged, got %v", err)
+	}
+}
+
+func TestCreateWithLead(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"lead", "u1", "u2", "u3", "u4"})
+	settings := &models.TeamSettings{TeamName: "backend", ReviewerCount: 3, LeadUserID: "lead", RequireLead: true}
+	if err := r.Teams.UpsertSettings(ctx, settings); err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+
+	svc := NewPRService(r.PRs, r.Users, r.Teams, NewLeastLoadedSelector(nil), DefaultTeamSettings(), nil)
+	for i := 0; i < 3; i++ {
+		pr, err := svc.Create(ctx, "pr-"+string(rune('a'+i)), "t", "u1", false, "")
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if len(pr.Reviewers) != 3 || pr.Reviewers[0] != "lead" || slices.Contains(pr.Reviewers, "u1") {
+			t.Fatalf("want lead plus 2 teammates, got %v", pr.Reviewers)
+		}
+	}
+	// Тимлид-автор себя не ревьюит
+	pr, err := svc.Create(ctx, "pr-lead", "t", "lead", false, "")
+	if err != nil || len(pr.Reviewers) != 3 || slices.Contains(pr.Reviewers, "lead") {
+		t.Fatalf("lead as author: %v, %v", pr, err)
+	}
+}
+
+func TestCreateWithFallbackTeam(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "small", []string{"s1", "s2"})
+	repotest.SeedTeam(t, r, "partner", []string{"p1", "p2"})
+	settings := &models.TeamSettings{TeamName: "small", ReviewerCount: 2, FallbackTeams: []string{"partner"}}
+	if err := r.Teams.UpsertSettings(ctx, settings); err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+
+	svc := NewPRService(r.PRs, r.Users, r.Teams, NewLeastLoadedSelector(nil), DefaultTeamSettings(), nil)
+	pr, err := svc.Create(ctx, "pr-1", "t", "s1", false, "")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(pr.Reviewers) != 2 || pr.Reviewers[0] != "s2" || pr.Fallback["s2"] || !pr.Fallback[pr.Reviewers[1]] {
+		t.Fatalf("want own teammate plus one fallback, got %v (fallback %v)", pr.Reviewers, pr.Fallback)
+	}
+	if pr.ReviewerShortage != "" {
+		t.Fatalf("fallback filled the slot, shortage %q", pr.ReviewerShortage)
+	}
+
+	// Замена запасного ревьюера берётся из той же запасной команды
+	fb := pr.Reviewers[1]
+	_, replacement, err := svc.Reassign(ctx, "pr-1", fb, "", "")
+	if err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	stored, _ := r.PRs.GetByID(ctx, "pr-1")
+	if !slices.Contains([]string{"p1", "p2"}, replacement) || replacement == fb || !stored.Fallback[replacement] {
+		t.Fatalf("replacement %s, fallback %v", replacement, stored.Fallback)
+	}
+}
+
+// noneSelector никого не выбирает: если замену нашли, её выбрала стратегия команды.
+type noneSelector struct{}
+
+func (noneSelector) Select(string, []models.ReviewCandidate, int) []string { return nil }
+
+func TestReassignUsesTeamStrategy(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"})
+	settings := &models.TeamSettings{
+		TeamName: "backend", ReviewerCount: 1, Strategy: StrategyLeastLoaded,
+		ReviewSLA: time.Hour, SLAAction: models.SLAActionReassign,
+	}
+	if err := r.Teams.UpsertSettings(ctx, settings); err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+	repotest.MustCreatePR(t, r, "pr-1", "u1", "u2")
+	repotest.MustCreatePR(t, r, "pr-2", "u1", "u4")
+	repotest.MustCreatePR(t, r, "busy", "u2", "u4")
+
+	svc := NewPRService(r.PRs, r.Users, r.Teams, noneSelector{}, DefaultTeamSettings(), nil)
+	if _, newID, err := svc.Reassign(ctx, "pr-1", "u2", "", ""); err != nil || newID != "u3" {
+		t.Fatalf("manual reassign: %s, %v; want least loaded u3", newID, err)
+	}
+
+	// SLA-замена идёт тем же путём: стратегия по умолчанию не нашла бы никого
+	done, err := svc.EscalateOverdue(ctx, time.Now().Add(2*time.Hour))
+	if err != nil || len(done) != 3 {
+		t.Fatalf("escalate: %+v, %v", done, err)
+	}
+	for _, e := range done {
+		if e.NewReviewerID == "" {
+			t.Fatalf("sla reassign without replacement: %+v", e)
+		}
+	}
+}
+
+func TestReassignAfterMoveUsesPRTeam(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"author", "u2", "u3"})
+	repotest.SeedTeam(t, r, "frontend", []string{"f1"})
+	repotest.MustCreatePR(t, r, "pr-1", "author", "u2")
+
+	svc := NewPRService(r.PRs, r.Users, r.Teams, NewLeastLoadedSelector(nil), DefaultTeamSettings(), nil)
+	if _, _, err := svc.MoveUser(ctx, "u2", "frontend", false, ""); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	// u2 остался на PR команды backend, замена — тоже из backend
+	if _, newID, err := svc.Reassign(ctx, "pr-1", "u2", "", ""); err != nil || newID != "u3" {
+		t.Fatalf("reassign: %s, %v; want u3 from the pr team", newID, err)
+	}
+}
+
+func TestDeactivateUsesPRTeamAndStrategy(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"author", "u2", "u3"})
+	repotest.SeedTeam(t, r, "frontend", []string{"f1", "f2"})
+	settings := &models.TeamSettings{TeamName: "backend", ReviewerCount: 1, Strategy: StrategyLeastLoaded}
+	if err := r.Teams.UpsertSettings(ctx, settings); err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+	repotest.MustCreatePR(t, r, "pr-1", "author", "u2")
+
+	// Стратегия по умолчанию никого не выбирает: замену находит стратегия команды
+	svc := NewPRService(r.PRs, r.Users, r.Teams, noneSelector{}, DefaultTeamSettings(), nil)
+	if _, _, err := svc.MoveUser(ctx, "u2", "frontend", false, ""); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	res, err := svc.DeactivateUsers(ctx, "", []string{"u2"}, "")
+	if err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	// u2 уже во frontend, но PR команды backend — замена оттуда же
+	if len(res.Changes) != 1 || res.Changes[0].NewReviewerID != "u3" {
+		t.Fatalf("changes %+v, want u3 from the pr team", res.Changes)
+	}
+}
+
+func TestHandoverOnFallbackReview(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "small", []string{"s1"})
+	repotest.SeedTeam(t, r, "partner", []string{"p1", "p2"})
+	repotest.SeedTeam(t, r, "ops", []string{"o1"})
+	repotest.SeedTeam(t, r, "design", []string{"d1"})
+	settings := &models.TeamSettings{TeamName: "small", ReviewerCount: 1, FallbackTeams: []string{"partner"}}
+	if err := r.Teams.UpsertSettings(ctx, settings); err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+	svc := NewPRService(r.PRs, r.Users, r.Teams, NewLeastLoadedSelector(nil), DefaultTeamSettings(), nil)
+	pr, err := svc.Create(ctx, "pr-1", "t", "s1", false, "")
+	if err != nil || len(pr.Reviewers) != 1 || !pr.Fallback[pr.Reviewers[0]] {
+		t.Fatalf("create: %+v, %v", pr, err)
+	}
+	fb := pr.Reviewers[0]
+
+	// Запасной ревьюер перешёл в ops без передачи, затем в design с передачей:
+	// замена из запасной команды partner, а не из ops, и остаётся запасной
+	if _, _, err := svc.MoveUser(ctx, fb, "ops", false, ""); err != nil {
+		t.Fatalf("move to ops: %v", err)
+	}
+	_, changes, err := svc.MoveUser(ctx, fb, "design", true, "")
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	other := map[string]string{"p1": "p2", "p2": "p1"}[fb]
+	if len(changes) != 1 || changes[0].NewReviewerID != other {
+		t.Fatalf("changes %+v, want %s from the fallback team", changes, other)
+	}
+	stored, _ := r.PRs.GetByID(ctx, "pr-1")
+	if !stored.Fallback[other] {
+		t.Fatalf("fallback flags %v", stored.Fallback)
+	}
+}
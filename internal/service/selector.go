@@ -0,0 +1,185 @@
+package service
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+const (
+	StrategyRandom       = "random"
+	StrategyRoundRobin   = "round_robin"
+	StrategyLeastLoaded  = "least_loaded"
+	StrategyWorkingHours = "working_hours"
+)
+
+// ReviewerSelector выбирает до n ревьюеров из уже отфильтрованных кандидатов
+// (активные, не автор, не назначенные на PR).
+type ReviewerSelector interface {
+	Select(teamName string, candidates []models.ReviewCandidate, n int) []string
+}
+
+// NewReviewerSelector: now — часы для working_hours, nil — time.Now.
+func NewReviewerSelector(strategy string, now func() time.Time) (ReviewerSelector, error) {
+	switch strategy {
+	case "", StrategyRandom:
+		return &RandomSelector{}, nil
+	case StrategyRoundRobin:
+		return NewRoundRobinSelector(), nil
+	case StrategyLeastLoaded:
+		return &LeastLoadedSelector{}, nil
+	case StrategyWorkingHours:
+		return NewWorkingHoursSelector(now, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown reviewer strategy %q", strategy)
+	}
+}
+
+// RandomSelector выбирает случайно; rnd nil — общий генератор.
+type RandomSelector struct {
+	rnd *rand.Rand
+}
+
+// NewRandomSelector: заданный rnd делает выбор воспроизводимым.
+func NewRandomSelector(rnd *rand.Rand) *RandomSelector {
+	return &RandomSelector{rnd: rnd}
+}
+
+func (s *RandomSelector) Select(_ string, candidates []models.ReviewCandidate, n int) []string {
+	return firstIDs(shuffled(s.rnd, candidates), n)
+}
+
+// RoundRobinSelector идёт по участникам команды в порядке user_id, продолжая
+// с того места, где остановился в прошлый раз. Состояние хранится в памяти процесса.
+type RoundRobinSelector struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{last: make(map[string]string)}
+}
+
+func (s *RoundRobinSelector) Select(teamName string, candidates []models.ReviewCandidate, n int) []string {
+	if len(candidates) == 0 || n <= 0 {
+		return nil
+	}
+
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.UserID
+	}
+	sort.Strings(ids)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := sort.SearchStrings(ids, s.last[teamName])
+	if start < len(ids) && ids[start] == s.last[teamName] {
+		start++
+	}
+
+	picked := make([]string, 0, min(n, len(ids)))
+	for i := 0; i < len(ids) && len(picked) < n; i++ {
+		picked = append(picked, ids[(start+i)%len(ids)])
+	}
+	s.last[teamName] = picked[len(picked)-1]
+	return picked
+}
+
+// LeastLoadedSelector отдаёт предпочтение кандидатам с наименьшим числом
+// открытых ревью, при равенстве выбор случайный (rnd nil — общий генератор).
+type LeastLoadedSelector struct {
+	rnd *rand.Rand
+}
+
+func NewLeastLoadedSelector(rnd *rand.Rand) *LeastLoadedSelector {
+	return &LeastLoadedSelector{rnd: rnd}
+}
+
+func (s *LeastLoadedSelector) Select(_ string, candidates []models.ReviewCandidate, n int) []string {
+	list := shuffled(s.rnd, candidates)
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].OpenReviews < list[j].OpenReviews
+	})
+	return firstIDs(list, n)
+}
+
+// WorkingHoursSelector отдаёт предпочтение тем, у кого сейчас рабочее время
+// (без заданных часов — всегда рабочее), дальше как LeastLoadedSelector. Если
+// в рабочем окне никого нет, назначаются остальные — PR не остаётся без ревьюеров.
+// now nil — time.Now, rnd nil — общий генератор.
+type WorkingHoursSelector struct {
+	now func() time.Time
+	rnd *rand.Rand
+}
+
+// NewWorkingHoursSelector: заданные now и rnd делают выбор воспроизводимым.
+func NewWorkingHoursSelector(now func() time.Time, rnd *rand.Rand) *WorkingHoursSelector {
+	return &WorkingHoursSelector{now: now, rnd: rnd}
+}
+
+func (s *WorkingHoursSelector) Select(_ string, candidates []models.ReviewCandidate, n int) []string {
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+
+	// Пояса загружаются по разу до сортировки, а не при каждом сравнении
+	locations := make(map[string]*time.Location)
+	working := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		if c.WorkingHours == nil {
+			working[c.UserID] = true
+			continue
+		}
+		loc, ok := locations[c.WorkingHours.TimeZone]
+		if !ok {
+			loc = c.WorkingHours.Location()
+			locations[c.WorkingHours.TimeZone] = loc
+		}
+		working[c.UserID] = c.WorkingHours.ContainsIn(now, loc)
+	}
+
+	list := shuffled(s.rnd, candidates)
+	sort.SliceStable(list, func(i, j int) bool {
+		wi, wj := working[list[i].UserID], working[list[j].UserID]
+		if wi != wj {
+			return wi
+		}
+		return list[i].OpenReviews < list[j].OpenReviews
+	})
+	return firstIDs(list, n)
+}
+
+func shuffled(rnd *rand.Rand, candidates []models.ReviewCandidate) []models.ReviewCandidate {
+	list := make([]models.ReviewCandidate, len(candidates))
+	copy(list, candidates)
+	swap := func(i, j int) {
+		list[i], list[j] = list[j], list[i]
+	}
+	if rnd != nil {
+		rnd.Shuffle(len(list), swap)
+	} else {
+		rand.Shuffle(len(list), swap)
+	}
+	return list
+}
+
+func firstIDs(candidates []models.ReviewCandidate, n int) []string {
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+	if n <= 0 {
+		return nil
+	}
+	ids := make([]string, n)
+	for i := 0; i < n; i++ {
+		ids[i] = candidates[i].UserID
+	}
+	return ids
+}
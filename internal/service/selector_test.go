@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+)
+
+func candidates(loads map[string]int) []models.ReviewCandidate {
+	var cs []models.ReviewCandidate
+	for _, id := range slices.Sorted(maps.Keys(loads)) {
+		cs = append(cs, models.ReviewCandidate{UserID: id, OpenReviews: loads[id]})
+	}
+	return cs
+}
+
+func seeded(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+func TestNewReviewerSelector(t *testing.T) {
+	for strategy, want := range map[string]ReviewerSelector{
+		// Без REVIEWER_STRATEGY — случайный выбор, как до появления стратегий
+		"":                   &RandomSelector{},
+		StrategyRandom:       &RandomSelector{},
+		StrategyRoundRobin:   NewRoundRobinSelector(),
+		StrategyLeastLoaded:  &LeastLoadedSelector{},
+		StrategyWorkingHours: &WorkingHoursSelector{},
+	} {
+		got, err := NewReviewerSelector(strategy, nil)
+		if err != nil {
+			t.Fatalf("%q: %v", strategy, err)
+		}
+		if reflect.TypeOf(got) != reflect.TypeOf(want) {
+			t.Fatalf("%q: got %T, want %T", strategy, got, want)
+		}
+	}
+	if _, err := NewReviewerSelector("fastest", nil); err == nil {
+		t.Fatal("unknown strategy accepted")
+	}
+}
+
+func TestRandomSelector(t *testing.T) {
+	cs := candidates(map[string]int{"u1": 0, "u2": 0, "u3": 0, "u4": 0})
+	for _, tc := range []struct {
+		name string
+		n    int
+		want int
+	}{
+		{"two of four", 2, 2},
+		{"more than available", 10, 4},
+		{"none", 0, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewRandomSelector(seeded(7)).Select("backend", cs, tc.n)
+			if len(got) != tc.want {
+				t.Fatalf("picked %v, want %d", got, tc.want)
+			}
+			if again := NewRandomSelector(seeded(7)).Select("backend", cs, tc.n); !slices.Equal(got, again) {
+				t.Fatalf("same seed: %v, then %v", got, again)
+			}
+			seen := make(map[string]bool)
+			for _, id := range got {
+				if seen[id] || !slices.ContainsFunc(cs, func(c models.ReviewCandidate) bool { return c.UserID == id }) {
+					t.Fatalf("picked %v: duplicate or unknown %s", got, id)
+				}
+				seen[id] = true
+			}
+		})
+	}
+
+	// Разные seed — разный выбор, иначе это не случайность
+	first := make(map[string]bool)
+	for seed := range uint64(50) {
+		first[NewRandomSelector(seeded(seed)).Select("backend", cs, 1)[0]] = true
+	}
+	if len(first) != len(cs) {
+		t.Fatalf("over 50 seeds picked only %v", first)
+	}
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	sel := NewRoundRobinSelector()
+	team := candidates(map[string]int{"u3": 0, "u1": 0, "u2": 0})
+	other := candidates(map[string]int{"v1": 0, "v2": 0})
+	for i, tc := range []struct {
+		team       string
+		candidates []models.ReviewCandidate
+		n          int
+		want       []string
+	}{
+		{"backend", team, 2, []string{"u1", "u2"}},
+		// У каждой команды своя позиция
+		{"frontend", other, 1, []string{"v1"}},
+		{"backend", team, 2, []string{"u3", "u1"}},
+		{"backend", team, 5, []string{"u2", "u3", "u1"}},
+		{"frontend", other, 1, []string{"v2"}},
+		// Последний выбранный ушёл из кандидатов: продолжаем со следующего за ним
+		{"backend", candidates(map[string]int{"u2": 0, "u3": 0}), 1, []string{"u2"}},
+		{"backend", team, 0, nil},
+		{"backend", nil, 1, nil},
+	} {
+		if got := sel.Select(tc.team, tc.candidates, tc.n); !slices.Equal(got, tc.want) {
+			t.Fatalf("step %d (%s): got %v, want %v", i, tc.team, got, tc.want)
+		}
+	}
+}
+
+// Create и Reassign идут по одному кругу: замена продолжает с места последнего назначения.
+func TestRoundRobinSharedBetweenCreateAndReassign(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos()
+	repotest.SeedTeam(t, r, "backend", []string{"author", "u1", "u2", "u3", "u4"})
+	prs := NewPRService(r.PRs, r.Users, r.Teams, NewRoundRobinSelector(), DefaultTeamSettings(), nil)
+
+	pr, err := prs.Create(ctx, "pr-1", "first", "author", false, "")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if want := []string{"u1", "u2"}; !slices.Equal(pr.Reviewers, want) {
+		t.Fatalf("create: reviewers %v, want %v", pr.Reviewers, want)
+	}
+	if _, newID, err := prs.Reassign(ctx, "pr-1", "u1", "", ""); err != nil || newID != "u3" {
+		t.Fatalf("reassign: got %q, %v; want u3", newID, err)
+	}
+	pr, err = prs.Create(ctx, "pr-2", "second", "author", false, "")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if want := []string{"u4", "u1"}; !slices.Equal(pr.Reviewers, want) {
+		t.Fatalf("create after reassign: reviewers %v, want %v", pr.Reviewers, want)
+	}
+}
+
+func TestLeastLoadedSelector(t *testing.T) {
+	cs := candidates(map[string]int{"busy": 5, "idle1": 0, "idle2": 0, "mid": 2})
+	for _, tc := range []struct {
+		name string
+		n    int
+		// Первые выбранные в любом порядке, дальше — строго по порядку
+		anyOrder []string
+		then     []string
+	}{
+		{"least loaded first", 2, []string{"idle1", "idle2"}, nil},
+		{"then by load", 3, []string{"idle1", "idle2"}, []string{"mid"}},
+		{"everyone", 9, []string{"idle1", "idle2"}, []string{"mid", "busy"}},
+		{"one", 1, nil, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewLeastLoadedSelector(seeded(3)).Select("backend", cs, tc.n)
+			if want := min(tc.n, len(cs)); len(got) != want {
+				t.Fatalf("picked %v, want %d", got, want)
+			}
+			k := len(tc.anyOrder)
+			if k > 0 && !slices.Equal(repotest.Sorted(got[:k]), tc.anyOrder) {
+				t.Fatalf("picked %v, want %v first", got, tc.anyOrder)
+			}
+			if !slices.Equal(got[k:], tc.then) && tc.then != nil {
+				t.Fatalf("picked %v, want %v after %v", got, tc.then, tc.anyOrder)
+			}
+			if tc.n == 1 && got[0] != "idle1" && got[0] != "idle2" {
+				t.Fatalf("picked %v, want an idle reviewer", got)
+			}
+			if again := NewLeastLoadedSelector(seeded(3)).Select("backend", cs, tc.n); !slices.Equal(got, again) {
+				t.Fatalf("same seed: %v, then %v", got, again)
+			}
+		})
+	}
+
+	// Равных по нагрузке выбираем случайно, а не всегда первого по id
+	first := make(map[string]bool)
+	for seed := range uint64(50) {
+		first[NewLeastLoadedSelector(seeded(seed)).Select("backend", cs, 1)[0]] = true
+	}
+	if !reflect.DeepEqual(first, map[string]bool{"idle1": true, "idle2": true}) {
+		t.Fatalf("over 50 seeds picked first %v", first)
+	}
+}
+
+func TestWorkingHoursSelector(t *testing.T) {
+	hours := func(zone string, start, end int) *models.WorkingHours {
+		return &models.WorkingHours{TimeZone: zone, Start: start * 60, End: end * 60}
+	}
+	// Окна 9–18 по местному времени: UTC, Москва (UTC+3) и Владивосток (UTC+10)
+	cs := []models.ReviewCandidate{
+		{UserID: "utc", WorkingHours: hours("UTC", 9, 18)},
+		{UserID: "msk", WorkingHours: hours("Europe/Moscow", 9, 18), OpenReviews: 1},
+		{UserID: "vvo", WorkingHours: hours("Asia/Vladivostok", 9, 18)},
+		{UserID: "night", WorkingHours: hours("UTC", 22, 6)},
+		{UserID: "busy", OpenReviews: 3},
+	}
+	for _, tc := range []struct {
+		name string
+		now  time.Time
+		// Рабочие сейчас; busy без часов рабочий всегда и из-за нагрузки идёт последним
+		want []string
+	}{
+		{"msk opens at 06:00 utc", time.Date(2025, 3, 10, 6, 0, 0, 0, time.UTC), []string{"msk", "vvo", "busy"}},
+		{"msk still closed", time.Date(2025, 3, 10, 5, 59, 0, 0, time.UTC), []string{"vvo", "night", "busy"}},
+		{"vvo closes at 08:00 utc", time.Date(2025, 3, 10, 7, 59, 0, 0, time.UTC), []string{"msk", "vvo", "busy"}},
+		{"utc opens, vvo closed", time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), []string{"utc", "msk", "busy"}},
+		{"msk closes at 15:00 utc", time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC), []string{"utc", "busy"}},
+		{"overnight before midnight", time.Date(2025, 3, 10, 23, 30, 0, 0, time.UTC), []string{"vvo", "night", "busy"}},
+		{"overnight last minute", time.Date(2025, 3, 11, 5, 59, 0, 0, time.UTC), []string{"vvo", "night", "busy"}},
+		{"overnight end", time.Date(2025, 3, 11, 6, 0, 0, 0, time.UTC), []string{"msk", "vvo", "busy"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sel := NewWorkingHoursSelector(func() time.Time { return tc.now }, seeded(1))
+			got := sel.Select("backend", cs, len(cs))
+			k := len(tc.want)
+			if !slices.Equal(repotest.Sorted(got[:k-1]), repotest.Sorted(tc.want[:k-1])) || got[k-1] != "busy" {
+				t.Fatalf("picked %v, want %v working first", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWorkingHoursPrefersWorkingReviewers(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2", "u3", "u4"})
+
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	working := &models.WorkingHours{TimeZone: "UTC", Start: 11 * 60, End: 13 * 60}
+	offHours := &models.WorkingHours{TimeZone: "UTC", Start: 14 * 60, End: 15 * 60}
+	for id, wh := range map[string]*models.WorkingHours{"u2": offHours, "u3": offHours, "u4": working} {
+		if err := r.Users.SetWorkingHours(ctx, id, wh); err != nil {
+			t.Fatalf("set hours %s: %v", id, err)
+		}
+	}
+
+	selector := NewWorkingHoursSelector(func() time.Time { return now }, nil)
+	svc := NewPRService(r.PRs, r.Users, r.Teams, selector, DefaultTeamSettings(), nil)
+	pr, err := svc.Create(ctx, "pr-1", "t", "u1", false, "")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	// Второй слот заполняется и вне рабочего окна
+	if len(pr.Reviewers) != 2 || !slices.Contains(pr.Reviewers, "u4") {
+		t.Fatalf("reviewers %v, want u4 and one more", pr.Reviewers)
+	}
+	if err := r.Teams.UpsertSettings(ctx, &models.TeamSettings{TeamName: "backend", ReviewerCount: 1}); err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+	for _, id := range []string{"pr-2", "pr-3", "pr-4"} {
+		pr, err := svc.Create(ctx, id, "t", "u1", false, "")
+		if err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+		if !slices.Equal(pr.Reviewers, []string{"u4"}) {
+			t.Fatalf("%s reviewers %v, want the one in working hours", id, pr.Reviewers)
+		}
+	}
+
+	// Стратегия команды идёт по тем же часам, что передали сервису
+	byTeam := NewPRService(r.PRs, r.Users, r.Teams, &RandomSelector{}, DefaultTeamSettings(), func() time.Time { return now })
+	if err := r.Teams.UpsertSettings(ctx, &models.TeamSettings{TeamName: "backend", ReviewerCount: 1, Strategy: StrategyWorkingHours}); err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+	for _, id := range []string{"pr-5", "pr-6", "pr-7"} {
+		pr, err := byTeam.Create(ctx, id, "t", "u1", false, "")
+		if err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+		if !slices.Equal(pr.Reviewers, []string{"u4"}) {
+			t.Fatalf("%s reviewers %v with team strategy, want the one in working hours", id, pr.Reviewers)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/memory"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+)
+
+// newTestRepos — репозитории поверх одного хранилища в памяти. Контракт самих
+// репозиториев проверяет repotest, здесь они только держат состояние.
+func newTestRepos() repotest.Repos {
+	s := memory.NewStore()
+	return repotest.Repos{
+		Users:         memory.NewUserRepo(s),
+		Teams:         memory.NewTeamRepo(s),
+		PRs:           memory.NewPRRepo(s),
+		Absences:      memory.NewAbsenceRepo(s),
+		Webhooks:      memory.NewWebhookRepo(s),
+		Outbox:        memory.NewOutboxRepo(s),
+		Notifications: memory.NewNotificationRepo(s),
+	}
+}
+
+func prIDs(prs []*models.PullRequest) []string {
+	ids := make([]string, len(prs))
+	for i, pr := range prs {
+		ids[i] = pr.ID
+	}
+	return ids
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+const slaReason = "review SLA breached"
+
+// OverdueReviews возвращает ревью PR команды, висящие в PENDING дольше её SLA.
+func (s *PRService) OverdueReviews(ctx context.Context, teamName string, now time.Time) ([]models.OverdueReview, error) {
+	if _, err := s.teamRepo.FindByName(ctx, teamName); err != nil {
+		return nil, err
+	}
+	return s.prRepo.ListOverdue(ctx, teamName, now)
+}
+
+// EscalateOverdue выполняет sla_action для просроченных ревью всех команд:
+// reassign — замена по правилам Reassign, add_reviewer — ещё один ревьюер из
+// команды автора. Если кандидатов нет, ревью остаётся за прежним ревьюером, но
+// помечается эскалированным (NewReviewerID пустой), чтобы не перебирать его
+// на каждом запуске; в списке просроченных оно по-прежнему видно.
+func (s *PRService) EscalateOverdue(ctx context.Context, now time.Time) ([]models.Escalation, error) {
+	overdue, err := s.prRepo.ListOverdue(ctx, "", now)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make(map[string]*models.TeamSettings)
+	var done []models.Escalation
+	for _, o := range overdue {
+		if o.Escalated {
+			continue
+		}
+		ts, ok := settings[o.TeamName]
+		if !ok {
+			if ts, err = effectiveSettings(ctx, s.teamRepo, o.TeamName, s.defaults); err != nil {
+				return done, err
+			}
+			settings[o.TeamName] = ts
+		}
+
+		e := models.Escalation{PRID: o.PRID, ReviewerID: o.ReviewerID, Action: ts.SLAAction}
+		switch ts.SLAAction {
+		case models.SLAActionReassign:
+			_, e.NewReviewerID, err = s.Reassign(ctx, o.PRID, o.ReviewerID, systemActor, slaReason)
+		case models.SLAActionAddReviewer:
+			if e.NewReviewerID, err = s.addReviewer(ctx, o.PRID, ts); err == nil {
+				err = s.prRepo.MarkEscalated(ctx, o.PRID, o.ReviewerID)
+			}
+		default:
+			continue
+		}
+		if errors.Is(err, domain.ErrNoCandidate) {
+			err = s.prRepo.MarkEscalated(ctx, o.PRID, o.ReviewerID)
+		}
+		switch {
+		case err == nil:
+			done = append(done, e)
+		case errors.Is(err, domain.ErrNotFound),
+			errors.Is(err, domain.ErrAlreadyExists),
+			errors.Is(err, domain.ErrNotAssigned),
+			errors.Is(err, domain.ErrInvalidState),
+			errors.Is(err, domain.ErrPRMerged):
+			// PR успел измениться
+		default:
+			return done, fmt.Errorf("escalate %s on pr %s: %w", o.ReviewerID, o.PRID, err)
+		}
+	}
+	return done, nil
+}
+
+// addReviewer добавляет к PR ещё одного ревьюера из команды ts стратегией команды.
+func (s *PRService) addReviewer(ctx context.Context, prID string, ts *models.TeamSettings) (string, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return "", err
+	}
+	exclude := append([]string{pr.AuthorID}, pr.Reviewers...)
+	candidates, err := s.prRepo.FindCandidates(ctx, ts.TeamName, exclude)
+	if err != nil {
+		return "", err
+	}
+	picked := s.selectorFor(ts).Select(ts.TeamName, underCapacity(candidates), 1)
+	if len(picked) == 0 {
+		return "", domain.ErrNoCandidate
+	}
+	if err := s.prRepo.AddReviewer(ctx, prID, picked[0], audit(systemActor, slaReason)); err != nil {
+		return "", err
+	}
+	return picked[0], nil
+}
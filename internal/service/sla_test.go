@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+)
+
+func TestEscalateOverdue(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+	repotest.SeedTeam(t, r, "frontend", []string{"f1", "f2", "f3"})
+	repotest.MustCreatePR(t, r, "pr-1", "u1", "u2")
+	repotest.MustCreatePR(t, r, "pr-2", "f1", "f2")
+
+	for _, ts := range []models.TeamSettings{
+		{TeamName: "backend", ReviewerCount: 2, ReviewSLA: time.Hour, SLAAction: models.SLAActionAddReviewer},
+		{TeamName: "frontend", ReviewerCount: 2, ReviewSLA: time.Hour, SLAAction: models.SLAActionReassign},
+	} {
+		if err := r.Teams.UpsertSettings(ctx, &ts); err != nil {
+			t.Fatalf("settings %s: %v", ts.TeamName, err)
+		}
+	}
+
+	selector, _ := NewReviewerSelector(StrategyLeastLoaded, nil)
+	svc := NewPRService(r.PRs, r.Users, r.Teams, selector, DefaultTeamSettings(), nil)
+
+	if done, err := svc.EscalateOverdue(ctx, time.Now()); err != nil || len(done) != 0 {
+		t.Fatalf("nothing is overdue yet: %+v, %v", done, err)
+	}
+
+	later := time.Now().Add(2 * time.Hour)
+	done, err := svc.EscalateOverdue(ctx, later)
+	if err != nil {
+		t.Fatalf("escalate: %v", err)
+	}
+	want := []models.Escalation{
+		{PRID: "pr-1", ReviewerID: "u2", Action: models.SLAActionAddReviewer, NewReviewerID: "u3"},
+		{PRID: "pr-2", ReviewerID: "f2", Action: models.SLAActionReassign, NewReviewerID: "f3"},
+	}
+	slices.SortFunc(done, func(a, b models.Escalation) int { return strings.Compare(a.PRID, b.PRID) })
+	if !reflect.DeepEqual(done, want) {
+		t.Fatalf("escalations %+v, want %+v", done, want)
+	}
+
+	pr1, _ := r.PRs.GetByID(ctx, "pr-1")
+	if !slices.Equal(repotest.Sorted(pr1.Reviewers), []string{"u2", "u3"}) || !pr1.Escalated["u2"] {
+		t.Fatalf("pr-1 after add_reviewer: %v, escalated %v", pr1.Reviewers, pr1.Escalated)
+	}
+	pr2, _ := r.PRs.GetByID(ctx, "pr-2")
+	if !slices.Equal(pr2.Reviewers, []string{"f3"}) {
+		t.Fatalf("pr-2 after reassign: %v", pr2.Reviewers)
+	}
+	history, _ := r.PRs.ListHistory(ctx, "pr-1")
+	if last := history[len(history)-1]; last.Action != models.ActionAssigned || last.ReviewerID != "u3" || last.Actor != "system" {
+		t.Fatalf("pr-1 history: %+v", last)
+	}
+
+	overdue, err := svc.OverdueReviews(ctx, "backend", later)
+	if err != nil || len(overdue) != 2 || overdue[0].ReviewerID != "u2" || !overdue[0].Escalated || overdue[1].Escalated {
+		t.Fatalf("overdue in backend: %+v, %v", overdue, err)
+	}
+	if _, err := svc.OverdueReviews(ctx, "nope", later); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown team: want ErrNotFound, got %v", err)
+	}
+
+	// u2 уже эскалирован, u3 ещё в сроке
+	if done, err := svc.EscalateOverdue(ctx, time.Now()); err != nil || len(done) != 0 {
+		t.Fatalf("second run must be a no-op: %+v, %v", done, err)
+	}
+}
+
+func TestEscalateWithoutCandidateMarksEscalated(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "solo", []string{"s1", "s2"})
+	repotest.MustCreatePR(t, r, "pr-1", "s1", "s2")
+	settings := &models.TeamSettings{TeamName: "solo", ReviewerCount: 1, ReviewSLA: time.Hour, SLAAction: models.SLAActionReassign}
+	if err := r.Teams.UpsertSettings(ctx, settings); err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+	svc := NewPRService(r.PRs, r.Users, r.Teams, NewLeastLoadedSelector(nil), DefaultTeamSettings(), nil)
+
+	later := time.Now().Add(2 * time.Hour)
+	done, err := svc.EscalateOverdue(ctx, later)
+	want := []models.Escalation{{PRID: "pr-1", ReviewerID: "s2", Action: models.SLAActionReassign}}
+	if err != nil || !reflect.DeepEqual(done, want) {
+		t.Fatalf("escalate: %+v, %v; want %+v", done, err, want)
+	}
+	// Ревью остаётся за s2 и видно в просроченных, но повторно не перебирается
+	overdue, err := svc.OverdueReviews(ctx, "solo", later)
+	if err != nil || len(overdue) != 1 || !overdue[0].Escalated {
+		t.Fatalf("overdue: %+v, %v", overdue, err)
+	}
+	if done, err := svc.EscalateOverdue(ctx, later); err != nil || len(done) != 0 {
+		t.Fatalf("second run must be a no-op: %+v, %v", done, err)
+	}
+}
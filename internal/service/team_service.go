@@ -2,32 +2,48 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"slices"
 
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
 	"github.com/humooo/avito-backend-trainee-2025/internal/models"
 	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
 )
 
+// maxReviewerCount — верхняя граница reviewer_count в настройках команды.
+const maxReviewerCount = 10
+
+// DefaultTeamSettings — настройки команды, для которой ничего не задано.
+func DefaultTeamSettings() models.TeamSettings {
+	return models.TeamSettings{ReviewerCount: 2}
+}
+
 type TeamService struct {
 	teamRepo repo.TeamRepository
 	userRepo repo.UserRepository
+	defaults models.TeamSettings
 }
 
-func NewTeamService(teamRepo repo.TeamRepository, userRepo repo.UserRepository) *TeamService {
-	return &TeamService{teamRepo: teamRepo, userRepo: userRepo}
+func NewTeamService(teamRepo repo.TeamRepository, userRepo repo.UserRepository, defaults models.TeamSettings) *TeamService {
+	return &TeamService{teamRepo: teamRepo, userRepo: userRepo, defaults: defaults}
 }
 
+// Create создаёт команду с участниками. Участник другой команды не переносится —
+// для этого есть /users/moveTeam.
 func (s *TeamService) Create(ctx context.Context, name string, members []models.User) (*models.Team, error) {
 	existing, _ := s.teamRepo.FindByName(ctx, name)
 	if existing != nil {
-		return nil, fmt.Errorf("team exists")
+		return nil, fmt.Errorf("team %s: %w", name, domain.ErrTeamExists)
+	}
+	if err := s.checkNotInOtherTeam(ctx, name, members); err != nil {
+		return nil, err
 	}
 
 	team := &models.Team{Name: name}
 	if err := s.teamRepo.Create(ctx, team); err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "duplicate") {
-			return nil, fmt.Errorf("team exists")
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return nil, fmt.Errorf("team %s: %w", name, domain.ErrTeamExists)
 		}
 		return nil, err
 	}
@@ -44,9 +60,122 @@ func (s *TeamService) Create(ctx context.Context, name string, members []models.
 
 func (s *TeamService) GetByName(ctx context.Context, name string) (*models.Team, []*models.User, error) {
 	team, err := s.teamRepo.FindByName(ctx, name)
-	if err != nil || team == nil {
-		return nil, nil, fmt.Errorf("team not found")
+	if err != nil {
+		return nil, nil, err
 	}
 	users, err := s.userRepo.ListByTeam(ctx, name, false)
 	return team, users, err
 }
+
+// AddMembers добавляет участников в существующую команду или обновляет тех,
+// кто уже в ней. Участник другой команды не переносится — для этого есть /users/moveTeam.
+func (s *TeamService) AddMembers(ctx context.Context, teamName string, members []models.User) (*models.Team, []*models.User, error) {
+	team, err := s.teamRepo.FindByName(ctx, teamName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil, fmt.Errorf("members required: %w", domain.ErrInvalidInput)
+	}
+
+	if err := s.checkNotInOtherTeam(ctx, teamName, members); err != nil {
+		return nil, nil, err
+	}
+	for _, m := range members {
+		m.TeamName = teamName
+		if err := s.userRepo.Upsert(ctx, &m); err != nil {
+			return nil, nil, fmt.Errorf("failed to add user %s: %w", m.ID, err)
+		}
+	}
+
+	users, err := s.userRepo.ListByTeam(ctx, teamName, false)
+	return team, users, err
+}
+
+// checkNotInOtherTeam запрещает неявный перевод: Upsert иначе молча сменил бы
+// команду пользователя, оставив его ревьюером в PR прежней команды.
+func (s *TeamService) checkNotInOtherTeam(ctx context.Context, teamName string, members []models.User) error {
+	for _, m := range members {
+		existing, err := s.userRepo.GetByID(ctx, m.ID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		if existing != nil && existing.TeamName != "" && existing.TeamName != teamName {
+			return fmt.Errorf("user %s is in team %s, use /users/moveTeam: %w", m.ID, existing.TeamName, domain.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// Rename переименовывает команду; участники и настройки переходят вместе с ней.
+func (s *TeamService) Rename(ctx context.Context, oldName, newName string) (*models.Team, []*models.User, error) {
+	if newName == "" {
+		return nil, nil, fmt.Errorf("new_team_name required: %w", domain.ErrInvalidInput)
+	}
+	if err := s.teamRepo.Rename(ctx, oldName, newName); err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return nil, nil, fmt.Errorf("team %s: %w", newName, domain.ErrTeamExists)
+		}
+		return nil, nil, err
+	}
+	return s.GetByName(ctx, newName)
+}
+
+// Settings возвращает действующие настройки команды (по умолчанию, если не заданы).
+func (s *TeamService) Settings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	if _, err := s.teamRepo.FindByName(ctx, teamName); err != nil {
+		return nil, err
+	}
+	return effectiveSettings(ctx, s.teamRepo, teamName, s.defaults)
+}
+
+// UpdateSettings проверяет и сохраняет настройки целиком.
+func (s *TeamService) UpdateSettings(ctx context.Context, ts *models.TeamSettings) error {
+	if ts.ReviewerCount < 0 || ts.ReviewerCount > maxReviewerCount {
+		return fmt.Errorf("reviewer_count must be in 0..%d: %w", maxReviewerCount, domain.ErrInvalidInput)
+	}
+	if ts.MinApprovals < 0 || ts.MinApprovals > ts.ReviewerCount {
+		return fmt.Errorf("min_approvals must be in 0..reviewer_count: %w", domain.ErrInvalidInput)
+	}
+	if ts.Strategy != "" {
+		if _, err := NewReviewerSelector(ts.Strategy, nil); err != nil {
+			return fmt.Errorf("%v: %w", err, domain.ErrInvalidInput)
+		}
+	}
+	if ts.ReviewSLA < 0 {
+		return fmt.Errorf("review_sla_minutes must not be negative: %w", domain.ErrInvalidInput)
+	}
+	switch ts.SLAAction {
+	case "", models.SLAActionReassign, models.SLAActionAddReviewer:
+	default:
+		return fmt.Errorf("unknown sla_action %q: %w", ts.SLAAction, domain.ErrInvalidInput)
+	}
+	if ts.RequireLead && ts.LeadUserID == "" {
+		return fmt.Errorf("require_lead needs lead_user_id: %w", domain.ErrInvalidInput)
+	}
+	for i, name := range ts.FallbackTeams {
+		if name == ts.TeamName || slices.Contains(ts.FallbackTeams[:i], name) {
+			return fmt.Errorf("fallback team %s: %w", name, domain.ErrInvalidInput)
+		}
+	}
+	if ts.LeadUserID != "" {
+		lead, err := s.userRepo.GetByID(ctx, ts.LeadUserID)
+		if err != nil {
+			return fmt.Errorf("lead: %w", err)
+		}
+		if lead.TeamName != ts.TeamName {
+			return fmt.Errorf("lead %s is not in team %s: %w", lead.ID, ts.TeamName, domain.ErrInvalidInput)
+		}
+	}
+	return s.teamRepo.UpsertSettings(ctx, ts)
+}
+
+func effectiveSettings(ctx context.Context, teamRepo repo.TeamRepository, teamName string, defaults models.TeamSettings) (*models.TeamSettings, error) {
+	ts, err := teamRepo.GetSettings(ctx, teamName)
+	if errors.Is(err, domain.ErrNotFound) {
+		ts = &defaults
+		ts.TeamName = teamName
+		return ts, nil
+	}
+	return ts, err
+}
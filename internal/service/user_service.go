@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
 	"github.com/humooo/avito-backend-trainee-2025/internal/models"
 	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
 )
@@ -17,13 +20,45 @@ func NewUserService(userRepo repo.UserRepository, prRepo repo.PRRepository) *Use
 }
 
 func (s *UserService) SetIsActive(ctx context.Context, userID string, active bool) error {
-	return s.userRepo.SetActive(ctx, userID, active)
+	return s.userRepo.SetActive(ctx, userID, active, audit("", "user deactivated"))
 }
 
 func (s *UserService) ListByTeam(ctx context.Context, teamName string, activeOnly bool) ([]*models.User, error) {
 	return s.userRepo.ListByTeam(ctx, teamName, activeOnly)
 }
 
+// SetWorkingHours задаёт рабочие часы: пояс IANA и окно "HH:MM"–"HH:MM" по местному
+// времени (конец раньше начала — окно через полночь). Пустой timeZone сбрасывает часы.
+func (s *UserService) SetWorkingHours(ctx context.Context, userID, timeZone, start, end string) error {
+	if timeZone == "" {
+		return s.userRepo.SetWorkingHours(ctx, userID, nil)
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil || timeZone == "Local" {
+		return fmt.Errorf("unknown time_zone %q: %w", timeZone, domain.ErrInvalidInput)
+	}
+	wh := &models.WorkingHours{TimeZone: timeZone}
+	var err error
+	if wh.Start, err = parseClock(start); err != nil {
+		return err
+	}
+	if wh.End, err = parseClock(end); err != nil {
+		return err
+	}
+	if wh.Start == wh.End {
+		return fmt.Errorf("working hours window is empty: %w", domain.ErrInvalidInput)
+	}
+	return s.userRepo.SetWorkingHours(ctx, userID, wh)
+}
+
+// parseClock переводит "HH:MM" в минуты от полуночи.
+func parseClock(v string) (int, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM: %w", v, domain.ErrInvalidInput)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
 func (s *UserService) GetByID(ctx context.Context, id string) (*models.User, error) {
 	return s.userRepo.GetByID(ctx, id)
 }
@@ -35,3 +70,11 @@ func (s *UserService) GetReviewPRs(ctx context.Context, reviewerID string) ([]*m
 func (s *UserService) GetStats(ctx context.Context) ([]models.UserStat, error) {
 	return s.userRepo.GetStats(ctx)
 }
+
+// Moves возвращает переводы пользователя между командами в порядке записи.
+func (s *UserService) Moves(ctx context.Context, userID string) ([]models.TeamMove, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.userRepo.ListMoves(ctx, userID)
+}
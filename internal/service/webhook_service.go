@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo"
+)
+
+// Статус, в который событие платформы переводит PR.
+var eventStatus = map[string]string{
+	models.PREventReady:    models.StatusOpen,
+	models.PREventMerged:   models.StatusMerged,
+	models.PREventClosed:   models.StatusClosed,
+	models.PREventReopened: models.StatusOpen,
+}
+
+// WebhookService применяет к PR события из вебхуков GitHub и GitLab.
+type WebhookService struct {
+	prService *PRService
+	userRepo  repo.UserRepository
+}
+
+func NewWebhookService(prService *PRService, userRepo repo.UserRepository) *WebhookService {
+	return &WebhookService{prService: prService, userRepo: userRepo}
+}
+
+// LinkIdentity связывает логин на платформе с пользователем.
+func (s *WebhookService) LinkIdentity(ctx context.Context, platform, login, userID string) error {
+	if platform != models.PlatformGitHub && platform != models.PlatformGitLab {
+		return fmt.Errorf("unknown platform %q: %w", platform, domain.ErrInvalidInput)
+	}
+	if login == "" {
+		return fmt.Errorf("login required: %w", domain.ErrInvalidInput)
+	}
+	return s.userRepo.LinkIdentity(ctx, platform, login, userID)
+}
+
+// Apply выполняет событие: opened — Create, ready — MarkReady, merged — RecordMerge,
+// closed — Close, reopened — Reopen. Платформа доставляет события повторно, поэтому
+// уже применённое событие не ошибка: applied = false и текущее состояние PR.
+func (s *WebhookService) Apply(ctx context.Context, ev models.PREvent) (pr *models.PullRequest, applied bool, err error) {
+	actor := ev.Platform
+	if ev.SenderLogin != "" {
+		actor += ":" + ev.SenderLogin
+	}
+
+	if ev.Action == models.PREventOpened {
+		author, err := s.resolveUser(ctx, ev.Platform, ev.AuthorLogin)
+		if err != nil {
+			return nil, false, err
+		}
+		pr, err := s.prService.Create(ctx, ev.PRID, ev.Title, author.ID, ev.Draft, actor)
+		if errors.Is(err, domain.ErrPRExists) {
+			pr, err = s.prService.Get(ctx, ev.PRID)
+			return pr, false, err
+		}
+		return pr, err == nil, err
+	}
+
+	target, ok := eventStatus[ev.Action]
+	if !ok {
+		return nil, false, fmt.Errorf("unknown event action %q: %w", ev.Action, domain.ErrInvalidInput)
+	}
+	pr, err = s.prService.Get(ctx, ev.PRID)
+	if err != nil {
+		return nil, false, err
+	}
+	if pr.Status == target {
+		return pr, false, nil
+	}
+
+	switch ev.Action {
+	case models.PREventReady:
+		pr, err = s.prService.MarkReady(ctx, ev.PRID, actor)
+	case models.PREventMerged:
+		pr, err = s.prService.RecordMerge(ctx, ev.PRID, actor, "merged on "+ev.Platform)
+	case models.PREventClosed:
+		pr, err = s.prService.Close(ctx, ev.PRID, actor)
+	case models.PREventReopened:
+		pr, err = s.prService.Reopen(ctx, ev.PRID, actor)
+	}
+	return pr, err == nil, err
+}
+
+// resolveUser находит пользователя по логину на платформе: сначала по связке из
+// /users/linkIdentity, затем логин как user_id.
+func (s *WebhookService) resolveUser(ctx context.Context, platform, login string) (*models.User, error) {
+	u, err := s.userRepo.FindByIdentity(ctx, platform, login)
+	if !errors.Is(err, domain.ErrNotFound) {
+		return u, err
+	}
+	u, err = s.userRepo.GetByID(ctx, login)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("no user for %s login %s: %w", platform, login, domain.ErrNotFound)
+	}
+	return u, err
+}
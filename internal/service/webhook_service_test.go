@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+	"github.com/humooo/avito-backend-trainee-2025/internal/repo/repotest"
+)
+
+func TestApplyWebhookEvents(t *testing.T) {
+	r := newTestRepos()
+	ctx := context.Background()
+	repotest.SeedTeam(t, r, "backend", []string{"u1", "u2", "u3"})
+	if err := r.Teams.UpsertSettings(ctx, &models.TeamSettings{TeamName: "backend", ReviewerCount: 2, MinApprovals: 1}); err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+
+	selector, _ := NewReviewerSelector(StrategyLeastLoaded, nil)
+	prs := NewPRService(r.PRs, r.Users, r.Teams, selector, DefaultTeamSettings(), nil)
+	svc := NewWebhookService(prs, r.Users)
+	if err := svc.LinkIdentity(ctx, models.PlatformGitHub, "alice-dev", "u1"); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if err := svc.LinkIdentity(ctx, "bitbucket", "alice-dev", "u1"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("unknown platform: want ErrInvalidInput, got %v", err)
+	}
+
+	apply := func(ev models.PREvent, wantApplied bool, wantStatus string) *models.PullRequest {
+		t.Helper()
+		pr, applied, err := svc.Apply(ctx, ev)
+		if err != nil {
+			t.Fatalf("%s %s: %v", ev.Action, ev.PRID, err)
+		}
+		if applied != wantApplied || pr.Status != wantStatus {
+			t.Fatalf("%s %s: applied %v, status %s; want %v, %s", ev.Action, ev.PRID, applied, pr.Status, wantApplied, wantStatus)
+		}
+		return pr
+	}
+	gh := func(action string) models.PREvent {
+		return models.PREvent{Platform: models.PlatformGitHub, Action: action, PRID: "org/repo#1", Title: "Search", AuthorLogin: "alice-dev", SenderLogin: "alice-dev"}
+	}
+
+	pr := apply(gh(models.PREventOpened), true, models.StatusOpen)
+	if pr.AuthorID != "u1" || len(pr.Reviewers) != 2 {
+		t.Fatalf("created: %+v", pr)
+	}
+	history, _ := r.PRs.ListHistory(ctx, "org/repo#1")
+	if len(history) == 0 || history[0].Actor != "github:alice-dev" {
+		t.Fatalf("history actor: %+v", history)
+	}
+	// Повторная доставка
+	apply(gh(models.PREventOpened), false, models.StatusOpen)
+	apply(gh(models.PREventReopened), false, models.StatusOpen)
+
+	// Merge на платформе уже случился — одобрения не проверяются
+	if _, err := prs.Merge(ctx, "org/repo#1", "tester"); !errors.Is(err, domain.ErrNotApproved) {
+		t.Fatalf("manual merge: want ErrNotApproved, got %v", err)
+	}
+	merged := apply(gh(models.PREventMerged), true, models.StatusMerged)
+	if merged.MergedAt == nil {
+		t.Fatal("merged_at not set")
+	}
+	apply(gh(models.PREventMerged), false, models.StatusMerged)
+
+	// Логин без связки ищется как user_id
+	gl := models.PREvent{Platform: models.PlatformGitLab, Action: models.PREventOpened, PRID: "group/repo!7", Title: "Draft", AuthorLogin: "u2", Draft: true}
+	if pr := apply(gl, true, models.StatusDraft); pr.AuthorID != "u2" || len(pr.Reviewers) != 0 {
+		t.Fatalf("draft: %+v", pr)
+	}
+	gl.Action = models.PREventReady
+	if pr := apply(gl, true, models.StatusOpen); len(pr.Reviewers) != 2 {
+		t.Fatalf("ready: %+v", pr)
+	}
+	gl.Action = models.PREventClosed
+	apply(gl, true, models.StatusClosed)
+	gl.Action = models.PREventReopened
+	apply(gl, true, models.StatusOpen)
+
+	unknown := gh(models.PREventOpened)
+	unknown.PRID, unknown.AuthorLogin = "org/repo#2", "nobody"
+	if _, _, err := svc.Apply(ctx, unknown); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown author: want ErrNotFound, got %v", err)
+	}
+	missing := gh(models.PREventClosed)
+	missing.PRID = "org/repo#404"
+	if _, _, err := svc.Apply(ctx, missing); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown PR: want ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+// githubEvent — нужная нам часть события pull_request.
+type githubEvent struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+// GitHub разбирает вебхук GitHub, подписанный в X-Hub-Signature-256.
+// ID PR — "owner/repo#номер".
+func GitHub(header http.Header, body []byte, secret string) (*models.PREvent, error) {
+	if err := verifySignature(header.Get("X-Hub-Signature-256"), body, secret); err != nil {
+		return nil, err
+	}
+	if header.Get("X-GitHub-Event") != "pull_request" {
+		return nil, nil
+	}
+
+	var e githubEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("github payload: %v: %w", err, domain.ErrInvalidInput)
+	}
+	if e.Repository.FullName == "" || e.PullRequest.Number == 0 {
+		return nil, fmt.Errorf("github payload without repository or pull request: %w", domain.ErrInvalidInput)
+	}
+
+	ev := &models.PREvent{
+		Platform:    models.PlatformGitHub,
+		PRID:        fmt.Sprintf("%s#%d", e.Repository.FullName, e.PullRequest.Number),
+		Title:       e.PullRequest.Title,
+		AuthorLogin: e.PullRequest.User.Login,
+		Draft:       e.PullRequest.Draft,
+		SenderLogin: e.Sender.Login,
+	}
+	switch e.Action {
+	case "opened":
+		ev.Action = models.PREventOpened
+	case "ready_for_review":
+		ev.Action = models.PREventReady
+	case "reopened":
+		ev.Action = models.PREventReopened
+	case "closed":
+		ev.Action = models.PREventClosed
+		if e.PullRequest.Merged {
+			ev.Action = models.PREventMerged
+		}
+	default:
+		return nil, nil
+	}
+	return ev, nil
+}
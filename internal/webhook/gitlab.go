@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+// gitlabEvent — нужная нам часть события Merge Request Hook.
+type gitlabEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID    int    `json:"iid"`
+		Title  string `json:"title"`
+		Action string `json:"action"`
+		Draft  bool   `json:"draft"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+// GitLab разбирает вебхук GitLab. GitLab не подписывает тело, а передаёт секрет
+// в X-Gitlab-Token — он и сверяется. ID PR — "group/project!iid".
+//
+// В событии нет логина автора MR, только того, кто выполнил действие; для open
+// это и есть автор.
+func GitLab(header http.Header, body []byte, secret string) (*models.PREvent, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("webhook secret is not configured: %w", domain.ErrUnauthorized)
+	}
+	if subtle.ConstantTimeCompare([]byte(header.Get("X-Gitlab-Token")), []byte(secret)) != 1 {
+		return nil, fmt.Errorf("invalid webhook token: %w", domain.ErrUnauthorized)
+	}
+	if header.Get("X-Gitlab-Event") != "Merge Request Hook" {
+		return nil, nil
+	}
+
+	var e gitlabEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("gitlab payload: %v: %w", err, domain.ErrInvalidInput)
+	}
+	if e.ObjectKind != "merge_request" {
+		return nil, nil
+	}
+	if e.Project.PathWithNamespace == "" || e.ObjectAttributes.IID == 0 {
+		return nil, fmt.Errorf("gitlab payload without project or merge request: %w", domain.ErrInvalidInput)
+	}
+
+	ev := &models.PREvent{
+		Platform:    models.PlatformGitLab,
+		PRID:        fmt.Sprintf("%s!%d", e.Project.PathWithNamespace, e.ObjectAttributes.IID),
+		Title:       e.ObjectAttributes.Title,
+		AuthorLogin: e.User.Username,
+		Draft:       e.ObjectAttributes.Draft,
+		SenderLogin: e.User.Username,
+	}
+	switch e.ObjectAttributes.Action {
+	case "open":
+		ev.Action = models.PREventOpened
+	case "reopen":
+		ev.Action = models.PREventReopened
+	case "close":
+		ev.Action = models.PREventClosed
+	case "merge":
+		ev.Action = models.PREventMerged
+	case "update":
+		// Из обновлений интересно только снятие черновика
+		if d := e.Changes.Draft; d == nil || !d.Previous || d.Current {
+			return nil, nil
+		}
+		ev.Action = models.PREventReady
+	default:
+		return nil, nil
+	}
+	return ev, nil
+}
@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+// Заголовки исходящих вебхуков. Подпись — как у GitHub: "sha256=" и HMAC-SHA256
+// тела с секретом подписки в hex.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature-256"
+)
+
+// Deliver отправляет подписанную доставку POST-запросом на URL подписки. Ответ не 2xx —
+// ошибка; statusCode 0 — ответа не было.
+func Deliver(ctx context.Context, client *http.Client, d models.WebhookDelivery) (statusCode int, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "review-service-webhooks")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(d.Payload, d.Secret))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Начало тела — в журнал, чтобы было видно, почему получатель отказал
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %s: %s", resp.Status, bytes.TrimSpace(snippet))
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,38 @@
+// Package webhook разбирает входящие вебхуки GitHub и GitLab о pull/merge request
+// и проверяет их подлинность. Результат — models.PREvent; что с ним делать,
+// решает service.WebhookService.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+// Parser разбирает тело вебхука платформы. Событие, которое не меняет PR
+// (ping, другие типы и действия), — nil без ошибки.
+type Parser func(header http.Header, body []byte, secret string) (*models.PREvent, error)
+
+// Sign — подпись тела в формате заголовка X-Hub-Signature-256.
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifySignature сверяет подпись "sha256=<hex>" с HMAC-SHA256 тела за постоянное время.
+func verifySignature(signature string, body []byte, secret string) error {
+	if secret == "" {
+		return fmt.Errorf("webhook secret is not configured: %w", domain.ErrUnauthorized)
+	}
+	if !strings.HasPrefix(signature, "sha256=") || !hmac.Equal([]byte(signature), []byte(Sign(body, secret))) {
+		return fmt.Errorf("invalid webhook signature: %w", domain.ErrUnauthorized)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/humooo/avito-backend-trainee-2025/internal/domain"
+	"github.com/humooo/avito-backend-trainee-2025/internal/models"
+)
+
+const testSecret = "s3cret"
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+	return body
+}
+
+func githubHeader(event string, body []byte) http.Header {
+	h := http.Header{}
+	h.Set("X-GitHub-Event", event)
+	h.Set("X-Hub-Signature-256", Sign(body, testSecret))
+	return h
+}
+
+func gitlabHeader(event string) http.Header {
+	h := http.Header{}
+	h.Set("X-Gitlab-Event", event)
+	h.Set("X-Gitlab-Token", testSecret)
+	return h
+}
+
+func TestGitHub(t *testing.T) {
+	ghEvent := func(action, sender string, draft bool) *models.PREvent {
+		return &models.PREvent{
+			Platform:    models.PlatformGitHub,
+			Action:      action,
+			PRID:        "avito-tech/review-service#42",
+			Title:       "Add search by author",
+			AuthorLogin: "alice-dev",
+			Draft:       draft,
+			SenderLogin: sender,
+		}
+	}
+	for _, tc := range []struct {
+		fixture string
+		event   string
+		want    *models.PREvent
+	}{
+		{"github_pull_request_opened.json", "pull_request", ghEvent(models.PREventOpened, "alice-dev", false)},
+		{"github_pull_request_opened_draft.json", "pull_request", ghEvent(models.PREventOpened, "alice-dev", true)},
+		{"github_pull_request_ready_for_review.json", "pull_request", ghEvent(models.PREventReady, "alice-dev", false)},
+		{"github_pull_request_closed_merged.json", "pull_request", ghEvent(models.PREventMerged, "bob-lead", false)},
+		{"github_pull_request_closed.json", "pull_request", ghEvent(models.PREventClosed, "alice-dev", false)},
+		{"github_pull_request_labeled.json", "pull_request", nil},
+		{"github_ping.json", "ping", nil},
+	} {
+		t.Run(tc.fixture, func(t *testing.T) {
+			body := fixture(t, tc.fixture)
+			got, err := GitHub(githubHeader(tc.event, body), body, testSecret)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGitHubSignature(t *testing.T) {
+	body := fixture(t, "github_pull_request_opened.json")
+	for name, tc := range map[string]struct {
+		header http.Header
+		secret string
+	}{
+		"missing":        {http.Header{"X-Github-Event": {"pull_request"}}, testSecret},
+		"wrong secret":   {githubHeader("pull_request", body), "other"},
+		"not configured": {githubHeader("pull_request", body), ""},
+		"tampered body":  {githubHeader("pull_request", append([]byte(" "), body...)), testSecret},
+	} {
+		if _, err := GitHub(tc.header, body, tc.secret); !errors.Is(err, domain.ErrUnauthorized) {
+			t.Errorf("%s: want ErrUnauthorized, got %v", name, err)
+		}
+	}
+
+	garbage := []byte("{not json")
+	if _, err := GitHub(githubHeader("pull_request", garbage), garbage, testSecret); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("malformed payload: want ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestGitLab(t *testing.T) {
+	glEvent := func(action, login string) *models.PREvent {
+		return &models.PREvent{
+			Platform:    models.PlatformGitLab,
+			Action:      action,
+			PRID:        "backend/review-service!7",
+			Title:       "Add search by author",
+			AuthorLogin: login,
+			SenderLogin: login,
+		}
+	}
+	for _, tc := range []struct {
+		fixture string
+		event   string
+		want    *models.PREvent
+	}{
+		{"gitlab_merge_request_open.json", "Merge Request Hook", glEvent(models.PREventOpened, "alice-dev")},
+		{"gitlab_merge_request_update_ready.json", "Merge Request Hook", glEvent(models.PREventReady, "alice-dev")},
+		{"gitlab_merge_request_merge.json", "Merge Request Hook", glEvent(models.PREventMerged, "bob-lead")},
+		{"gitlab_merge_request_update_description.json", "Merge Request Hook", nil},
+		{"gitlab_merge_request_open.json", "Push Hook", nil},
+	} {
+		t.Run(tc.fixture+"/"+tc.event, func(t *testing.T) {
+			got, err := GitLab(gitlabHeader(tc.event), fixture(t, tc.fixture), testSecret)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+
+	body := fixture(t, "gitlab_merge_request_open.json")
+	if _, err := GitLab(gitlabHeader("Merge Request Hook"), body, "other"); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("wrong token: want ErrUnauthorized, got %v", err)
+	}
+	if _, err := GitLab(gitlabHeader("Merge Request Hook"), body, ""); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("not configured: want ErrUnauthorized, got %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS reviewer_assignments (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL,
+    new_reviewer_id TEXT,
+    action TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS reviewer_assignments_pr_idx ON reviewer_assignments (pr_id, id);
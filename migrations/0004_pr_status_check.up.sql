@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_status_check CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED'));
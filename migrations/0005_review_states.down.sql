@@ -0,0 +1,3 @@
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS state_updated_at,
+    DROP COLUMN IF EXISTS state;
@@ -0,0 +1,4 @@
+ALTER TABLE pr_reviewers
+    ADD COLUMN IF NOT EXISTS state TEXT NOT NULL DEFAULT 'PENDING'
+        CHECK (state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    ADD COLUMN IF NOT EXISTS state_updated_at TIMESTAMP;
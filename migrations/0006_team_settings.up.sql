@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_name TEXT PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    reviewer_count INTEGER NOT NULL CHECK (reviewer_count >= 0),
+    min_approvals INTEGER NOT NULL CHECK (min_approvals >= 0),
+    lead_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    require_lead BOOLEAN NOT NULL DEFAULT FALSE,
+    strategy TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,2 @@
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS is_fallback;
+DROP TABLE IF EXISTS team_fallbacks;
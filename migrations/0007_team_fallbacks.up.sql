@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_name TEXT NOT NULL REFERENCES teams(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    fallback_team TEXT NOT NULL REFERENCES teams(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (team_name, fallback_team)
+);
+
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS is_fallback BOOLEAN NOT NULL DEFAULT FALSE;
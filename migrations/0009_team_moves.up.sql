@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS team_moves (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_team TEXT,
+    to_team TEXT NOT NULL,
+    handover BOOLEAN NOT NULL,
+    actor TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS team_moves_user_idx ON team_moves (user_id, id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS absences (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    -- Ревью уже переназначены фоновой задачей
+    handled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT absences_period_check CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS absences_user_idx ON absences (user_id, ends_at);
+CREATE INDEX IF NOT EXISTS absences_pending_idx ON absences (starts_at) WHERE NOT handled;
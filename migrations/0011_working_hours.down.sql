@@ -0,0 +1,6 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_working_hours_check;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS work_end,
+    DROP COLUMN IF EXISTS work_start,
+    DROP COLUMN IF EXISTS time_zone;
@@ -0,0 +1,14 @@
+-- Рабочее окно в часовом поясе пользователя, минуты от полуночи.
+-- work_end < work_start — окно через полночь.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS time_zone TEXT,
+    ADD COLUMN IF NOT EXISTS work_start SMALLINT,
+    ADD COLUMN IF NOT EXISTS work_end SMALLINT;
+
+ALTER TABLE users ADD CONSTRAINT users_working_hours_check CHECK (
+    (time_zone IS NULL AND work_start IS NULL AND work_end IS NULL)
+    OR (time_zone IS NOT NULL
+        AND work_start BETWEEN 0 AND 1439
+        AND work_end BETWEEN 0 AND 1439
+        AND work_start <> work_end)
+);
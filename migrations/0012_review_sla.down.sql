@@ -0,0 +1,9 @@
+ALTER TABLE team_settings
+    DROP COLUMN IF EXISTS sla_action,
+    DROP COLUMN IF EXISTS review_sla_minutes;
+
+DROP INDEX IF EXISTS pr_reviewers_pending_idx;
+
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS escalated_at,
+    DROP COLUMN IF EXISTS assigned_at;
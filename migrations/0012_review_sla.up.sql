@@ -0,0 +1,20 @@
+ALTER TABLE pr_reviewers
+    ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- SLA-эскалация по этому назначению уже выполнена
+    ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMPTZ;
+
+-- Для уже существующих назначений берём время из истории
+UPDATE pr_reviewers rev SET assigned_at = h.created_at
+FROM (
+    SELECT pr_id, COALESCE(new_reviewer_id, reviewer_id) AS reviewer_id, MAX(created_at) AS created_at
+    FROM reviewer_assignments
+    WHERE action IN ('ASSIGNED', 'REASSIGNED')
+    GROUP BY 1, 2
+) h
+WHERE h.pr_id = rev.pr_id AND h.reviewer_id = rev.reviewer_id;
+
+CREATE INDEX IF NOT EXISTS pr_reviewers_pending_idx ON pr_reviewers (assigned_at) WHERE state = 'PENDING';
+
+ALTER TABLE team_settings
+    ADD COLUMN IF NOT EXISTS review_sla_minutes INTEGER NOT NULL DEFAULT 0 CHECK (review_sla_minutes >= 0),
+    ADD COLUMN IF NOT EXISTS sla_action TEXT NOT NULL DEFAULT '' CHECK (sla_action IN ('', 'reassign', 'add_reviewer'));
@@ -0,0 +1,8 @@
+-- Список PR: сортировка (created_at, id) от новых к старым и фильтры по статусу,
+-- автору, ревьюеру и команде
+CREATE INDEX IF NOT EXISTS pull_requests_created_idx ON pull_requests (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS pull_requests_status_created_idx ON pull_requests (status, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS pull_requests_author_created_idx ON pull_requests (author_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS pull_requests_merged_idx ON pull_requests (merged_at) WHERE merged_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS pr_reviewers_reviewer_idx ON pr_reviewers (reviewer_id, pr_id);
+CREATE INDEX IF NOT EXISTS users_team_idx ON users (team_name);
@@ -0,0 +1,9 @@
+-- Логины пользователей на GitHub/GitLab для вебхуков
+CREATE TABLE IF NOT EXISTS user_identities (
+    platform TEXT NOT NULL CHECK (platform IN ('github', 'gitlab')),
+    login TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (platform, login)
+);
+
+CREATE INDEX IF NOT EXISTS user_identities_user_idx ON user_identities (user_id);
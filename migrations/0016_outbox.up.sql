@@ -0,0 +1,16 @@
+-- Доменные события, записанные в одной транзакции с изменением. Внешних ключей
+-- нет: событие переживает PR и пользователя, о которых оно.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    pr_id TEXT,
+    user_id TEXT,
+    reviewer_id TEXT,
+    new_reviewer_id TEXT,
+    actor TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (id) WHERE published_at IS NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_preferences;
+
+ALTER TABLE team_settings DROP COLUMN IF EXISTS chat_channel;
@@ -0,0 +1,22 @@
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS chat_channel TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    mode TEXT NOT NULL CHECK (mode IN ('instant', 'digest', 'mute')),
+    channel TEXT NOT NULL DEFAULT '',
+    -- Когда ушёл последний дайджест; период считается от него
+    last_digest_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Очередь сообщений о назначениях; отправленные удаляются.
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL,
+    text TEXT NOT NULL,
+    digest BOOLEAN NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_idx ON notifications (user_id, id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS pull_requests_open_idx;
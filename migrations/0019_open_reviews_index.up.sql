@@ -0,0 +1,3 @@
+-- Нагрузка ревьюеров и массовое переназначение соединяют pr_reviewers только с
+-- OPEN PR: статус проверяется по индексу, без чтения строк pull_requests
+CREATE INDEX IF NOT EXISTS pull_requests_open_idx ON pull_requests (id) WHERE status = 'OPEN';
@@ -0,0 +1,14 @@
+-- Обратно в пояс сессии, как писал NOW() до перехода на TIMESTAMPTZ
+ALTER TABLE schema_migrations
+    ALTER COLUMN applied_at TYPE TIMESTAMP USING applied_at::timestamp;
+ALTER TABLE absences
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at::timestamp;
+ALTER TABLE team_moves
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at::timestamp;
+ALTER TABLE pr_reviewers
+    ALTER COLUMN state_updated_at TYPE TIMESTAMP USING state_updated_at::timestamp;
+ALTER TABLE reviewer_assignments
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at::timestamp;
+ALTER TABLE pull_requests
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at::timestamp,
+    ALTER COLUMN merged_at TYPE TIMESTAMP USING merged_at::timestamp;
@@ -0,0 +1,16 @@
+-- Все отметки времени хранятся с зоной. Старые значения записаны NOW() в поясе
+-- сессии (TimeZone), поэтому приводятся в нём же, а не как UTC: миграцию нужно
+-- накатывать с тем же TimeZone, с которым работал сервис.
+ALTER TABLE pull_requests
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at::timestamptz,
+    ALTER COLUMN merged_at TYPE TIMESTAMPTZ USING merged_at::timestamptz;
+ALTER TABLE reviewer_assignments
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at::timestamptz;
+ALTER TABLE pr_reviewers
+    ALTER COLUMN state_updated_at TYPE TIMESTAMPTZ USING state_updated_at::timestamptz;
+ALTER TABLE team_moves
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at::timestamptz;
+ALTER TABLE absences
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at::timestamptz;
+ALTER TABLE schema_migrations
+    ALTER COLUMN applied_at TYPE TIMESTAMPTZ USING applied_at::timestamptz;
@@ -0,0 +1,4 @@
+-- Доставки создаются из событий outbox; relay может повторить событие, поэтому
+-- одна подписка получает его не больше одного раза
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS event_id BIGINT;
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_event_idx ON webhook_deliveries (subscription_id, event_id);
@@ -0,0 +1,6 @@
+-- События outbox, по которым уже поставлены уведомления: relay может повторить
+-- событие, а отправленные уведомления из очереди удаляются
+CREATE TABLE IF NOT EXISTS notification_events (
+    event_id BIGINT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS outbox_relays;
+DROP INDEX IF EXISTS outbox_unexported_idx;
+ALTER TABLE outbox DROP COLUMN IF EXISTS exported_at;
@@ -0,0 +1,14 @@
+-- Внешние sinks (OUTBOX_SINKS) отмечают события отдельно от внутренних получателей
+-- (вебхуки, уведомления): сбой внешнего не задерживает внутренних. Уже
+-- опубликованное считается выгруженным, чтобы не отправить историю заново.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS exported_at TIMESTAMPTZ;
+UPDATE outbox SET exported_at = published_at WHERE exported_at IS NULL AND published_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS outbox_unexported_idx ON outbox (id) WHERE exported_at IS NULL;
+
+-- Аренда relay по получателю: публикует один экземпляр за раз, транзакция на время
+-- вызова sinks не держится. Истёкшую аренду берёт другой экземпляр.
+CREATE TABLE IF NOT EXISTS outbox_relays (
+    consumer TEXT PRIMARY KEY,
+    holder TEXT NOT NULL,
+    leased_until TIMESTAMPTZ NOT NULL
+);